@@ -8,6 +8,10 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/auth/tokenfetch"
+	"github.com/yaroslav-koval/hange/domain/auth/tokenverify"
 	"golang.org/x/term"
 )
 
@@ -55,6 +59,90 @@ hange auth "token-value"`,
 	},
 }
 
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where the token comes from and verify it",
+	Long: `Show the active profile, where its token comes from and a masked preview of the token.
+The token is verified with a lightweight call listing models.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app, err := appFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		p, err := app.GetProfile()
+		if err != nil {
+			return err
+		}
+
+		au, err := app.GetAuth()
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+
+		if _, err = fmt.Fprintf(w, "Profile: %s\nSource:  %s\n", p.Name, au.TokenSource()); err != nil {
+			return err
+		}
+
+		token, err := au.GetToken()
+		if errors.Is(err, tokenfetch.ErrTokenNotSet) {
+			_, _ = fmt.Fprintln(w, "Token:   not set")
+			return err
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintf(w, "Token:   %s\n", auth.MaskToken(token)); err != nil {
+			return err
+		}
+
+		err = au.VerifyToken(cmd.Context())
+		if errors.Is(err, dryrun.ErrDryRun) {
+			_, err = fmt.Fprintln(w, "Status:  not verified (dry run)")
+			return err
+		}
+
+		if err != nil {
+			status := "unverified"
+			if errors.Is(err, tokenverify.ErrInvalidToken) {
+				status = "invalid"
+			}
+
+			_, _ = fmt.Fprintf(w, "Status:  %s\n", status)
+
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, "Status:  valid")
+
+		return err
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored token of the active profile",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app, err := appFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		au, err := app.GetAuth()
+		if err != nil {
+			return err
+		}
+
+		return au.DeleteToken()
+	},
+}
+
 func readTokenFromStdin() (string, error) {
 	if term.IsTerminal(int(os.Stdin.Fd())) {
 		return "", fmt.Errorf("no token provided; pass it as argument or via stdin")
@@ -71,5 +159,6 @@ func readTokenFromStdin() (string, error) {
 }
 
 func init() {
+	authCmd.AddCommand(authStatusCmd, authLogoutCmd)
 	rootCmd.AddCommand(authCmd)
 }
@@ -1,14 +1,21 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
 	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/auth/tokenfetch"
+	"github.com/yaroslav-koval/hange/domain/auth/tokenverify"
+	"github.com/yaroslav-koval/hange/domain/profile"
 	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
 	auth_mock "github.com/yaroslav-koval/hange/mocks/auth"
 )
@@ -109,3 +116,85 @@ func setStdin(t *testing.T, content string) func() {
 		_ = r.Close()
 	}
 }
+
+func TestAuthStatusCommand(t *testing.T) {
+	networkErr := errors.New("no such host")
+
+	tests := []struct {
+		name      string
+		fetchErr  error
+		verifyErr error
+		wantOut   string
+		wantErr   error
+	}{
+		{
+			name:    "valid token",
+			wantOut: "Profile: work\nSource:  config profiles.work.token (file)\nToken:   sk-p…x9Qz\nStatus:  valid\n",
+		},
+		{
+			name:      "invalid token",
+			verifyErr: tokenverify.ErrInvalidToken,
+			wantOut:   "Profile: work\nSource:  config profiles.work.token (file)\nToken:   sk-p…x9Qz\nStatus:  invalid\n",
+			wantErr:   tokenverify.ErrInvalidToken,
+		},
+		{
+			name:      "verification failed",
+			verifyErr: networkErr,
+			wantOut:   "Profile: work\nSource:  config profiles.work.token (file)\nToken:   sk-p…x9Qz\nStatus:  unverified\n",
+			wantErr:   networkErr,
+		},
+		{
+			name:      "dry run",
+			verifyErr: fmt.Errorf("token is not verified: %w", dryrun.ErrDryRun),
+			wantOut: "Profile: work\nSource:  config profiles.work.token (file)\nToken:   sk-p…x9Qz\n" +
+				"Status:  not verified (dry run)\n",
+		},
+		{
+			name:     "token is not set",
+			fetchErr: tokenfetch.ErrTokenNotSet,
+			wantOut:  "Profile: work\nSource:  config profiles.work.token (file)\nToken:   not set\n",
+			wantErr:  tokenfetch.ErrTokenNotSet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := auth_mock.NewMockAuth(t)
+			mockAuth.EXPECT().TokenSource().Return("config profiles.work.token (file)")
+
+			if tt.fetchErr != nil {
+				mockAuth.EXPECT().GetToken().Return("", tt.fetchErr)
+			} else {
+				mockAuth.EXPECT().GetToken().Return("sk-proj-abcdefghx9Qz", nil)
+				mockAuth.EXPECT().VerifyToken(mock.Anything).Return(tt.verifyErr)
+			}
+
+			app := appbuilder_mock.NewMockAppBuilder(t)
+			app.EXPECT().GetProfile().Return(profile.Profile{Name: "work"}, nil)
+			app.EXPECT().GetAuth().Return(mockAuth, nil)
+
+			cmd := &cobra.Command{RunE: authStatusCmd.RunE}
+			cmd.SetContext(appToContext(context.Background(), app))
+
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			err := cmd.RunE(cmd, nil)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
+
+func TestAuthLogoutCommand(t *testing.T) {
+	mockAuth := auth_mock.NewMockAuth(t)
+	mockAuth.EXPECT().DeleteToken().Return(nil)
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetAuth().Return(mockAuth, nil)
+
+	cmd := &cobra.Command{RunE: authLogoutCmd.RunE}
+	cmd.SetContext(appToContext(context.Background(), app))
+
+	require.NoError(t, cmd.RunE(cmd, nil))
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+)
+
+const flagKeyOlderThan = "older-than"
+
+// defaultCleanupAge keeps resources of runs which may still be in progress
+const defaultCleanupAge = time.Hour
+
+var errCleanupFailed = errors.New("failed to delete remote resources")
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete vector stores and files left by interrupted runs",
+	Long: `Delete vector stores and files left on the provider's side by runs which were killed before their cleanup.
+Vector stores are found by the "hange_" name prefix, files by the purpose and expiration set by hange
+and by membership in those vector stores. Only resources older than --older-than are deleted,
+so runs in progress are not affected. Resources expire by themselves too: files in an hour, vector stores in a day.`,
+	Example: `hange cleanup --dry-run
+hange cleanup --older-than 0s`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, err := cmd.Flags().GetDuration(flagKeyOlderThan)
+		if err != nil {
+			return err
+		}
+
+		if olderThan < 0 {
+			return fmt.Errorf("--%s can't be negative", flagKeyOlderThan)
+		}
+
+		dryRun, err := cmd.Flags().GetBool(flagKeyDryRun)
+		if err != nil {
+			return err
+		}
+
+		app, err := appFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		janitor, err := app.GetJanitor()
+		if err != nil {
+			return err
+		}
+
+		resources, err := janitor.FindOrphans(cmd.Context(), olderThan)
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+
+		if len(resources) == 0 {
+			_, err = fmt.Fprintln(w, "Nothing to clean up")
+			return err
+		}
+
+		if dryRun {
+			results := make([]entity.CleanupResult, len(resources))
+			for i, r := range resources {
+				results[i] = entity.CleanupResult{Resource: r}
+			}
+
+			return printCleanupReport(w, results, "to delete")
+		}
+
+		results := janitor.Delete(cmd.Context(), resources)
+
+		if err = printCleanupReport(w, results, "deleted"); err != nil {
+			return err
+		}
+
+		failed := 0
+
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+			}
+		}
+
+		_, err = fmt.Fprintf(w, "Deleted %d of %d resource(s)\n", len(results)-failed, len(results))
+		if err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%w: %d resource(s)", errCleanupFailed, failed)
+		}
+
+		return nil
+	},
+}
+
+// printCleanupReport prints a line per resource, the status of a failed one is its error.
+func printCleanupReport(w io.Writer, results []entity.CleanupResult, okStatus string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, r := range results {
+		status := okStatus
+		if r.Err != nil {
+			status = "failed: " + r.Err.Error()
+		}
+
+		_, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Resource.Kind, r.Resource.ID, r.Resource.Name,
+			r.Resource.CreatedAt.Local().Format(time.DateTime), status)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+func init() {
+	cleanupCmd.Flags().Bool(flagKeyDryRun, false, "print resources without deleting them")
+	cleanupCmd.Flags().Duration(flagKeyOlderThan, defaultCleanupAge, "delete only resources created earlier than this")
+
+	rootCmd.AddCommand(cleanupCmd)
+}
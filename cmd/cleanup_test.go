@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	janitor_mock "github.com/yaroslav-koval/hange/mocks/janitor"
+)
+
+var cleanupResources = []entity.RemoteResource{
+	{Kind: entity.RemoteVectorStore, ID: "vs_1", Name: "hange_1799996400", CreatedAt: time.Unix(1799996400, 0)},
+	{Kind: entity.RemoteFile, ID: "file_1", Name: "main.go", CreatedAt: time.Unix(1799996400, 0)},
+}
+
+func TestCleanupDryRun(t *testing.T) {
+	j := janitor_mock.NewMockJanitor(t)
+	j.EXPECT().FindOrphans(mock.Anything, time.Hour).Return(cleanupResources, nil)
+
+	cmd, out := newCleanupTestCommand(t, j)
+	require.NoError(t, cmd.Flags().Set(flagKeyDryRun, "true"))
+
+	require.NoError(t, cmd.RunE(cmd, nil))
+	require.Contains(t, out.String(), "vector store  vs_1    hange_1799996400")
+	require.Contains(t, out.String(), "to delete")
+}
+
+func TestCleanupReportsFailures(t *testing.T) {
+	j := janitor_mock.NewMockJanitor(t)
+	j.EXPECT().FindOrphans(mock.Anything, time.Minute).Return(cleanupResources, nil)
+	j.EXPECT().Delete(mock.Anything, cleanupResources).Return([]entity.CleanupResult{
+		{Resource: cleanupResources[0]},
+		{Resource: cleanupResources[1], Err: errors.New("bad request")},
+	})
+
+	cmd, out := newCleanupTestCommand(t, j)
+	require.NoError(t, cmd.Flags().Set(flagKeyOlderThan, "1m"))
+
+	require.ErrorIs(t, cmd.RunE(cmd, nil), errCleanupFailed)
+	require.Contains(t, out.String(), "failed: bad request")
+	require.Contains(t, out.String(), "Deleted 1 of 2 resource(s)")
+}
+
+func TestCleanupNothingFound(t *testing.T) {
+	j := janitor_mock.NewMockJanitor(t)
+	j.EXPECT().FindOrphans(mock.Anything, time.Hour).Return(nil, nil)
+
+	cmd, out := newCleanupTestCommand(t, j)
+
+	require.NoError(t, cmd.RunE(cmd, nil))
+	require.Equal(t, "Nothing to clean up\n", out.String())
+}
+
+func newCleanupTestCommand(t *testing.T, j *janitor_mock.MockJanitor) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetJanitor().Return(j, nil)
+
+	cmd := &cobra.Command{RunE: cleanupCmd.RunE}
+	cmd.SetContext(appToContext(context.Background(), app))
+	cmd.Flags().Bool(flagKeyDryRun, false, "")
+	cmd.Flags().Duration(flagKeyOlderThan, defaultCleanupAge, "")
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	return cmd, out
+}
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
 )
 
 var commitCmd = &cobra.Command{
-	Use:     "commit [input]",
-	Short:   "Makes a commit to current git branch",
-	Long:    `Takes a changelist of git, generates a commit message and commits to a current branch.`,
+	Use:   "commit [input]",
+	Short: "Makes a commit to current git branch",
+	Long: `Takes a changelist of git, generates a commit message and commits to a current branch.
+The message falls back to heuristics like in commit-msg, --offline always uses them.`,
 	Example: `hange commit "Task description"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		app, err := appFromContext(cmd.Context())
@@ -16,7 +20,9 @@ var commitCmd = &cobra.Command{
 		}
 
 		message, err := generateCommitMessage(cmd.Context(), app, args)
-		if err != nil {
+		if errors.Is(err, dryrun.ErrDryRun) {
+			return nil
+		} else if err != nil {
 			return err
 		}
 
@@ -25,10 +31,12 @@ var commitCmd = &cobra.Command{
 			return err
 		}
 
-		return git.Commit(cmd.Context(), message)
+		return git.Commit(cmd.Context(), message.Text)
 	},
 }
 
 func init() {
+	addOfflineFlag(commitCmd)
+
 	rootCmd.AddCommand(commitCmd)
 }
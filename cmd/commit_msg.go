@@ -2,46 +2,67 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
 	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
 	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/git"
 )
 
 var commitMsgCmd = &cobra.Command{
-	Use:     "commit-msg [input]",
-	Short:   "Generate a git commit message",
-	Long:    `Takes a changelist of git and outputs a short commit message.`,
-	Example: `hange commit-msg "Task description"`,
+	Use:   "commit-msg [input]",
+	Short: "Generate a git commit message",
+	Long: `Takes a changelist of git and outputs a short commit message.
+If the provider fails or no token is set, the message is derived from the staged patch by heuristics
+with a warning. --offline always uses the heuristics.`,
+	Example: `hange commit-msg "Task description"
+hange commit-msg --offline`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
+
 		app, err := appFromContext(cmd.Context())
 		if err != nil {
 			return err
 		}
 
-		message, err := generateCommitMessage(cmd.Context(), app, args)
+		rw, err := resultWriterFromFlags(cmd)
 		if err != nil {
 			return err
 		}
 
-		_, err = fmt.Fprintln(cmd.OutOrStdout(), message)
-		if err != nil {
+		message, err := generateCommitMessage(cmd.Context(), app, args)
+		if errors.Is(err, dryrun.ErrDryRun) {
+			return nil
+		} else if err != nil {
 			return err
 		}
 
-		return nil
+		return rw.write(entities.NewResult(cmd.Name(), message, time.Since(start)))
 	},
 }
 
 func init() {
+	addOutputFlags(commitMsgCmd)
+	addOfflineFlag(commitMsgCmd)
+
 	rootCmd.AddCommand(commitMsgCmd)
 }
 
-func generateCommitMessage(ctx context.Context, app factory.AppBuilder, args []string) (string, error) {
+const flagKeyOffline = "offline"
+
+func addOfflineFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(flagKeyOffline, false, "derive the message from the staged patch by heuristics without the provider")
+}
+
+func generateCommitMessage(ctx context.Context, app factory.AppBuilder, args []string) (entities.Completion, error) {
 	if len(args) > 1 {
-		return "", fmt.Errorf("received %d args. This command accepts at most 1 arg with user context of changes",
-			len(args))
+		return entities.Completion{}, fmt.Errorf(
+			"received %d args. This command accepts at most 1 arg with user context of changes", len(args))
 	}
 
 	var userInput string
@@ -49,29 +70,29 @@ func generateCommitMessage(ctx context.Context, app factory.AppBuilder, args []s
 		userInput = args[0]
 	}
 
-	git, err := app.GetGitChangesProvider()
+	changes, err := app.GetGitChangesProvider()
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
-	status, err := git.Status(ctx)
+	status, err := changes.Status(ctx)
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
-	stagedStatus, err := git.StagedStatus(ctx)
+	stagedStatus, err := changes.StagedStatus(ctx)
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
-	diff, err := git.StagedDiff(ctx, 30)
+	diff, err := changes.StagedDiff(ctx, git.DiffContextLines)
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
 	agent, err := app.GetAIAgent()
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
 	res, err := agent.CreateCommitMessage(ctx, entity.CommitData{
@@ -81,7 +102,7 @@ func generateCommitMessage(ctx context.Context, app factory.AppBuilder, args []s
 		Diff:         diff,
 	})
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
 	return res, nil
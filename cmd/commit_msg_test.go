@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
 	aiagent_mock "github.com/yaroslav-koval/hange/mocks/aiagent"
 	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
 	changesprovider_mock "github.com/yaroslav-koval/hange/mocks/changesprovider"
@@ -32,7 +33,7 @@ func TestCommitMessageCommandRunESuccess(t *testing.T) {
 		Status:       "git status",
 		StagedStatus: "staged status",
 		Diff:         "diff output",
-	}).Return("final message", nil)
+	}).Return(entities.Completion{Text: "final message"}, nil)
 
 	commitMsgCmd.SetContext(ctx)
 
@@ -44,7 +45,7 @@ func TestGenerateCommitMessageRejectsMultipleArgs(t *testing.T) {
 	t.Parallel()
 
 	message, err := generateCommitMessage(context.Background(), nil, []string{"one", "two"})
-	require.Empty(t, message)
+	require.Empty(t, message.Text)
 	require.ErrorContains(t, err, "at most 1 arg")
 }
 
@@ -86,7 +87,7 @@ func TestGenerateCommitMessageSuccess(t *testing.T) {
 				Status:       "git status",
 				StagedStatus: "staged status",
 				Diff:         "diff output",
-			}).Return("final message", nil)
+			}).Return(entities.Completion{Text: "final message"}, nil)
 
 			app := appbuilder_mock.NewMockAppBuilder(t)
 
@@ -95,7 +96,7 @@ func TestGenerateCommitMessageSuccess(t *testing.T) {
 
 			message, err := generateCommitMessage(ctx, app, tt.args)
 			require.NoError(t, err)
-			require.Equal(t, "final message", message)
+			require.Equal(t, "final message", message.Text)
 		})
 	}
 }
@@ -118,7 +119,7 @@ func TestGenerateCommitMessagePropagatesErrors(t *testing.T) {
 		app.EXPECT().GetGitChangesProvider().Return(gitMock, nil)
 
 		message, err := generateCommitMessage(ctx, app, nil)
-		require.Empty(t, message)
+		require.Empty(t, message.Text)
 		require.ErrorIs(t, err, statusErr)
 	})
 
@@ -136,7 +137,7 @@ func TestGenerateCommitMessagePropagatesErrors(t *testing.T) {
 		app.EXPECT().GetGitChangesProvider().Return(gitMock, nil)
 
 		message, err := generateCommitMessage(ctx, app, nil)
-		require.Empty(t, message)
+		require.Empty(t, message.Text)
 		require.ErrorIs(t, err, stagedStatusErr)
 	})
 
@@ -155,7 +156,7 @@ func TestGenerateCommitMessagePropagatesErrors(t *testing.T) {
 		app.EXPECT().GetGitChangesProvider().Return(gitMock, nil)
 
 		message, err := generateCommitMessage(ctx, app, nil)
-		require.Empty(t, message)
+		require.Empty(t, message.Text)
 		require.ErrorIs(t, err, diffErr)
 	})
 
@@ -174,7 +175,7 @@ func TestGenerateCommitMessagePropagatesErrors(t *testing.T) {
 			Status:       "git status",
 			StagedStatus: "staged status",
 			Diff:         "diff output",
-		}).Return("", agentErr)
+		}).Return(entities.Completion{}, agentErr)
 
 		app := appbuilder_mock.NewMockAppBuilder(t)
 
@@ -182,7 +183,7 @@ func TestGenerateCommitMessagePropagatesErrors(t *testing.T) {
 		app.EXPECT().GetAIAgent().Return(agentMock, nil)
 
 		message, err := generateCommitMessage(ctx, app, nil)
-		require.Empty(t, message)
+		require.Empty(t, message.Text)
 		require.ErrorIs(t, err, agentErr)
 	})
 }
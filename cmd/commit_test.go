@@ -4,8 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
 	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
 	aiagent_mock "github.com/yaroslav-koval/hange/mocks/aiagent"
 	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
 	changesprovider_mock "github.com/yaroslav-koval/hange/mocks/changesprovider"
@@ -31,7 +35,7 @@ func TestCommitCmdRunESuccess(t *testing.T) {
 		Status:       "git status",
 		StagedStatus: "staged status",
 		Diff:         "diff output",
-	}).Return("final message", nil)
+	}).Return(entities.Completion{Text: "final message"}, nil)
 	gitMock.EXPECT().Commit(ctx, "final message").Return(nil)
 
 	commitCmd.SetContext(ctx)
@@ -39,3 +43,28 @@ func TestCommitCmdRunESuccess(t *testing.T) {
 	err := commitCmd.RunE(commitCmd, nil)
 	require.NoError(t, err)
 }
+
+func TestCommitCmdRunEDryRun(t *testing.T) {
+	t.Parallel()
+
+	gitMock := changesprovider_mock.NewMockChangesProvider(t)
+	agentMock := aiagent_mock.NewMockAIAgent(t)
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+
+	app.EXPECT().GetGitChangesProvider().Return(gitMock, nil)
+	app.EXPECT().GetAIAgent().Return(agentMock, nil)
+
+	ctx := appToContext(context.Background(), app)
+
+	gitMock.EXPECT().Status(ctx).Return("git status", nil)
+	gitMock.EXPECT().StagedStatus(ctx).Return("staged status", nil)
+	gitMock.EXPECT().StagedDiff(ctx, 30).Return("diff output", nil)
+	agentMock.EXPECT().CreateCommitMessage(ctx, mock.Anything).Return(entities.Completion{}, dryrun.ErrDryRun)
+
+	cmd := &cobra.Command{RunE: commitCmd.RunE}
+	cmd.SetContext(ctx)
+
+	require.NoError(t, cmd.RunE(cmd, nil))
+	gitMock.AssertNotCalled(t, "Commit", mock.Anything, mock.Anything)
+}
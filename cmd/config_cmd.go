@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/schema"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+const (
+	flagKeyShowSecrets = "show-secrets"
+	flagKeyRepo        = "repo"
+	flagKeyDryRun      = "dry-run"
+)
+
+var (
+	errInvalidConfig = errors.New("config is invalid")
+	errNoRepoConfig  = errors.New("no repository config found")
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get, set and validate settings",
+	Long: `Get, set and validate settings of the config file.
+Values are validated against the known keys. Secrets are masked unless --show-secrets is set.
+A repository can set keys marked "repo" in a .hange.yaml file. It is searched from the working directory
+up to the git root and overrides the config file, env variables override both.
+
+Known keys ("*" is a profile name):
+` + knownKeysHelp(),
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a value, a default value is printed if the key is not set",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := schema.Lookup(args[0])
+		if err != nil {
+			return err
+		}
+
+		c, err := configuratorFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		var value any = key.Default
+		if c.FieldSource(args[0]) != config.SourceDefault {
+			value = c.ReadField(args[0])
+		}
+
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), formatConfigValue(cmd, key, value))
+
+		return err
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:     "set <key> <value>",
+	Short:   "Validate and write a value",
+	Example: `hange config set profiles.work.token_command_timeout 30s`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := strings.ToLower(args[0])
+
+		key, err := schema.Lookup(path)
+		if err != nil {
+			return err
+		}
+
+		value, err := key.Coerce(args[1])
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		c, err := configuratorFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err = c.WriteField(path, value); err != nil {
+			return err
+		}
+
+		switch c.FieldSource(path) {
+		case config.SourceEnv:
+			slog.Warn("The key is written, but an env variable overrides it", slog.String("key", path))
+		case config.SourceRepo:
+			slog.Warn("The key is written, but the repository config overrides it",
+				slog.String("key", path), slog.String(logging.KeyFile, c.RepoPath()))
+		default:
+		}
+
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a key from the config file, unknown keys can be removed too",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := configuratorFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		return c.DeleteField(args[0])
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List values with their sources: file, repo, env or default",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := configuratorFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+		for _, e := range schema.Entries(c) {
+			value := formatConfigValue(cmd, e.Key, e.Value)
+			if !e.Known() {
+				value += " (unknown key)"
+			}
+
+			if _, err = fmt.Fprintf(w, "%s\t%s\t%s\n", e.Path, value, e.Source); err != nil {
+				return err
+			}
+		}
+
+		return w.Flush()
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print a path of the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := configuratorFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		path := c.Path()
+
+		if repo, _ := cmd.Flags().GetBool(flagKeyRepo); repo {
+			if path = c.RepoPath(); path == "" {
+				return errNoRepoConfig
+			}
+		}
+
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), path)
+
+		return err
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $VISUAL or $EDITOR and validate it",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := configuratorFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		editor := strings.Fields(editorCommand())
+
+		e := exec.CommandContext(cmd.Context(), editor[0], append(editor[1:], c.Path())...)
+		e.Stdin, e.Stdout, e.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+		if err = e.Run(); err != nil {
+			return fmt.Errorf("failed to run editor: %w", err)
+		}
+
+		if err = c.Reload(); err != nil {
+			return err
+		}
+
+		return validateConfig(c)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check values of the config file and env against the known keys",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := configuratorFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		return validateConfig(c)
+	},
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the config file written by an older version",
+	Long: `Upgrade the config file written by an older version: move it from an old location and change its layout.
+The file is backed up before it is changed. Other commands migrate the config automatically,
+use --dry-run to see the pending changes before that.`,
+	Example: `hange config migrate --dry-run`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app, err := appFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool(flagKeyDryRun)
+		if err != nil {
+			return err
+		}
+
+		// not a configurator, it would migrate the config before the dry run
+		m, err := app.GetConfigMigrator()
+		if err != nil {
+			return err
+		}
+
+		res, err := m.Migrate(dryRun)
+		if err != nil {
+			return err
+		}
+
+		return printMigrationResult(cmd.OutOrStdout(), res, dryRun)
+	},
+}
+
+func printMigrationResult(w io.Writer, res config.MigrationResult, dryRun bool) error {
+	if res.FromVersion == res.ToVersion && len(res.Changes) == 0 {
+		_, err := fmt.Fprintf(w, "Config %s is up to date, version %d\n", res.Path, res.ToVersion)
+		return err
+	}
+
+	title := "Migrated"
+	if dryRun {
+		title = "Pending migration of"
+	}
+
+	if _, err := fmt.Fprintf(w, "%s config %s from version %d to %d\n", title, res.Path, res.FromVersion,
+		res.ToVersion); err != nil {
+		return err
+	}
+
+	for _, c := range res.Changes {
+		if _, err := fmt.Fprintf(w, "  - %s\n", c); err != nil {
+			return err
+		}
+	}
+
+	if res.Backup == "" {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w, "Backup: %s\n", res.Backup)
+
+	return err
+}
+
+func configuratorFromContext(cmd *cobra.Command) (config.Configurator, error) {
+	app, err := appFromContext(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return app.GetConfigurator()
+}
+
+func validateConfig(c config.Configurator) error {
+	errs := schema.Validate(c)
+	if len(errs) == 0 {
+		slog.Info("Config is valid")
+		return nil
+	}
+
+	for _, err := range errs {
+		slog.Error("Config is invalid", logging.Err(err))
+	}
+
+	return fmt.Errorf("%w: %d problem(s)", errInvalidConfig, len(errs))
+}
+
+func formatConfigValue(cmd *cobra.Command, key schema.Key, value any) string {
+	if show, _ := cmd.Flags().GetBool(flagKeyShowSecrets); show {
+		return fmt.Sprint(value)
+	}
+
+	return key.Format(value)
+}
+
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	return "vi"
+}
+
+func knownKeysHelp() string {
+	b := strings.Builder{}
+
+	for _, k := range schema.Keys() {
+		b.WriteString(fmt.Sprintf("  %s (%s): %s", k.Pattern, k.Kind, k.Description))
+
+		if k.Repo {
+			b.WriteString(", repo")
+		}
+
+		if k.Default != "" {
+			b.WriteString(fmt.Sprintf(", default %q", k.Default))
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func init() {
+	configGetCmd.Flags().Bool(flagKeyShowSecrets, false, "print secrets as is")
+	configListCmd.Flags().Bool(flagKeyShowSecrets, false, "print secrets as is")
+	configMigrateCmd.Flags().Bool(flagKeyDryRun, false, "print the pending changes without writing them")
+	configPathCmd.Flags().Bool(flagKeyRepo, false, "print a path of the repository config instead")
+
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd, configPathCmd, configEditCmd,
+		configValidateCmd, configMigrateCmd)
+	rootCmd.AddCommand(configCmd)
+}
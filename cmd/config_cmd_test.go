@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/schema"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+	migrator_mock "github.com/yaroslav-koval/hange/mocks/migrator"
+)
+
+func TestConfigSetCoercesValue(t *testing.T) {
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().WriteField("profiles.work.token_command_timeout", "1m30s").Return(nil)
+	c.EXPECT().FieldSource("profiles.work.token_command_timeout").Return(config.SourceFile)
+
+	cmd := newConfigTestCommand(t, c, configSetCmd)
+
+	require.NoError(t, cmd.RunE(cmd, []string{"Profiles.Work.Token_Command_Timeout", "90s"}))
+}
+
+func TestConfigSetRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "unknown key", args: []string{"profiles.work.color", "red"}, wantErr: schema.ErrUnknownKey},
+		{name: "invalid value", args: []string{"profiles.work.base_url", "localhost"}, wantErr: schema.ErrInvalidValue},
+		{name: "managed key", args: []string{"profiles.work.token", "sk-plain"}, wantErr: schema.ErrManagedKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{RunE: configSetCmd.RunE}
+			cmd.SetContext(context.Background())
+
+			require.ErrorIs(t, cmd.RunE(cmd, tt.args), tt.wantErr)
+		})
+	}
+}
+
+func TestConfigGetMasksSecrets(t *testing.T) {
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().FieldSource("profiles.work.token_command").Return(config.SourceFile)
+	c.EXPECT().ReadField("profiles.work.token_command").Return("op read op://work/token")
+
+	cmd := newConfigTestCommand(t, c, configGetCmd)
+	cmd.Flags().Bool(flagKeyShowSecrets, false, "")
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, cmd.RunE(cmd, []string{"profiles.work.token_command"}))
+	require.Equal(t, "********\n", out.String())
+
+	out.Reset()
+	require.NoError(t, cmd.Flags().Set(flagKeyShowSecrets, "true"))
+	require.NoError(t, cmd.RunE(cmd, []string{"profiles.work.token_command"}))
+	require.Equal(t, "op read op://work/token\n", out.String())
+}
+
+func TestConfigGetPrintsDefault(t *testing.T) {
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().FieldSource("active_profile").Return(config.SourceDefault)
+
+	cmd := newConfigTestCommand(t, c, configGetCmd)
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, cmd.RunE(cmd, []string{"active_profile"}))
+	require.Equal(t, "default\n", out.String())
+}
+
+func TestConfigValidateReportsProblems(t *testing.T) {
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().ReadField("profiles").Return(nil)
+	c.EXPECT().FieldSource("active_profile").Return(config.SourceFile)
+	c.EXPECT().ReadField("active_profile").Return("My.Work")
+	c.EXPECT().FieldSource(mock.Anything).Return(config.SourceDefault)
+	c.EXPECT().FileFields().Return([]string{"active_profile"})
+
+	cmd := newConfigTestCommand(t, c, configValidateCmd)
+
+	require.ErrorIs(t, cmd.RunE(cmd, nil), errInvalidConfig)
+}
+
+func newConfigTestCommand(t *testing.T, c config.Configurator, source *cobra.Command) *cobra.Command {
+	t.Helper()
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetConfigurator().Return(c, nil)
+
+	cmd := &cobra.Command{RunE: source.RunE}
+	cmd.SetContext(appToContext(context.Background(), app))
+
+	return cmd
+}
+
+func TestConfigPathRepo(t *testing.T) {
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().Path().Return("/home/user/.hange/config")
+	c.EXPECT().RepoPath().Return("").Once()
+
+	cmd := newConfigTestCommand(t, c, configPathCmd)
+	cmd.Flags().Bool(flagKeyRepo, false, "")
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, cmd.RunE(cmd, nil))
+	require.Equal(t, "/home/user/.hange/config\n", out.String())
+
+	require.NoError(t, cmd.Flags().Set(flagKeyRepo, "true"))
+	require.ErrorIs(t, cmd.RunE(cmd, nil), errNoRepoConfig)
+
+	c.EXPECT().RepoPath().Return("/src/project/.hange.yaml")
+
+	out.Reset()
+	require.NoError(t, cmd.RunE(cmd, nil))
+	require.Equal(t, "/src/project/.hange.yaml\n", out.String())
+}
+
+func TestConfigMigrateDryRun(t *testing.T) {
+	m := migrator_mock.NewMockMigrator(t)
+	m.EXPECT().Migrate(true).Return(config.MigrationResult{
+		Path:        "/home/user/.hange/config",
+		FromVersion: 0,
+		ToVersion:   1,
+		Changes:     []string{"move auth.openai.token to profiles.default.token"},
+	}, nil)
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetConfigMigrator().Return(m, nil)
+
+	cmd := &cobra.Command{RunE: configMigrateCmd.RunE}
+	cmd.SetContext(appToContext(context.Background(), app))
+	cmd.Flags().Bool(flagKeyDryRun, true, "")
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, cmd.RunE(cmd, nil))
+	require.Equal(t, `Pending migration of config /home/user/.hange/config from version 0 to 1
+  - move auth.openai.token to profiles.default.token
+`, out.String())
+}
@@ -4,27 +4,111 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"runtime"
+	"io"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/entities"
 	"github.com/yaroslav-koval/hange/domain/factory"
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
-	"golang.org/x/sync/errgroup"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/archiveprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/ignore"
+)
+
+const (
+	flagKeyInclude          = "include"
+	flagKeyExclude          = "exclude"
+	flagKeyNoIgnore         = "no-ignore"
+	flagKeyMaxFileSize      = "max-file-size"
+	flagKeyIncludeGenerated = "include-generated"
+	flagKeyFollowSymlinks   = "follow-symlinks"
+	flagKeySymlinkEscape    = "allow-symlink-escape"
+	flagKeyStdinName        = "stdin-name"
+	flagKeyPersistent       = "persistent"
+	flagKeyFresh            = "fresh"
 )
 
 var explainCmd = &cobra.Command{
-	Use:     "explain [inputs]",
-	Short:   "Explain file(s) or directory(ies)",
-	Long:    `Explain file(s) or directory(ies) from the engineer's perspective.`,
-	Example: `hange explain file1 file2 directory`,
+	Use:   "explain [inputs]",
+	Short: "Explain file(s) or directory(ies)",
+	Long: `Explain file(s) or directory(ies) from the engineer's perspective.
+Directories are read recursively. Files matched by .gitignore and .hangeignore files are skipped,
+as well as the .git directory and patterns of the "ignore" config key. Use --no-ignore to read everything.
+Binary files, files above --max-file-size and generated files (generated code, lockfiles, minified assets)
+are skipped too. A summary of skipped files is printed to stderr.
+Progress of reading, uploading and indexing is printed to stderr: a live bar on a terminal,
+otherwise a line every few seconds. Use --no-progress to disable it.
+Symlinks found inside directories are skipped unless --follow-symlinks is set. Followed symlinks
+must point inside the input directory unless --allow-symlink-escape is set.
+Use "-" to explain content from stdin. Archives (.tar, .tar.gz, .tgz, .zip) passed as arguments are expanded in memory.
+With --persistent (or the "explain.persistent" config key) the vector store of the repository is kept between runs,
+so only new and changed files are uploaded. Files under the inputs which are missing from them and files deleted
+from the disk are removed from it, files of other directories are kept. Use --fresh to rebuild it from scratch.`,
+	Example: `hange explain file1 file2 directory
+hange explain . --include '*.go' --exclude '*_test.go'
+hange explain . --no-ignore
+hange explain . --follow-symlinks
+pbpaste | hange explain - --stdin-name snippet.go
+hange explain release.tar.gz
+hange explain . --format json --output explanation.json
+hange explain . --persistent
+hange explain . --dry-run`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
+
 		app, err := appFromContext(cmd.Context())
 		if err != nil {
 			return err
 		}
 
+		rw, err := resultWriterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		namesCfg, err := namesConfigFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		if namesCfg, err = withConfigIgnore(app, "", namesCfg); err != nil {
+			return err
+		}
+
+		policy, err := skipPolicyFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		stdinName, err := cmd.Flags().GetString(flagKeyStdinName)
+		if err != nil {
+			return err
+		}
+
+		opts, err := explainOptionsFromFlags(cmd, app)
+		if err != nil {
+			return err
+		}
+
+		reporter, closeProgress, err := progressFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer closeProgress()
+
+		opts.Progress = reporter
+
 		ep := &explainCmdProcessor{
-			app: app,
+			app:       app,
+			namesCfg:  namesCfg,
+			policy:    policy,
+			skipped:   fileprovider.NewSkipCollector(),
+			stdinName: stdinName,
+			opts:      opts,
 		}
 
 		if err := ep.validateArgs(args); err != nil {
@@ -32,91 +116,242 @@ var explainCmd = &cobra.Command{
 		}
 
 		e, err := ep.processExplanation(cmd.Context(), args)
+		// the bar must be gone before the results are printed
+		closeProgress()
+
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				return nil
 			}
 
+			if errors.Is(err, dryrun.ErrDryRun) {
+				return printSkippedFiles(cmd.ErrOrStderr(), ep.skipped.Skipped())
+			}
+
 			return err
 		}
 
-		if _, err = fmt.Fprintln(cmd.OutOrStdout(), e); err != nil {
-			return err
+		skipped := ep.skipped.Skipped()
+
+		if rw.isText() {
+			if err = printSkippedFiles(cmd.ErrOrStderr(), skipped); err != nil {
+				return err
+			}
 		}
 
-		return nil
+		return rw.write(ep.result(cmd.Name(), e, time.Since(start)))
 	},
 }
 
 func init() {
+	explainCmd.Flags().StringSlice(flagKeyInclude, nil, "read only files matching gitignore-style patterns")
+	explainCmd.Flags().StringSlice(flagKeyExclude, nil, "skip files and directories matching gitignore-style patterns")
+	explainCmd.Flags().Bool(flagKeyNoIgnore, false, "do not respect .gitignore and .hangeignore files")
+	explainCmd.Flags().Bool(flagKeyFollowSymlinks, false, "read symlinked files and directories found inside directories")
+	explainCmd.Flags().Bool(flagKeySymlinkEscape, false, "let followed symlinks point outside of the input directory")
+	explainCmd.Flags().String(flagKeyStdinName, archiveprovider.DefaultStdinName, "path displayed for content read from stdin")
+	explainCmd.Flags().Int64(flagKeyMaxFileSize, fileprovider.DefaultMaxFileSize,
+		"skip files larger than this size in bytes, 0 disables the limit")
+	explainCmd.Flags().Bool(flagKeyIncludeGenerated, false, "do not skip generated code, lockfiles and minified assets")
+	explainCmd.Flags().Bool(flagKeyPersistent, false, "keep the repository vector store, upload only changes")
+	explainCmd.Flags().Bool(flagKeyFresh, false, "rebuild the repository vector store, implies --persistent")
+	explainCmd.Flags().Bool(flagKeyNoProgress, false, "do not print progress of reading, uploading and indexing")
+
+	addOutputFlags(explainCmd)
+
 	rootCmd.AddCommand(explainCmd)
 }
 
-type explainCmdProcessor struct {
-	app factory.AppBuilder
-}
+func namesConfigFromFlags(cmd *cobra.Command) (fileprovider.NamesConfig, error) {
+	include, err := cmd.Flags().GetStringSlice(flagKeyInclude)
+	if err != nil {
+		return fileprovider.NamesConfig{}, err
+	}
 
-var errNoArgs = errors.New("no arguments provided")
-var errEmptyArg = errors.New("empty argument")
+	exclude, err := cmd.Flags().GetStringSlice(flagKeyExclude)
+	if err != nil {
+		return fileprovider.NamesConfig{}, err
+	}
 
-func (ep *explainCmdProcessor) validateArgs(args []string) error {
-	if len(args) == 0 {
-		return errNoArgs
+	noIgnore, err := cmd.Flags().GetBool(flagKeyNoIgnore)
+	if err != nil {
+		return fileprovider.NamesConfig{}, err
 	}
 
-	for _, arg := range args {
-		if len(arg) == 0 {
-			return errEmptyArg
-		}
+	followSymlinks, err := cmd.Flags().GetBool(flagKeyFollowSymlinks)
+	if err != nil {
+		return fileprovider.NamesConfig{}, err
 	}
 
-	return nil
+	allowSymlinkEscape, err := cmd.Flags().GetBool(flagKeySymlinkEscape)
+	if err != nil {
+		return fileprovider.NamesConfig{}, err
+	}
+
+	return fileprovider.NamesConfig{
+		Include:            include,
+		Exclude:            exclude,
+		NoIgnore:           noIgnore,
+		FollowSymlinks:     followSymlinks,
+		AllowSymlinkEscape: allowSymlinkEscape,
+	}, nil
 }
 
-func (ep *explainCmdProcessor) processExplanation(ctx context.Context, args []string) (string, error) {
-	eg, ctx := errgroup.WithContext(ctx)
+// withConfigIgnore adds the ignore rules of the config, relative to the repository config or the repository of
+// workDir. --no-ignore disables them too.
+func withConfigIgnore(
+	app factory.AppBuilder, workDir string, cfg fileprovider.NamesConfig,
+) (fileprovider.NamesConfig, error) {
+	if cfg.NoIgnore {
+		return cfg, nil
+	}
 
-	fp, err := ep.app.GetFileProvider()
+	c, err := app.GetConfigurator()
 	if err != nil {
-		return "", nil
+		return cfg, err
 	}
 
-	fileNames, err := fp.GetAllFileNames(ctx, args)
+	rules, err := ignore.ReadConfig(c, workDir)
 	if err != nil {
-		return "", err
+		return cfg, fmt.Errorf("invalid %s pattern of the config: %w", consts.IgnorePath, err)
 	}
 
-	agent, err := ep.app.GetAIAgent()
+	cfg.Ignore = rules
+
+	return cfg, nil
+}
+
+func skipPolicyFromFlags(cmd *cobra.Command) (fileprovider.SkipPolicy, error) {
+	maxFileSize, err := cmd.Flags().GetInt64(flagKeyMaxFileSize)
 	if err != nil {
-		return "", err
+		return fileprovider.SkipPolicy{}, err
 	}
 
-	workers := runtime.GOMAXPROCS(0) - 1 // keep 1 free thread for files consumer
-	workers = max(workers, 1)            // in case GOMAXPROCS=1
+	if maxFileSize < 0 {
+		return fileprovider.SkipPolicy{}, fmt.Errorf("--%s can't be negative", flagKeyMaxFileSize)
+	}
 
-	filesCh, doneCh := fp.ReadFiles(ctx, fileprovider.Config{
-		Workers:    workers,
-		BufferSize: workers * 2,
-	}, fileNames)
+	includeGenerated, err := cmd.Flags().GetBool(flagKeyIncludeGenerated)
+	if err != nil {
+		return fileprovider.SkipPolicy{}, err
+	}
 
-	eg.Go(func() error {
-		return <-doneCh
-	})
+	return fileprovider.SkipPolicy{
+		MaxFileSize:    maxFileSize,
+		AllowGenerated: includeGenerated,
+	}, nil
+}
 
-	var output string
+// explainOptionsFromFlags falls back to the config if --persistent is not set
+func explainOptionsFromFlags(cmd *cobra.Command, app factory.AppBuilder) (entity.ExplainOptions, error) {
+	fresh, err := cmd.Flags().GetBool(flagKeyFresh)
+	if err != nil {
+		return entity.ExplainOptions{}, err
+	}
+
+	persistent, err := cmd.Flags().GetBool(flagKeyPersistent)
+	if err != nil {
+		return entity.ExplainOptions{}, err
+	}
 
-	eg.Go(func() error {
-		output, err = agent.ExplainFiles(ctx, filesCh)
+	if !fresh && !cmd.Flags().Changed(flagKeyPersistent) {
+		c, err := app.GetConfigurator()
 		if err != nil {
-			return err
+			return entity.ExplainOptions{}, err
 		}
 
+		if persistent, err = config.ReadBool(c, consts.ExplainPersistentPath); err != nil {
+			return entity.ExplainOptions{}, err
+		}
+	}
+
+	return entity.ExplainOptions{Persistent: persistent || fresh, Fresh: fresh}, nil
+}
+
+func printSkippedFiles(w io.Writer, skipped []entities.SkippedFile) error {
+	if len(skipped) == 0 {
 		return nil
-	})
+	}
+
+	if _, err := fmt.Fprintf(w, "Skipped %d file(s):\n", len(skipped)); err != nil {
+		return err
+	}
+
+	for _, f := range skipped {
+		line := fmt.Sprintf("  %s: %s", f.Path, f.Reason)
+		if f.Details != "" {
+			line += " (" + f.Details + ")"
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type explainCmdProcessor struct {
+	app       factory.AppBuilder
+	namesCfg  fileprovider.NamesConfig
+	policy    fileprovider.SkipPolicy
+	skipped   *fileprovider.SkipCollector
+	stdinName string
+	opts      entity.ExplainOptions
+	// processed is a number of files sent to the agent. Valid after processExplanation returns.
+	processed int
+}
+
+// result is an envelope of the explanation with counts of processed and skipped files
+func (ep *explainCmdProcessor) result(command string, c entities.Completion, duration time.Duration) entities.Result {
+	res := entities.NewResult(command, c, duration)
+	res.FilesProcessed = ep.processed
+
+	if skipped := ep.skipped.Skipped(); len(skipped) > 0 {
+		res.Skipped = skipped
+	}
+
+	return res
+}
+
+var errNoArgs = errors.New("no arguments provided")
+var errEmptyArg = errors.New("empty argument")
+var errStdinTwice = errors.New("stdin can be read only once")
 
-	if err = eg.Wait(); err != nil {
-		return "", err
+func (ep *explainCmdProcessor) validateArgs(args []string) error {
+	if len(args) == 0 {
+		return errNoArgs
 	}
 
-	return output, nil
+	stdinCount := 0
+
+	for _, arg := range args {
+		if len(arg) == 0 {
+			return errEmptyArg
+		}
+
+		if arg == archiveprovider.StdinPath {
+			stdinCount++
+		}
+	}
+
+	if stdinCount > 1 {
+		return errStdinTwice
+	}
+
+	return nil
+}
+
+func (ep *explainCmdProcessor) processExplanation(ctx context.Context, args []string) (entities.Completion, error) {
+	c, processed, err := factory.ExplainPaths(ctx, ep.app, factory.ExplainRun{
+		Paths:     args,
+		Names:     ep.namesCfg,
+		Policy:    ep.policy,
+		Skipped:   ep.skipped,
+		StdinName: ep.stdinName,
+		Options:   ep.opts,
+	})
+	ep.processed = processed
+
+	return c, err
 }
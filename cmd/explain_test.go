@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/ignore"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+func TestWithConfigIgnore(t *testing.T) {
+	repo := t.TempDir()
+
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().ReadField("ignore").Return([]any{"/gen"})
+	c.EXPECT().FieldSource("ignore").Return(config.SourceRepo)
+	c.EXPECT().RepoPath().Return(filepath.Join(repo, ".hange.yaml"))
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetConfigurator().Return(c, nil)
+
+	cfg, err := withConfigIgnore(app, filepath.Join(repo, "sub"), fileprovider.NamesConfig{Exclude: []string{"*_test.go"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"*_test.go"}, cfg.Exclude, "the config doesn't change the flags")
+
+	// the pattern is anchored at the directory of the repository config, not at the working directory
+	m := ignore.NewMatcher(cfg.Ignore...)
+	require.True(t, m.Ignored(filepath.Join(repo, "gen"), true))
+	require.False(t, m.Ignored(filepath.Join(repo, "sub", "gen"), true))
+
+	// --no-ignore skips the config
+	cfg, err = withConfigIgnore(app, repo, fileprovider.NamesConfig{NoIgnore: true})
+	require.NoError(t, err)
+	require.Empty(t, cfg.Ignore)
+}
+
+func TestExplainOptionsFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool(flagKeyPersistent, false, "")
+		cmd.Flags().Bool(flagKeyFresh, false, "")
+		require.NoError(t, cmd.Flags().Parse(args))
+
+		return cmd
+	}
+
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().ReadField("explain.persistent").Return(true).Once()
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetConfigurator().Return(c, nil).Once()
+
+	opts, err := explainOptionsFromFlags(newCmd(), app)
+	require.NoError(t, err)
+	require.Equal(t, entity.ExplainOptions{Persistent: true}, opts)
+
+	// flags don't read the config
+	opts, err = explainOptionsFromFlags(newCmd("--persistent=false"), app)
+	require.NoError(t, err)
+	require.Equal(t, entity.ExplainOptions{}, opts)
+
+	opts, err = explainOptionsFromFlags(newCmd("--fresh"), app)
+	require.NoError(t, err)
+	require.Equal(t, entity.ExplainOptions{Persistent: true, Fresh: true}, opts)
+}
+
+func TestProgressFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.Flags().Bool(flagKeyNoProgress, false, "")
+		require.NoError(t, cmd.Flags().Parse(args))
+
+		return cmd
+	}
+
+	reporter, closeProgress, err := progressFromFlags(newCmd("--no-progress"))
+	require.NoError(t, err)
+	require.Equal(t, progress.Discard, reporter)
+	closeProgress()
+
+	// stderr is not a terminal, so plain lines are printed
+	cmd := newCmd()
+
+	reporter, closeProgress, err = progressFromFlags(cmd)
+	require.NoError(t, err)
+	require.IsType(t, &progress.Printer{}, reporter)
+
+	reporter.Done(progress.StageRead, 2, 10)
+	reporter.Finish(progress.StageRead)
+	closeProgress()
+
+	require.Contains(t, cmd.ErrOrStderr().(*bytes.Buffer).String(), "read: 2 files, 10 B in ")
+}
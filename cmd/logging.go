@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+const (
+	flagKeyLogFormat = "log-format"
+	flagKeyLogFile   = "log-file"
+)
+
+// closeLog closes the log file of the run. It is set by the root command.
+var closeLog = func() error { return nil }
+
+// setupLogging makes the default logger write records of the format to stderr or the log file.
+// Every record has the run ID and the command, so runs sharing a log file can be told apart.
+func setupLogging(cmd *cobra.Command, verbose bool) error {
+	format, err := cmd.Flags().GetString(flagKeyLogFormat)
+	if err != nil {
+		return err
+	}
+
+	file, err := cmd.Flags().GetString(flagKeyLogFile)
+	if err != nil {
+		return err
+	}
+
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+
+	logger, closeFile, err := logging.New(logging.Options{
+		Format: format,
+		File:   file,
+		Level:  level,
+		Attrs: []slog.Attr{
+			slog.String(logging.KeyRunID, logging.NewRunID()),
+			slog.String(logging.KeyCommand, cmd.CommandPath()),
+		},
+	}, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	_ = closeLog()
+
+	slog.SetDefault(logger)
+	closeLog = closeFile
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/archiveprovider"
+	"github.com/yaroslav-koval/hange/pkg/mcp"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+const (
+	mcpToolCommitMessage = "generate_commit_message"
+	mcpToolExplainPaths  = "explain_paths"
+	mcpToolGitStatus     = "git_status"
+)
+
+var errMCPDryRun = errors.New("--dry-run is not supported by mcp serve, stdout carries the protocol")
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve hange to editors and agents over the Model Context Protocol",
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve hange tools over stdio",
+	Long: `Serve hange tools over the Model Context Protocol. Messages are read from stdin and written to stdout,
+logs go to stderr or --log-file. Tools:
+  ` + mcpToolCommitMessage + `  a commit message of the staged changes
+  ` + mcpToolExplainPaths + `            an explanation of files and directories, progress is reported
+  ` + mcpToolGitStatus + `               the status of the repository
+Tools work in the directory the server is started in and use the config and the profile of the flags.
+Each call is wired like a separate run of the command, so calls can run concurrently and be cancelled.
+Persistent explains share the vector store of the repository, so they run one at a time.`,
+	Example: `hange mcp serve
+hange --profile work --log-file /tmp/hange-mcp.log mcp serve`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, err := cmd.Root().PersistentFlags().GetBool(flagKeyDryRun)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errMCPDryRun
+		}
+
+		newApp := func(offline bool) (factory.AppBuilder, error) {
+			return newAppBuilder(cmd, "", offline, false)
+		}
+
+		server := mcp.NewServer(mcp.Implementation{Name: "hange", Version: buildVersion()}, mcpInstructions,
+			mcpTools(newApp))
+
+		err = server.Serve(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+
+		return err
+	},
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpServeCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+const mcpInstructions = `hange explains code and writes commit messages of the git repository the server runs in.
+Use ` + mcpToolGitStatus + ` to check what is staged before ` + mcpToolCommitMessage + `.`
+
+// buildVersion is the version of the build, "dev" if it is unknown
+func buildVersion() string {
+	val, err := config.ReadFieldFromBytes(getBuildConfig(), config.FileTypeYaml, "version")
+	if err != nil || val == nil {
+		return "dev"
+	}
+
+	return fmt.Sprint(val)
+}
+
+// appBuilderFunc wires a new application for every call, since the agent keeps the state of a run
+type appBuilderFunc func(offline bool) (factory.AppBuilder, error)
+
+func mcpTools(newApp appBuilderFunc) []mcp.Tool {
+	// the server works in one repository, persistent explains of it share a sync state and a vector store
+	persistentLock := make(chan struct{}, 1)
+
+	return []mcp.Tool{
+		{
+			Name: mcpToolCommitMessage,
+			Description: "Generate a commit message of the staged changes of the git repository. " +
+				"With offline the message is derived from the patch by heuristics without the provider.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "context": {"type": "string", "description": "description of the changes, e.g. a task"},
+    "offline": {"type": "boolean", "description": "derive the message by heuristics without the provider"}
+  }
+}`),
+			Run: mcpCommitMessage(newApp),
+		},
+		{
+			Name: mcpToolExplainPaths,
+			Description: "Explain files and directories from a developer's perspective. Directories are read " +
+				"recursively, ignored, binary, generated and large files are skipped.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "paths": {"type": "array", "items": {"type": "string"}, "minItems": 1,
+      "description": "files, directories or archives relative to the working directory"},
+    "include": {"type": "array", "items": {"type": "string"}, "description": "gitignore-style patterns to read"},
+    "exclude": {"type": "array", "items": {"type": "string"}, "description": "gitignore-style patterns to skip"},
+    "persistent": {"type": "boolean", "description": "keep the vector store of the repository between calls"}
+  },
+  "required": ["paths"]
+}`),
+			Run: mcpExplainPaths(newApp, persistentLock),
+		},
+		{
+			Name:        mcpToolGitStatus,
+			Description: "Show the status of the git repository: all changes and the staged ones.",
+			InputSchema: json.RawMessage(`{"type": "object", "properties": {}}`),
+			Run:         mcpGitStatus(newApp),
+		},
+	}
+}
+
+func mcpCommitMessage(newApp appBuilderFunc) mcp.ToolFunc {
+	return func(ctx context.Context, rawArgs json.RawMessage, _ progress.Reporter) (mcp.ToolResult, error) {
+		var args struct {
+			Context string `json:"context"`
+			Offline bool   `json:"offline"`
+		}
+		if err := mcp.DecodeArguments(rawArgs, &args); err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		start := time.Now()
+
+		app, err := newApp(args.Offline)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		var userInput []string
+		if args.Context != "" {
+			userInput = []string{args.Context}
+		}
+
+		c, err := generateCommitMessage(ctx, app, userInput)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		return mcp.ToolResult{
+			Text:       c.Text,
+			Structured: entities.NewResult(mcpToolCommitMessage, c, time.Since(start)),
+		}, nil
+	}
+}
+
+var errMCPStdin = fmt.Errorf("%q can't be explained, stdin carries the protocol", archiveprovider.StdinPath)
+
+// mcpExplainPaths runs persistent explains one at a time by the lock, the last saved sync state would win otherwise
+// and the calls would delete files of each other
+func mcpExplainPaths(newApp appBuilderFunc, persistentLock chan struct{}) mcp.ToolFunc {
+	return func(ctx context.Context, rawArgs json.RawMessage, reporter progress.Reporter) (mcp.ToolResult, error) {
+		var args struct {
+			Paths      []string `json:"paths"`
+			Include    []string `json:"include"`
+			Exclude    []string `json:"exclude"`
+			Persistent *bool    `json:"persistent"`
+		}
+		if err := mcp.DecodeArguments(rawArgs, &args); err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		if slices.Contains(args.Paths, archiveprovider.StdinPath) {
+			return mcp.ToolResult{}, fmt.Errorf("%w: %w", mcp.ErrInvalidArguments, errMCPStdin)
+		}
+
+		start := time.Now()
+
+		app, err := newApp(false)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		ep, err := newDefaultExplainProcessor(app, "", args.Include, args.Exclude, args.Persistent)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		ep.opts.Progress = reporter
+
+		if err = ep.validateArgs(args.Paths); err != nil {
+			return mcp.ToolResult{}, fmt.Errorf("%w: %w", mcp.ErrInvalidArguments, err)
+		}
+
+		if ep.opts.Persistent {
+			select {
+			case <-ctx.Done():
+				return mcp.ToolResult{}, ctx.Err()
+			case persistentLock <- struct{}{}:
+			}
+
+			defer func() { <-persistentLock }()
+		}
+
+		c, err := ep.processExplanation(ctx, args.Paths)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		return mcp.ToolResult{
+			Text:       c.Text,
+			Structured: ep.result(mcpToolExplainPaths, c, time.Since(start)),
+		}, nil
+	}
+}
+
+// newDefaultExplainProcessor applies the defaults of the explain command to the files of workDir, the working
+// directory if it is empty. The config decides if the vector store is persistent unless the call does.
+func newDefaultExplainProcessor(
+	app factory.AppBuilder, workDir string, include, exclude []string, persistent *bool,
+) (*explainCmdProcessor, error) {
+	namesCfg, err := withConfigIgnore(app, workDir, fileprovider.NamesConfig{Include: include, Exclude: exclude})
+	if err != nil {
+		return nil, err
+	}
+
+	ep := &explainCmdProcessor{
+		app:       app,
+		namesCfg:  namesCfg,
+		policy:    fileprovider.SkipPolicy{MaxFileSize: fileprovider.DefaultMaxFileSize},
+		skipped:   fileprovider.NewSkipCollector(),
+		stdinName: archiveprovider.DefaultStdinName,
+	}
+
+	if persistent != nil {
+		ep.opts.Persistent = *persistent
+		return ep, nil
+	}
+
+	c, err := app.GetConfigurator()
+	if err != nil {
+		return nil, err
+	}
+
+	if ep.opts.Persistent, err = config.ReadBool(c, consts.ExplainPersistentPath); err != nil {
+		return nil, err
+	}
+
+	return ep, nil
+}
+
+func mcpGitStatus(newApp appBuilderFunc) mcp.ToolFunc {
+	return func(ctx context.Context, rawArgs json.RawMessage, _ progress.Reporter) (mcp.ToolResult, error) {
+		if err := mcp.DecodeArguments(rawArgs, &struct{}{}); err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		app, err := newApp(false)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		git, err := app.GetGitChangesProvider()
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		status, err := git.Status(ctx)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		staged, err := git.StagedStatus(ctx)
+		if err != nil {
+			return mcp.ToolResult{}, err
+		}
+
+		return mcp.ToolResult{
+			Text: fmt.Sprintf("Staged changes:\n%s\nAll changes:\n%s", staged, status),
+			Structured: map[string]string{
+				"status":        status,
+				"staged_status": staged,
+			},
+		}, nil
+	}
+}
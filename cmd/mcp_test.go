@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	aiagent_mock "github.com/yaroslav-koval/hange/mocks/aiagent"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	changesprovider_mock "github.com/yaroslav-koval/hange/mocks/changesprovider"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+	fileprovider_mock "github.com/yaroslav-koval/hange/mocks/fileprovider"
+	"github.com/yaroslav-koval/hange/pkg/mcp"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+func TestMCPServe(t *testing.T) {
+	git := changesprovider_mock.NewMockChangesProvider(t)
+	git.EXPECT().Status(mock.Anything).Return("M main.go", nil)
+	git.EXPECT().StagedStatus(mock.Anything).Return("M main.go", nil)
+	git.EXPECT().StagedDiff(mock.Anything, 30).Return("+func main() {}", nil)
+
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().ReadField("ignore").Return(nil)
+
+	fp := fileprovider_mock.NewMockFileProvider(t)
+	fp.EXPECT().GetAllFileNames(mock.Anything, mock.Anything, []string{"cmd"}).Return([]string{"cmd/root.go"}, nil)
+	fp.EXPECT().ReadFiles(mock.Anything, mock.Anything, []string{"cmd/root.go"}).RunAndReturn(
+		func(_ context.Context, cfg fileprovider.Config, _ []string) (<-chan entities.File, <-chan error) {
+			cfg.Progress.Expect(progress.StageRead, 1)
+			cfg.Progress.Done(progress.StageRead, 1, 4)
+
+			files := make(chan entities.File, 1)
+			files <- entities.File{Path: "cmd/root.go", Data: []byte("root")}
+			close(files)
+
+			done := make(chan error, 1)
+			close(done)
+
+			return files, done
+		})
+
+	ag := aiagent_mock.NewMockAIAgent(t)
+	ag.EXPECT().CreateCommitMessage(mock.Anything, entity.CommitData{
+		UserInput:    "JIRA-1",
+		Status:       "M main.go",
+		StagedStatus: "M main.go",
+		Diff:         "+func main() {}",
+	}).Return(entities.Completion{Text: "Add main", Model: "gpt-5-mini"}, nil)
+	ag.EXPECT().ExplainFiles(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, files <-chan entities.File, opts entity.ExplainOptions) (entities.Completion, error) {
+			for range files {
+				opts.Progress.Expect(progress.StageUpload, 1)
+				opts.Progress.Done(progress.StageUpload, 1, 4)
+			}
+
+			return entities.Completion{Text: "The root command"}, nil
+		})
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetGitChangesProvider().Return(git, nil)
+	app.EXPECT().GetConfigurator().Return(c, nil)
+	app.EXPECT().GetFileProvider().Return(fp, nil)
+	app.EXPECT().GetAIAgent().Return(ag, nil)
+
+	var offlineCalls []bool
+
+	session := startMCPSession(t, func(offline bool) (factory.AppBuilder, error) {
+		offlineCalls = append(offlineCalls, offline)
+		return app, nil
+	})
+
+	session.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18",` +
+		`"capabilities":{},"clientInfo":{"name":"editor","version":"1"}}}`)
+	assert.Contains(t, session.receive(), `"serverInfo":{"name":"hange"`)
+
+	session.send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+
+	var list struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(session.receive()), &list))
+	require.Len(t, list.Result.Tools, 3)
+	assert.Equal(t, mcpToolCommitMessage, list.Result.Tools[0].Name)
+	assert.Equal(t, mcpToolExplainPaths, list.Result.Tools[1].Name)
+	assert.Equal(t, mcpToolGitStatus, list.Result.Tools[2].Name)
+
+	t.Run("git status", func(t *testing.T) {
+		session.send(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"git_status"}}`)
+
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":3,"result":{"content":[{"type":"text",`+
+			`"text":"Staged changes:\nM main.go\nAll changes:\nM main.go"}],`+
+			`"structuredContent":{"status":"M main.go","staged_status":"M main.go"},"isError":false}}`,
+			session.receive())
+	})
+
+	t.Run("commit message", func(t *testing.T) {
+		session.send(`{"jsonrpc":"2.0","id":4,"method":"tools/call",` +
+			`"params":{"name":"generate_commit_message","arguments":{"context":"JIRA-1"}}}`)
+
+		var res struct {
+			Result struct {
+				Content           []map[string]string `json:"content"`
+				StructuredContent entities.Result     `json:"structuredContent"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(session.receive()), &res))
+		assert.Equal(t, "Add main", res.Result.Content[0]["text"])
+		assert.Equal(t, mcpToolCommitMessage, res.Result.StructuredContent.Command)
+		assert.Equal(t, "gpt-5-mini", res.Result.StructuredContent.Model)
+	})
+
+	t.Run("explain with progress", func(t *testing.T) {
+		session.send(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"explain_paths",` +
+			`"arguments":{"paths":["cmd"],"persistent":false},"_meta":{"progressToken":7}}}`)
+
+		assert.JSONEq(t, `{"jsonrpc":"2.0","method":"notifications/progress",`+
+			`"params":{"progressToken":7,"progress":1,"total":1,"message":"read 1/1 files"}}`, session.receive())
+		assert.JSONEq(t, `{"jsonrpc":"2.0","method":"notifications/progress",`+
+			`"params":{"progressToken":7,"progress":2,"total":2,"message":"upload 1/1 files"}}`, session.receive())
+
+		var res struct {
+			Result struct {
+				StructuredContent entities.Result `json:"structuredContent"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(session.receive()), &res))
+		assert.Equal(t, "The root command", res.Result.StructuredContent.Result)
+		assert.Equal(t, 1, res.Result.StructuredContent.FilesProcessed)
+	})
+
+	t.Run("stdin is rejected", func(t *testing.T) {
+		session.send(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"explain_paths",` +
+			`"arguments":{"paths":["-"]}}}`)
+
+		assert.Contains(t, session.receive(), `"code":-32602`)
+	})
+
+	assert.Equal(t, []bool{false, false, false}, offlineCalls, "every call gets its own app")
+}
+
+func TestMCPExplainPaths_PersistentCallsRunOneAtATime(t *testing.T) {
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().ReadField("ignore").Return(nil)
+
+	fp := fileprovider_mock.NewMockFileProvider(t)
+	fp.EXPECT().GetAllFileNames(mock.Anything, mock.Anything, []string{"cmd"}).Return([]string{"cmd/root.go"}, nil)
+	fp.EXPECT().ReadFiles(mock.Anything, mock.Anything, []string{"cmd/root.go"}).RunAndReturn(
+		func(context.Context, fileprovider.Config, []string) (<-chan entities.File, <-chan error) {
+			files := make(chan entities.File, 1)
+			files <- entities.File{Path: "cmd/root.go", Data: []byte("root")}
+			close(files)
+
+			done := make(chan error)
+			close(done)
+
+			return files, done
+		})
+
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	ag := aiagent_mock.NewMockAIAgent(t)
+	ag.EXPECT().ExplainFiles(mock.Anything, mock.Anything, entity.ExplainOptions{
+		Persistent: true,
+		Roots:      []string{"cmd"},
+	}).RunAndReturn(
+		func(_ context.Context, files <-chan entities.File, _ entity.ExplainOptions) (entities.Completion, error) {
+			// drain the files like the agent does
+			for range files {
+			}
+
+			entered <- struct{}{}
+			<-release
+
+			return entities.Completion{Text: "The root command"}, nil
+		})
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetConfigurator().Return(c, nil)
+	app.EXPECT().GetFileProvider().Return(fp, nil)
+	app.EXPECT().GetAIAgent().Return(ag, nil)
+
+	explain := mcpExplainPaths(func(bool) (factory.AppBuilder, error) { return app, nil }, make(chan struct{}, 1))
+	args := json.RawMessage(`{"paths":["cmd"],"persistent":true}`)
+
+	results := make(chan error, 2)
+	call := func() {
+		_, err := explain(t.Context(), args, nil)
+		results <- err
+	}
+
+	go call()
+	<-entered
+
+	go call()
+
+	select {
+	case <-entered:
+		t.Fatal("the second persistent call started while the first was running")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := explain(ctx, args, nil)
+	require.ErrorIs(t, err, context.Canceled, "a waiting call is cancelled by its context")
+
+	close(release)
+	<-entered
+
+	require.NoError(t, <-results)
+	require.NoError(t, <-results)
+}
+
+// mcpSession talks to the tools of mcp serve over in-memory pipes
+type mcpSession struct {
+	t   *testing.T
+	in  *io.PipeWriter
+	out *bufio.Scanner
+}
+
+func startMCPSession(t *testing.T, newApp appBuilderFunc) *mcpSession {
+	t.Helper()
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	server := mcp.NewServer(mcp.Implementation{Name: "hange", Version: buildVersion()}, mcpInstructions,
+		mcpTools(newApp))
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Serve(context.Background(), inR, outW)
+	}()
+
+	t.Cleanup(func() {
+		_ = inW.Close()
+		require.NoError(t, <-done)
+		_ = outR.Close()
+	})
+
+	return &mcpSession{t: t, in: inW, out: bufio.NewScanner(outR)}
+}
+
+func (s *mcpSession) send(msg string) {
+	s.t.Helper()
+
+	_, err := s.in.Write([]byte(msg + "\n"))
+	require.NoError(s.t, err)
+}
+
+func (s *mcpSession) receive() string {
+	s.t.Helper()
+
+	line := make(chan string, 1)
+
+	go func() {
+		if s.out.Scan() {
+			line <- s.out.Text()
+		}
+	}()
+
+	select {
+	case l := <-line:
+		return l
+	case <-time.After(time.Second):
+		s.t.Fatal("no message from the server")
+		return ""
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/pkg/atomicfile"
+)
+
+const (
+	flagKeyFormat = "format"
+	flagKeyOutput = "output"
+)
+
+type outputFormat string
+
+const (
+	outputFormatText outputFormat = "text"
+	outputFormatJSON outputFormat = "json"
+)
+
+const outputFilePerm = 0o644
+
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagKeyFormat, string(outputFormatText), "output format: text or json")
+	cmd.Flags().StringP(flagKeyOutput, "o", "", "write the result to a file instead of stdout")
+}
+
+// resultWriter renders a command result in the requested format to stdout or to a file.
+type resultWriter struct {
+	format outputFormat
+	path   string
+	stdout io.Writer
+}
+
+// resultWriterFromFlags validates output flags. Must be called before any work is done, so a typo costs nothing.
+func resultWriterFromFlags(cmd *cobra.Command) (*resultWriter, error) {
+	format, err := cmd.Flags().GetString(flagKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	switch outputFormat(format) {
+	case outputFormatText, outputFormatJSON:
+	default:
+		return nil, fmt.Errorf("unsupported --%s %q, use %q or %q",
+			flagKeyFormat, format, outputFormatText, outputFormatJSON)
+	}
+
+	path, err := cmd.Flags().GetString(flagKeyOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resultWriter{
+		format: outputFormat(format),
+		path:   path,
+		stdout: cmd.OutOrStdout(),
+	}, nil
+}
+
+func (w *resultWriter) isText() bool {
+	return w.format == outputFormatText
+}
+
+func (w *resultWriter) write(res entities.Result) error {
+	data, err := w.render(res)
+	if err != nil {
+		return err
+	}
+
+	if w.path != "" {
+		if err = atomicfile.WriteFile(w.path, data, outputFilePerm); err != nil {
+			return fmt.Errorf("failed to write output file %s: %w", w.path, err)
+		}
+
+		return nil
+	}
+
+	_, err = w.stdout.Write(data)
+
+	return err
+}
+
+func (w *resultWriter) render(res entities.Result) ([]byte, error) {
+	if w.format == outputFormatJSON {
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	}
+
+	return []byte(res.Result + "\n"), nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+func TestResultWriter(t *testing.T) {
+	t.Parallel()
+
+	res := entities.NewResult("explain", entities.Completion{
+		Text:    "explanation",
+		Model:   "gpt-5-nano",
+		Backend: "work",
+		Usage:   entities.Usage{InputTokens: 3, OutputTokens: 2, TotalTokens: 5},
+	}, 1500*time.Millisecond)
+	res.FilesProcessed = 2
+
+	t.Run("text to stdout", func(t *testing.T) {
+		t.Parallel()
+
+		rw, stdout := newTestResultWriter(t)
+
+		require.NoError(t, rw.write(res))
+		assert.Equal(t, "explanation\n", stdout.String())
+	})
+
+	t.Run("json to stdout", func(t *testing.T) {
+		t.Parallel()
+
+		rw, stdout := newTestResultWriter(t, "--format", "json")
+
+		require.NoError(t, rw.write(res))
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		assert.Equal(t, map[string]any{
+			"version":         float64(entities.ResultVersion),
+			"command":         "explain",
+			"result":          "explanation",
+			"model":           "gpt-5-nano",
+			"backend":         "work",
+			"usage":           map[string]any{"input_tokens": float64(3), "output_tokens": float64(2), "total_tokens": float64(5)},
+			"files_processed": float64(2),
+			"skipped":         []any{},
+			"duration_ms":     float64(1500),
+			"warnings":        []any{},
+		}, got)
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "result.txt")
+		rw, stdout := newTestResultWriter(t, "--output", path)
+
+		require.NoError(t, rw.write(res))
+		assert.Empty(t, stdout.String())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "explanation\n", string(data))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &cobra.Command{}
+		addOutputFlags(cmd)
+		require.NoError(t, cmd.ParseFlags([]string{"--format", "yaml"}))
+
+		_, err := resultWriterFromFlags(cmd)
+		require.ErrorContains(t, err, `unsupported --format "yaml"`)
+	})
+}
+
+func newTestResultWriter(t *testing.T, args ...string) (*resultWriter, *bytes.Buffer) {
+	t.Helper()
+
+	stdout := &bytes.Buffer{}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(stdout)
+	addOutputFlags(cmd)
+	require.NoError(t, cmd.ParseFlags(args))
+
+	rw, err := resultWriterFromFlags(cmd)
+	require.NoError(t, err)
+
+	return rw, stdout
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/agent/commit"
+	"github.com/yaroslav-koval/hange/domain/agent/explain"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/git"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+	"github.com/yaroslav-koval/hange/pkg/plugin"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage plugins, " + plugin.Prefix + "<name> executables on PATH run as hange <name>",
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plugins found on PATH",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := cmd.Root()
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+		for _, p := range plugin.Discover(os.Getenv("PATH")) {
+			for _, path := range p.Shadowed {
+				slog.Warn("Plugin is shadowed by one earlier on PATH", slog.String("plugin", p.Name),
+					slog.String(logging.KeyFile, path), slog.String("used", p.Path))
+			}
+
+			if c := builtinCommand(root, p.Name); c != nil && c.Annotations[annotationPlugin] == "" {
+				slog.Warn("Plugin is ignored, it has a name of a built-in command", slog.String("plugin", p.Name),
+					slog.String(logging.KeyFile, p.Path))
+
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Path); err != nil {
+				return err
+			}
+		}
+
+		return w.Flush()
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+// annotationPlugin marks commands of plugins, its value is a path of the executable
+const annotationPlugin = "hange_plugin"
+
+// addPluginCommands adds a command of every plugin which doesn't shadow a built-in one
+func addPluginCommands(root *cobra.Command, plugins []plugin.Plugin) {
+	for _, p := range plugins {
+		if builtinCommand(root, p.Name) != nil {
+			slog.Debug("Plugin is ignored, it has a name of a built-in command", slog.String("plugin", p.Name))
+			continue
+		}
+
+		root.AddCommand(newPluginCommand(p))
+	}
+}
+
+// builtinCommand returns a command of the name or the alias. Help and completion commands are added by cobra
+// on execution, so they are reserved too.
+func builtinCommand(root *cobra.Command, name string) *cobra.Command {
+	if name == "help" || name == "completion" {
+		return root
+	}
+
+	for _, c := range root.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func newPluginCommand(p plugin.Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:         p.Name,
+		Short:       "Plugin " + p.Path,
+		Annotations: map[string]string{annotationPlugin: p.Path},
+		// global flags go before the name of the plugin, all the arguments after it are of the plugin
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			hs, err := pluginHandshake(cmd, app, p)
+			if err != nil {
+				return err
+			}
+
+			token := ""
+			if !hs.DryRun {
+				token = pluginToken(app, p)
+			}
+
+			err = plugin.Run(cmd.Context(), p, args, hs, token, plugin.Stdio{
+				In:  cmd.InOrStdin(),
+				Out: cmd.OutOrStdout(),
+				Err: cmd.ErrOrStderr(),
+			})
+			if exitCode(err) > 0 {
+				// the plugin has reported its error, hange only exits with its code
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+			}
+
+			return err
+		},
+	}
+}
+
+// pluginHandshake resolves the config, the profile and the models of the run for the plugin
+func pluginHandshake(cmd *cobra.Command, app factory.AppBuilder, p plugin.Plugin) (plugin.Handshake, error) {
+	cfg, err := app.GetConfigurator()
+	if err != nil {
+		return plugin.Handshake{}, err
+	}
+
+	pr, err := app.GetProfile()
+	if err != nil {
+		return plugin.Handshake{}, err
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return plugin.Handshake{}, err
+	}
+
+	repoRoot, err := git.FindRoot(workDir)
+	if err != nil {
+		return plugin.Handshake{}, err
+	}
+
+	verbose, err := cmd.Root().PersistentFlags().GetBool(flagKeyVerbose)
+	if err != nil {
+		return plugin.Handshake{}, err
+	}
+
+	dryRun, err := cmd.Root().PersistentFlags().GetBool(flagKeyDryRun)
+	if err != nil {
+		return plugin.Handshake{}, err
+	}
+
+	return plugin.Handshake{
+		Version:      plugin.HandshakeVersion,
+		HangeVersion: buildVersion(),
+		Plugin:       p.Name,
+		WorkDir:      workDir,
+		RepoRoot:     repoRoot,
+		ConfigPath:   cfg.Path(),
+		Profile:      pluginProfile(pr),
+		DryRun:       dryRun,
+		Verbose:      verbose,
+	}, nil
+}
+
+func pluginProfile(p profile.Profile) plugin.Profile {
+	res := plugin.Profile{
+		Name:     p.Name,
+		Provider: p.Provider,
+		BaseURL:  p.BaseURL,
+		Models: plugin.Models{
+			Commit:  p.Models.Commit,
+			Explain: p.Models.Explain,
+		},
+	}
+
+	if res.Provider == "" {
+		res.Provider = profile.ProviderOpenAI
+	}
+
+	if res.Models.Commit == "" {
+		res.Models.Commit = commit.DefaultModel
+	}
+
+	if res.Models.Explain == "" {
+		res.Models.Explain = explain.DefaultModel
+	}
+
+	return res
+}
+
+// pluginToken returns the token of the profile if the plugin is listed by plugins.token, so a token command
+// or a passphrase prompt runs only for plugins which need the token. A missing token is not an error,
+// the plugin runs without it.
+func pluginToken(app factory.AppBuilder, p plugin.Plugin) string {
+	cfg, err := app.GetConfigurator()
+	if err != nil {
+		slog.Debug("Plugin runs without a token", logging.Err(err))
+		return ""
+	}
+
+	names, err := config.ReadStringSlice(cfg, consts.PluginsTokenPath)
+	if err != nil {
+		slog.Warn("Plugin runs without a token", logging.Err(err))
+		return ""
+	}
+
+	if !slices.Contains(names, p.Name) {
+		slog.Debug("Plugin runs without a token, it is not listed by "+consts.PluginsTokenPath,
+			slog.String("plugin", p.Name))
+
+		return ""
+	}
+
+	au, err := app.GetAuth()
+	if err != nil {
+		slog.Debug("Plugin runs without a token", logging.Err(err))
+		return ""
+	}
+
+	token, err := au.GetToken()
+	if err != nil {
+		slog.Debug("Plugin runs without a token", logging.Err(err))
+		return ""
+	}
+
+	return token
+}
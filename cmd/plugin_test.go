@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/commit"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	auth_mock "github.com/yaroslav-koval/hange/mocks/auth"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+	"github.com/yaroslav-koval/hange/pkg/plugin"
+)
+
+func TestAddPluginCommandsKeepsBuiltins(t *testing.T) {
+	root := &cobra.Command{Use: "hange"}
+	root.AddCommand(&cobra.Command{Use: "version"}, &cobra.Command{Use: "explain", Aliases: []string{"ex"}})
+
+	addPluginCommands(root, []plugin.Plugin{
+		{Name: "completion", Path: "/bin/hange-completion"},
+		{Name: "deploy", Path: "/bin/hange-deploy"},
+		{Name: "ex", Path: "/bin/hange-ex"},
+		{Name: "version", Path: "/bin/hange-version"},
+	})
+
+	names := make([]string, 0, len(root.Commands()))
+	for _, c := range root.Commands() {
+		names = append(names, c.Name())
+	}
+
+	assert.Equal(t, []string{"deploy", "explain", "version"}, names)
+
+	deploy, _, err := root.Find([]string{"deploy", "--help", "-v"})
+	require.NoError(t, err)
+	assert.True(t, deploy.DisableFlagParsing, "flags after the name are of the plugin")
+	assert.Equal(t, "/bin/hange-deploy", deploy.Annotations[annotationPlugin])
+}
+
+func TestPluginProfileAppliesDefaults(t *testing.T) {
+	got := pluginProfile(profile.Profile{
+		Name:         "work",
+		BaseURL:      "http://localhost:11434/v1",
+		TokenCommand: "op read op://work/token",
+		Models:       profile.Models{Explain: "llama3"},
+	})
+
+	assert.Equal(t, plugin.Profile{
+		Name:     "work",
+		Provider: profile.ProviderOpenAI,
+		BaseURL:  "http://localhost:11434/v1",
+		Models:   plugin.Models{Commit: commit.DefaultModel, Explain: "llama3"},
+	}, got)
+}
+
+func TestPluginTokenOnlyForListedPlugins(t *testing.T) {
+	cfg := configurator_mock.NewMockConfigurator(t)
+	cfg.EXPECT().ReadField(consts.PluginsTokenPath).Return([]any{"deploy"})
+
+	au := auth_mock.NewMockAuth(t)
+	au.EXPECT().GetToken().Return("sk-test", nil).Once()
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetConfigurator().Return(cfg, nil)
+	app.EXPECT().GetAuth().Return(au, nil).Once()
+
+	// the token of the profile isn't fetched for a plugin which doesn't need it
+	assert.Empty(t, pluginToken(app, plugin.Plugin{Name: "lint"}))
+	assert.Equal(t, "sk-test", pluginToken(app, plugin.Plugin{Name: "deploy"}))
+}
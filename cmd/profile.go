@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+const (
+	flagKeyProvider            = "provider"
+	flagKeyBaseURL             = "base-url"
+	flagKeyTokenCommand        = "token-command"
+	flagKeyTokenCommandTimeout = "token-command-timeout"
+	flagKeyCommitModel         = "commit-model"
+	flagKeyExplainModel        = "explain-model"
+	flagKeyCommitPrompt        = "commit-prompt"
+	flagKeyExplainPrompt       = "explain-prompt"
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles of accounts and providers",
+	Long: `Manage profiles of accounts and providers.
+Each profile has its own token, provider, base URL, models and prompts.
+The active profile is used unless --profile or HANGE_PROFILE selects another one.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles, the active one is marked with '*'",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pm, err := profileManagerFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		profiles, err := pm.List()
+		if err != nil {
+			return err
+		}
+
+		active := pm.Active()
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+		for _, p := range profiles {
+			mark := " "
+			if p.Name == active {
+				mark = "*"
+			}
+
+			provider := p.Provider
+			if provider == "" {
+				provider = profile.ProviderOpenAI
+			}
+
+			if _, err = fmt.Fprintf(w, "%s %s\t%s\t%s\n", mark, p.Name, provider, p.BaseURL); err != nil {
+				return err
+			}
+		}
+
+		return w.Flush()
+	},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a profile",
+	Example: `hange profile add work --token-command "op read op://work/openai/token"
+hange profile add local --base-url http://localhost:11434/v1 --commit-model llama3.1
+hange --profile work auth "token-value"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := profileFromFlags(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		pm, err := profileManagerFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err = pm.Add(p); err != nil {
+			return err
+		}
+
+		slog.Info("Profile is added", slog.String(logging.KeyProfile, p.Name))
+
+		return nil
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a profile active",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pm, err := profileManagerFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err = pm.Use(args[0]); err != nil {
+			return err
+		}
+
+		slog.Info("Profile is active", slog.String(logging.KeyProfile, args[0]))
+
+		return nil
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a profile with its stored token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pm, err := profileManagerFromContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err = pm.Remove(args[0]); err != nil {
+			return err
+		}
+
+		slog.Info("Profile is removed", slog.String(logging.KeyProfile, args[0]))
+
+		return nil
+	},
+}
+
+func profileManagerFromContext(cmd *cobra.Command) (profile.ProfileManager, error) {
+	app, err := appFromContext(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return app.GetProfileManager()
+}
+
+func profileFromFlags(cmd *cobra.Command, name string) (profile.Profile, error) {
+	p := profile.Profile{Name: name}
+
+	fields := map[string]*string{
+		flagKeyProvider:            &p.Provider,
+		flagKeyBaseURL:             &p.BaseURL,
+		flagKeyTokenCommand:        &p.TokenCommand,
+		flagKeyTokenCommandTimeout: &p.TokenCommandTimeout,
+		flagKeyCommitModel:         &p.Models.Commit,
+		flagKeyExplainModel:        &p.Models.Explain,
+		flagKeyCommitPrompt:        &p.Prompts.Commit,
+		flagKeyExplainPrompt:       &p.Prompts.Explain,
+	}
+
+	for flag, dst := range fields {
+		v, err := cmd.Flags().GetString(flag)
+		if err != nil {
+			return profile.Profile{}, err
+		}
+
+		*dst = v
+	}
+
+	return p, nil
+}
+
+func addProfileFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagKeyProvider, "", fmt.Sprintf("LLM provider, one of %v (default openai)", profile.Providers))
+	cmd.Flags().String(flagKeyBaseURL, "", "base URL of an OpenAI compatible API, e.g. a local model")
+	cmd.Flags().String(flagKeyTokenCommand, "", "command printing the token, replaces the stored token")
+	cmd.Flags().String(flagKeyTokenCommandTimeout, "", "timeout of the token command, e.g. 30s")
+	cmd.Flags().String(flagKeyCommitModel, "", "model of the commit-msg command")
+	cmd.Flags().String(flagKeyExplainModel, "", "model of the explain command")
+	cmd.Flags().String(flagKeyCommitPrompt, "", "system prompt of the commit-msg command")
+	cmd.Flags().String(flagKeyExplainPrompt, "", "system prompt of the explain command")
+}
+
+func init() {
+	addProfileFlags(profileAddCmd)
+
+	profileCmd.AddCommand(profileListCmd, profileAddCmd, profileUseCmd, profileRemoveCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	profilemanager_mock "github.com/yaroslav-koval/hange/mocks/profilemanager"
+)
+
+func TestProfileListMarksActiveProfile(t *testing.T) {
+	pm := profilemanager_mock.NewMockProfileManager(t)
+	pm.EXPECT().List().Return([]profile.Profile{
+		{Name: profile.DefaultName},
+		{Name: "local", BaseURL: "http://localhost:11434/v1"},
+	}, nil)
+	pm.EXPECT().Active().Return("local")
+
+	cmd := newProfileTestCommand(t, pm, profileListCmd)
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, cmd.RunE(cmd, nil))
+	require.Equal(t, "  default  openai  \n* local    openai  http://localhost:11434/v1\n", out.String())
+}
+
+func TestProfileAddReadsFlags(t *testing.T) {
+	pm := profilemanager_mock.NewMockProfileManager(t)
+	pm.EXPECT().Add(profile.Profile{
+		Name:         "work",
+		BaseURL:      "https://llm.example.com/v1",
+		TokenCommand: "pass show work",
+		Models:       profile.Models{Commit: "gpt-5-mini"},
+		Prompts:      profile.Prompts{Explain: "Be brief."},
+	}).Return(nil)
+
+	cmd := newProfileTestCommand(t, pm, profileAddCmd)
+	addProfileFlags(cmd)
+
+	require.NoError(t, cmd.ParseFlags([]string{
+		"--" + flagKeyBaseURL, "https://llm.example.com/v1",
+		"--" + flagKeyTokenCommand, "pass show work",
+		"--" + flagKeyCommitModel, "gpt-5-mini",
+		"--" + flagKeyExplainPrompt, "Be brief.",
+	}))
+
+	require.NoError(t, cmd.RunE(cmd, []string{"work"}))
+}
+
+func TestProfileUseAndRemovePropagateErrors(t *testing.T) {
+	pm := profilemanager_mock.NewMockProfileManager(t)
+	pm.EXPECT().Use("missing").Return(profile.ErrProfileNotFound)
+	pm.EXPECT().Remove(profile.DefaultName).Return(profile.ErrDefaultProfile)
+
+	useCmd := newProfileTestCommand(t, pm, profileUseCmd)
+	require.ErrorIs(t, useCmd.RunE(useCmd, []string{"missing"}), profile.ErrProfileNotFound)
+
+	removeCmd := newProfileTestCommand(t, pm, profileRemoveCmd)
+	require.ErrorIs(t, removeCmd.RunE(removeCmd, []string{profile.DefaultName}), profile.ErrDefaultProfile)
+}
+
+func TestProfileCommandPropagatesManagerError(t *testing.T) {
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	managerErr := errors.New("broken config")
+	app.EXPECT().GetProfileManager().Return(nil, managerErr)
+
+	cmd := &cobra.Command{RunE: profileListCmd.RunE}
+	cmd.SetContext(appToContext(context.Background(), app))
+
+	require.ErrorIs(t, cmd.RunE(cmd, nil), managerErr)
+}
+
+// newProfileTestCommand creates a command running the source, so tests don't share flag values.
+func newProfileTestCommand(t *testing.T, pm profile.ProfileManager, source *cobra.Command) *cobra.Command {
+	t.Helper()
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetProfileManager().Return(pm, nil)
+
+	cmd := &cobra.Command{RunE: source.RunE}
+	cmd.SetContext(appToContext(context.Background(), app))
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+	"golang.org/x/term"
+)
+
+const flagKeyNoProgress = "no-progress"
+
+// progressFromFlags creates a reporter printing to stderr, a live bar is drawn if stderr is a terminal.
+// The returned function stops printing.
+func progressFromFlags(cmd *cobra.Command) (progress.Reporter, func(), error) {
+	noProgress, err := cmd.Flags().GetBool(flagKeyNoProgress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if noProgress {
+		return progress.Discard, func() {}, nil
+	}
+
+	w := cmd.ErrOrStderr()
+
+	f, ok := w.(*os.File)
+	tty := ok && term.IsTerminal(int(f.Fd()))
+
+	p := progress.NewPrinter(w, tty)
+
+	return p, p.Close, nil
+}
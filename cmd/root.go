@@ -2,27 +2,36 @@ package cmd
 
 import (
 	_ "embed"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/yaroslav-koval/hange/domain/factory"
 	"github.com/yaroslav-koval/hange/domain/factory/agentfactory"
 	"github.com/yaroslav-koval/hange/domain/factory/appfactory"
 	"github.com/yaroslav-koval/hange/pkg/envs"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+	"github.com/yaroslav-koval/hange/pkg/plugin"
 )
 
 const (
 	flagKeyVerbose    = "verbose"
 	flagKeyConfigPath = "config"
+	flagKeyProfile    = "profile"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "hange",
-	Short: "A reliable CLI soldier to perform routine tasks",
+	Use: "hange",
+	// global flags before the name of a plugin are parsed by hange, the rest of the arguments go to the plugin
+	TraverseChildren: true,
+	Short:            "A reliable CLI soldier to perform routine tasks",
 	Long: `A reliable CLI soldier to perform developer's routine tasks.
 It likes to explain code, write documentation and just chat.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
@@ -31,8 +40,8 @@ It likes to explain code, write documentation and just chat.`,
 			return err
 		}
 
-		if isVerbose {
-			slog.SetLogLoggerLevel(slog.LevelDebug)
+		if err = setupLogging(cmd, isVerbose); err != nil {
+			return err
 		}
 
 		ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
@@ -45,15 +54,20 @@ It likes to explain code, write documentation and just chat.`,
 
 		cmd.SetContext(ctx)
 
-		cfgPath, err := cmd.Flags().GetString(flagKeyConfigPath)
+		// subcommands may have a local --dry-run with their own meaning, it shadows the global one
+		dryRun, err := cmd.Root().PersistentFlags().GetBool(flagKeyDryRun)
 		if err != nil {
 			return err
 		}
 
-		cliFactory := appfactory.NewCLIFactory(cfgPath)
-		openAIFactory := agentfactory.NewOpenAIFactory()
+		// --offline is a flag of the commands which can work without the provider
+		f := cmd.Flags().Lookup(flagKeyOffline)
+		offline := f != nil && f.Value.String() == "true"
 
-		app := factory.NewAppBuilder(cliFactory, openAIFactory)
+		app, err := newAppBuilder(cmd, "", offline, dryRun)
+		if err != nil {
+			return err
+		}
 
 		ctx = appToContext(cmd.Context(), app)
 		cmd.SetContext(ctx)
@@ -62,20 +76,80 @@ It likes to explain code, write documentation and just chat.`,
 	},
 }
 
+// newAppBuilder wires the application of the config and the profile of the flags. The repository config and git
+// commands are of workDir, the working directory if it is empty. A payload of the dry run is printed to stdout.
+func newAppBuilder(cmd *cobra.Command, workDir string, offline, dryRun bool) (factory.AppBuilder, error) {
+	cfgPath, err := cmd.Flags().GetString(flagKeyConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	profileName, err := cmd.Flags().GetString(flagKeyProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	cliFactory := appfactory.NewCLIFactory(cfgPath, profileName, workDir)
+	agentFactory := agentfactory.NewOpenAIFactory()
+
+	if offline {
+		agentFactory = agentfactory.NewOfflineFactory(agentFactory)
+	}
+
+	if dryRun {
+		agentFactory = agentfactory.NewDryRunFactory(cmd.OutOrStdout(), agentFactory)
+	}
+
+	return factory.NewAppBuilder(cliFactory, agentFactory), nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	start := time.Now()
+
+	addPluginCommands(rootCmd, plugin.Discover(os.Getenv("PATH")))
+
 	err := rootCmd.Execute()
+
+	attrs := []any{slog.Duration(logging.KeyDuration, time.Since(start))}
 	if err != nil {
+		attrs = append(attrs, logging.Err(err))
+	}
+
+	slog.Debug("Command is finished", attrs...)
+
+	_ = closeLog()
+
+	if code := exitCode(err); code > 0 {
+		os.Exit(code)
+	} else if err != nil {
 		os.Exit(1)
 	}
 }
 
+// exitCode returns an exit code of a plugin which failed, it is 0 for other errors
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return 0
+}
+
 func GetRootCmd() *cobra.Command {
 	return rootCmd
 }
 
 func init() {
-	rootCmd.PersistentFlags().String(flagKeyConfigPath, os.Getenv(envs.EnvHangeConfigPath), "config file (default is $HOME/.hange.yaml)")
+	rootCmd.PersistentFlags().String(flagKeyConfigPath, os.Getenv(envs.EnvHangeConfigPath), "config file (default is $HOME/.hange/config)")
+	rootCmd.PersistentFlags().String(flagKeyProfile, os.Getenv(envs.EnvHangeProfile),
+		"profile to use instead of the active one (env "+envs.EnvHangeProfile+")")
 	rootCmd.PersistentFlags().BoolP(flagKeyVerbose, "v", false, "verbose logging")
+	rootCmd.PersistentFlags().String(flagKeyLogFormat, logging.FormatText,
+		fmt.Sprintf("format of logs written to stderr, one of %v", logging.Formats))
+	rootCmd.PersistentFlags().String(flagKeyLogFile, "", "append logs to the file instead of stderr")
+	rootCmd.PersistentFlags().Bool(flagKeyDryRun, false,
+		"print what would be sent to the provider with estimated tokens and cost, without sending it")
 }
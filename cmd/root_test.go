@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -10,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/pkg/logging"
 	"golang.org/x/sys/unix"
 )
 
@@ -61,7 +65,11 @@ func TestRootPersistentPreRunEHandlesCancellation(t *testing.T) {
 
 	cmd := &cobra.Command{}
 	cmd.Flags().String(flagKeyConfigPath, cfgPath, "config")
+	cmd.Flags().String(flagKeyProfile, "", "profile")
 	cmd.Flags().BoolP(flagKeyVerbose, "v", false, "verbose logging")
+	cmd.Flags().String(flagKeyLogFormat, "text", "log format")
+	cmd.Flags().String(flagKeyLogFile, "", "log file")
+	cmd.PersistentFlags().Bool(flagKeyDryRun, false, "dry run")
 	cmd.SetContext(context.Background())
 
 	err := rootCmd.PersistentPreRunE(cmd, nil)
@@ -88,3 +96,29 @@ func TestRootPersistentPreRunEHandlesCancellation(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, app)
 }
+
+func TestSetupLogging(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	stderr := &bytes.Buffer{}
+
+	cmd := &cobra.Command{Use: "explain"}
+	cmd.Flags().String(flagKeyLogFormat, "json", "log format")
+	cmd.Flags().String(flagKeyLogFile, "", "log file")
+	cmd.SetErr(stderr)
+
+	require.NoError(t, setupLogging(cmd, false))
+
+	slog.Debug("hidden")
+	slog.Info("Vector store is created", slog.String(logging.KeyVectorStoreID, "vs_1"))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(stderr.Bytes(), &record))
+	require.Equal(t, "explain", record[logging.KeyCommand])
+	require.Equal(t, "vs_1", record[logging.KeyVectorStoreID])
+	require.Len(t, record[logging.KeyRunID], 16)
+
+	require.NoError(t, cmd.Flags().Set(flagKeyLogFormat, "yaml"))
+	require.ErrorIs(t, setupLogging(cmd, false), logging.ErrUnsupportedFormat)
+}
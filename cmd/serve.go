@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/archiveprovider"
+	"github.com/yaroslav-koval/hange/domain/git"
+	"github.com/yaroslav-koval/hange/pkg/envs"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+const (
+	flagKeyListen    = "listen"
+	flagKeyTokenFile = "token-file"
+)
+
+const (
+	defaultListen = "127.0.0.1:7373"
+	// serveTokenFile is written next to the config file unless --token-file is set
+	serveTokenFile = "serve.token"
+	unixPrefix     = "unix:"
+
+	maxRequestSize  = 1 << 20 // 1 MiB
+	shutdownTimeout = 10 * time.Second
+)
+
+var errServeDryRun = errors.New("--dry-run is not supported by serve, payloads can't be returned to clients")
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve hange to editors over a local HTTP API",
+	Long: `Serve hange over a local HTTP API with JSON requests and responses. Endpoints:
+  POST /v1/commit-message  {"repo": "/abs/dir", "context": "JIRA-1", "offline": false}
+  POST /v1/explain         {"repo": "/abs/dir", "paths": ["cmd"], "include": [], "exclude": [], "persistent": true}
+  GET  /v1/health
+Responses are the JSON results of --format json, errors are {"error": "..."}.
+"repo" is any directory of a git repository, paths are relative to its root. Every request but health needs
+the header "Authorization: Bearer <token>". The token is $` + envs.EnvHangeServeToken + ` or a random one written
+to --token-file (default serve.token next to the config) with permissions 0600 on every start.
+--listen is host:port or unix:/path/to/socket. A request is cancelled when its client disconnects.
+Applications are kept per repository root, so configs and agents are wired once. Requests to one repository
+run one at a time. An application is wired again after a failed request, restart the server for config changes.`,
+	Example: `hange serve
+hange serve --listen 127.0.0.1:9000
+hange serve --listen unix:/tmp/hange.sock --token-file /tmp/hange.token
+curl -H "Authorization: Bearer $(cat ~/.hange/serve.token)" -d '{"repo": "'$PWD'"}' \
+  http://127.0.0.1:7373/v1/commit-message`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, err := cmd.Root().PersistentFlags().GetBool(flagKeyDryRun)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errServeDryRun
+		}
+
+		addr, err := cmd.Flags().GetString(flagKeyListen)
+		if err != nil {
+			return err
+		}
+
+		tokenFile, err := serveTokenFileFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		token, err := serveToken(tokenFile)
+		if err != nil {
+			return err
+		}
+
+		ln, err := listen(addr)
+		if err != nil {
+			return err
+		}
+
+		api := newServeAPI(token, func(root string, offline bool) (factory.AppBuilder, error) {
+			return newAppBuilder(cmd, root, offline, false)
+		})
+
+		ctx := cmd.Context()
+
+		srv := &http.Server{
+			Handler:           api.handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+			// runs of requests are cancelled on termination, so the shutdown doesn't wait for the provider
+			BaseContext: func(net.Listener) context.Context { return ctx },
+		}
+
+		go func() {
+			<-ctx.Done()
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+
+		slog.Info("Serving HTTP API", slog.String("address", ln.Addr().String()),
+			slog.String(logging.KeyFile, tokenFile))
+
+		err = srv.Serve(ln)
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		return err
+	},
+}
+
+func init() {
+	serveCmd.Flags().String(flagKeyListen, defaultListen, "address to listen on: host:port or unix:/path/to/socket")
+	serveCmd.Flags().String(flagKeyTokenFile, "", "file the token is written to (default serve.token next to the config)")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func serveTokenFileFromFlags(cmd *cobra.Command) (string, error) {
+	tokenFile, err := cmd.Flags().GetString(flagKeyTokenFile)
+	if err != nil || tokenFile != "" {
+		return tokenFile, err
+	}
+
+	app, err := appFromContext(cmd.Context())
+	if err != nil {
+		return "", err
+	}
+
+	c, err := app.GetConfigurator()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(c.Path()), serveTokenFile), nil
+}
+
+// serveToken returns the token of the environment variable. Otherwise a random token is written to the file,
+// so only the user can read it.
+func serveToken(file string) (string, error) {
+	if token := os.Getenv(envs.EnvHangeServeToken); token != "" {
+		return token, nil
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(b)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(file, []byte(token+"\n"), 0o600); err != nil {
+		return "", err
+	}
+
+	// WriteFile keeps permissions of an existing file
+	if err := os.Chmod(file, 0o600); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// listen listens on a TCP address or on a unix socket with the "unix:" prefix. A socket left by a previous run
+// is replaced.
+func listen(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, unixPrefix)
+	if !ok {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", flagKeyListen, err)
+		}
+
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			slog.Warn("HTTP API is reachable from other hosts, only the token protects it",
+				slog.String("address", addr))
+		}
+
+		return net.Listen("tcp", addr)
+	}
+
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err = os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+
+	return ln, nil
+}
+
+var (
+	errServeBadRequest  = errors.New("bad request")
+	errServeNoRepo      = errors.New("repo must be an absolute path of a directory in a git repository")
+	errServeOutsideRepo = errors.New("path is outside of the repository")
+	errServeStdin       = fmt.Errorf("%q can't be explained over the HTTP API", archiveprovider.StdinPath)
+)
+
+// repoAppFunc wires an application of the repository root
+type repoAppFunc func(root string, offline bool) (factory.AppBuilder, error)
+
+type repoAppKey struct {
+	root    string
+	offline bool
+}
+
+// repoApp is a long-lived application of a repository. The agent keeps the state of a run,
+// so runs are serialized by the lock.
+type repoApp struct {
+	app  factory.AppBuilder
+	lock chan struct{}
+}
+
+// serveAPI handles requests of the HTTP API with applications shared by requests to the same repository
+type serveAPI struct {
+	token  []byte
+	newApp repoAppFunc
+
+	mutex *sync.Mutex
+	apps  map[repoAppKey]*repoApp
+}
+
+func newServeAPI(token string, newApp repoAppFunc) *serveAPI {
+	return &serveAPI{
+		token:  []byte(token),
+		newApp: newApp,
+		mutex:  &sync.Mutex{},
+		apps:   map[repoAppKey]*repoApp{},
+	}
+}
+
+func (a *serveAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", a.health)
+	mux.HandleFunc("POST /v1/commit-message", a.authorized(a.commitMessage))
+	mux.HandleFunc("POST /v1/explain", a.authorized(a.explain))
+
+	return logRequests(mux)
+}
+
+func (a *serveAPI) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), a.token) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, apiError{Error: "invalid or missing token"})
+
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (a *serveAPI) health(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": buildVersion()})
+}
+
+func (a *serveAPI) commitMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Repo    string `json:"repo"`
+		Context string `json:"context"`
+		Offline bool   `json:"offline"`
+	}
+	if err := decodeRequest(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	start := time.Now()
+
+	var userInput []string
+	if req.Context != "" {
+		userInput = []string{req.Context}
+	}
+
+	var res entities.Result
+
+	err := a.run(r.Context(), req.Repo, req.Offline, func(app factory.AppBuilder, _ string) error {
+		c, err := generateCommitMessage(r.Context(), app, userInput)
+		if err != nil {
+			return err
+		}
+
+		res = entities.NewResult(commitMsgCmd.Name(), c, time.Since(start))
+
+		return nil
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (a *serveAPI) explain(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Repo       string   `json:"repo"`
+		Paths      []string `json:"paths"`
+		Include    []string `json:"include"`
+		Exclude    []string `json:"exclude"`
+		Persistent *bool    `json:"persistent"`
+	}
+	if err := decodeRequest(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	start := time.Now()
+
+	var res entities.Result
+
+	err := a.run(r.Context(), req.Repo, false, func(app factory.AppBuilder, root string) error {
+		paths, err := repoPaths(root, req.Paths)
+		if err != nil {
+			return err
+		}
+
+		ep, err := newDefaultExplainProcessor(app, root, req.Include, req.Exclude, req.Persistent)
+		if err != nil {
+			return err
+		}
+
+		if err = ep.validateArgs(paths); err != nil {
+			return fmt.Errorf("%w: %w", errServeBadRequest, err)
+		}
+
+		c, err := ep.processExplanation(r.Context(), paths)
+		if err != nil {
+			return err
+		}
+
+		res = ep.result(explainCmd.Name(), c, time.Since(start))
+
+		for i, f := range res.Skipped {
+			if rel, err := filepath.Rel(root, f.Path); err == nil {
+				res.Skipped[i].Path = filepath.ToSlash(rel)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// repoPaths resolves paths relative to the root. The server doesn't change its working directory,
+// so the paths are absolute.
+func repoPaths(root string, paths []string) ([]string, error) {
+	resolved := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		if p == archiveprovider.StdinPath {
+			return nil, fmt.Errorf("%w: %w", errServeBadRequest, errServeStdin)
+		}
+
+		if p == "" {
+			resolved = append(resolved, p)
+			continue
+		}
+
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(root, p)
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil || !filepath.IsLocal(rel) && rel != "." {
+			return nil, fmt.Errorf("%w: %w: %s", errServeBadRequest, errServeOutsideRepo, p)
+		}
+
+		resolved = append(resolved, filepath.Clean(p))
+	}
+
+	return resolved, nil
+}
+
+// run runs fn with the application of the repository containing dir. An application is dropped after
+// an internal error, so the next request wires it again instead of getting the cached error.
+func (a *serveAPI) run(
+	ctx context.Context, dir string, offline bool, fn func(app factory.AppBuilder, root string) error,
+) error {
+	if !filepath.IsAbs(dir) {
+		return fmt.Errorf("%w: %w", errServeBadRequest, errServeNoRepo)
+	}
+
+	root, err := git.FindRoot(dir)
+	if err != nil || root == "" {
+		return fmt.Errorf("%w: %w", errServeBadRequest, errServeNoRepo)
+	}
+
+	key := repoAppKey{root: root, offline: offline}
+
+	ra, err := a.repoApp(key)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case ra.lock <- struct{}{}:
+	}
+
+	defer func() { <-ra.lock }()
+
+	err = fn(ra.app, root)
+	if err != nil && ctx.Err() == nil && serveStatus(err) == http.StatusInternalServerError {
+		a.drop(key, ra)
+	}
+
+	return err
+}
+
+func (a *serveAPI) repoApp(key repoAppKey) (*repoApp, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if ra, ok := a.apps[key]; ok {
+		return ra, nil
+	}
+
+	app, err := a.newApp(key.root, key.offline)
+	if err != nil {
+		return nil, err
+	}
+
+	ra := &repoApp{app: app, lock: make(chan struct{}, 1)}
+	a.apps[key] = ra
+
+	slog.Debug("Application of repository is wired", slog.String("repo", key.root))
+
+	return ra, nil
+}
+
+func (a *serveAPI) drop(key repoAppKey, ra *repoApp) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	// a concurrent request may have replaced it already
+	if a.apps[key] == ra {
+		delete(a.apps, key)
+	}
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) error {
+	d := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	d.DisallowUnknownFields()
+
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", errServeBadRequest, err)
+	}
+
+	return nil
+}
+
+// serveStatus maps an error of a run to a status. Errors of the input are the client's fault.
+func serveStatus(err error) int {
+	switch {
+	case errors.Is(err, errServeBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, agent.ErrNoStatusProvided), errors.Is(err, agent.ErrProvidedEmptyInput):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	// the client is gone, there is nobody to answer
+	if r.Context().Err() != nil {
+		return
+	}
+
+	writeJSON(w, serveStatus(err), apiError{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("Failed to write response", logging.Err(err))
+	}
+}
+
+// statusRecorder remembers the status of the response for the log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		slog.Info("Request is served", slog.String("method", r.Method), slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status), slog.Duration(logging.KeyDuration, time.Since(start)),
+			slog.Bool("cancelled", r.Context().Err() != nil))
+	})
+}
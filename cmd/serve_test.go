@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	aiagent_mock "github.com/yaroslav-koval/hange/mocks/aiagent"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	changesprovider_mock "github.com/yaroslav-koval/hange/mocks/changesprovider"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+	fileprovider_mock "github.com/yaroslav-koval/hange/mocks/fileprovider"
+)
+
+const testServeToken = "secret"
+
+func TestServeAPI(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o700))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "cmd"), 0o700))
+
+	git := changesprovider_mock.NewMockChangesProvider(t)
+	git.EXPECT().Status(mock.Anything).Return("M main.go", nil)
+	git.EXPECT().StagedStatus(mock.Anything).Return("M main.go", nil)
+	git.EXPECT().StagedDiff(mock.Anything, 30).Return("+func main() {}", nil)
+
+	c := configurator_mock.NewMockConfigurator(t)
+	c.EXPECT().ReadField("ignore").Return(nil)
+
+	cmdDir := filepath.Join(root, "cmd")
+
+	fp := fileprovider_mock.NewMockFileProvider(t)
+	fp.EXPECT().GetAllFileNames(mock.Anything, mock.Anything, []string{cmdDir}).
+		Return([]string{filepath.Join(cmdDir, "root.go"), filepath.Join(cmdDir, "logo.png")}, nil)
+	fp.EXPECT().ReadFiles(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, cfg fileprovider.Config, _ []string) (<-chan entities.File, <-chan error) {
+			cfg.Skipped.ReportSkipped(entities.SkippedFile{
+				Path:   filepath.Join(cmdDir, "logo.png"),
+				Reason: entities.SkipReasonBinary,
+			})
+
+			files := make(chan entities.File, 1)
+			files <- entities.File{Path: filepath.Join(cmdDir, "root.go"), Data: []byte("root")}
+			close(files)
+
+			done := make(chan error, 1)
+			close(done)
+
+			return files, done
+		})
+
+	ag := aiagent_mock.NewMockAIAgent(t)
+	ag.EXPECT().CreateCommitMessage(mock.Anything, entity.CommitData{
+		UserInput:    "JIRA-1",
+		Status:       "M main.go",
+		StagedStatus: "M main.go",
+		Diff:         "+func main() {}",
+	}).Return(entities.Completion{Text: "Add main", Model: "gpt-5-mini"}, nil)
+	ag.EXPECT().ExplainFiles(mock.Anything, mock.Anything, entity.ExplainOptions{Roots: []string{cmdDir}}).RunAndReturn(
+		func(_ context.Context, files <-chan entities.File, _ entity.ExplainOptions) (entities.Completion, error) {
+			for range files {
+			}
+
+			return entities.Completion{Text: "The root command"}, nil
+		})
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetGitChangesProvider().Return(git, nil)
+	app.EXPECT().GetConfigurator().Return(c, nil)
+	app.EXPECT().GetFileProvider().Return(fp, nil)
+	app.EXPECT().GetAIAgent().Return(ag, nil)
+
+	var wired []repoAppKey
+
+	srv := httptest.NewServer(newServeAPI(testServeToken, func(root string, offline bool) (factory.AppBuilder, error) {
+		wired = append(wired, repoAppKey{root: root, offline: offline})
+		return app, nil
+	}).handler())
+	t.Cleanup(srv.Close)
+
+	t.Run("health needs no token", func(t *testing.T) {
+		res, body := serveRequest(t, srv, http.MethodGet, "/v1/health", "", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Contains(t, body, `"status":"ok"`)
+	})
+
+	t.Run("token is checked", func(t *testing.T) {
+		res, body := serveRequest(t, srv, http.MethodPost, "/v1/commit-message", "wrong", `{"repo":"/"}`)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		assert.Equal(t, "Bearer", res.Header.Get("WWW-Authenticate"))
+		assert.JSONEq(t, `{"error":"invalid or missing token"}`, body)
+	})
+
+	t.Run("commit message", func(t *testing.T) {
+		res, body := serveRequest(t, srv, http.MethodPost, "/v1/commit-message", testServeToken,
+			`{"repo":"`+cmdDir+`","context":"JIRA-1"}`)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+
+		var r entities.Result
+		require.NoError(t, json.Unmarshal([]byte(body), &r))
+		assert.Equal(t, "Add main", r.Result)
+		assert.Equal(t, "commit-msg", r.Command)
+		assert.Equal(t, "gpt-5-mini", r.Model)
+	})
+
+	t.Run("explain", func(t *testing.T) {
+		res, body := serveRequest(t, srv, http.MethodPost, "/v1/explain", testServeToken,
+			`{"repo":"`+root+`","paths":["cmd"],"persistent":false}`)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+
+		var r entities.Result
+		require.NoError(t, json.Unmarshal([]byte(body), &r))
+		assert.Equal(t, "The root command", r.Result)
+		assert.Equal(t, 1, r.FilesProcessed)
+		assert.Equal(t, []entities.SkippedFile{{Path: "cmd/logo.png", Reason: entities.SkipReasonBinary}}, r.Skipped)
+	})
+
+	assert.Equal(t, []repoAppKey{{root: root}}, wired, "requests to a repository share the application")
+
+	t.Run("bad requests", func(t *testing.T) {
+		for _, body := range []string{
+			`{"repo":"relative"}`,
+			`{"repo":"` + t.TempDir() + `"}`,
+			`{"repo":"` + root + `","unknown":1}`,
+			`{"repo":"` + root + `","paths":["-"]}`,
+			`{"repo":"` + root + `","paths":["../outside"]}`,
+			`{"repo":"` + root + `","paths":[],"persistent":false}`,
+		} {
+			res, resBody := serveRequest(t, srv, http.MethodPost, "/v1/explain", testServeToken, body)
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+			assert.Contains(t, resBody, `"error":"bad request: `, body)
+		}
+	})
+}
+
+func TestServeAPI_failedRunRewiresApp(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o700))
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetGitChangesProvider().Return(nil, errors.New("no token"))
+
+	wired := 0
+
+	srv := httptest.NewServer(newServeAPI(testServeToken, func(string, bool) (factory.AppBuilder, error) {
+		wired++
+		return app, nil
+	}).handler())
+	t.Cleanup(srv.Close)
+
+	for range 2 {
+		res, body := serveRequest(t, srv, http.MethodPost, "/v1/commit-message", testServeToken,
+			`{"repo":"`+root+`","offline":true}`)
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.JSONEq(t, `{"error":"no token"}`, body)
+	}
+
+	assert.Equal(t, 2, wired)
+}
+
+func TestServeToken(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "hange", serveTokenFile)
+
+	t.Run("random token is written to the file", func(t *testing.T) {
+		t.Setenv("HANGE_SERVE_TOKEN", "")
+
+		token, err := serveToken(file)
+		require.NoError(t, err)
+		assert.Len(t, token, 64)
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, token+"\n", string(data))
+
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("environment variable wins", func(t *testing.T) {
+		t.Setenv("HANGE_SERVE_TOKEN", "from-env")
+
+		token, err := serveToken(filepath.Join(t.TempDir(), serveTokenFile))
+		require.NoError(t, err)
+		assert.Equal(t, "from-env", token)
+	})
+}
+
+func serveRequest(t *testing.T, srv *httptest.Server, method, path, token, body string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), method, srv.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = res.Body.Close() }()
+
+	b, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return res, string(b)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/pkg/logging"
 )
 
 // versionCmd represents the version command
@@ -15,18 +16,19 @@ var versionCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		val, err := config.ReadFieldFromBytes(getBuildConfig(), config.FileTypeYaml, "version")
 		if err != nil {
-			slog.Info(fmt.Sprintf("Failed to read version: %s\n", err))
+			slog.Info("Failed to read version", logging.Err(err))
 			return err
 		}
 
 		if val == nil {
-			slog.Info(fmt.Sprintf("Failed to read version: version value is nil"))
+			slog.Info("Failed to read version: version value is nil")
 			return err
 		}
 
-		slog.Info(fmt.Sprintf("hange version %v", val))
+		// the version is a result of the command, so it is not a log record
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "hange version %v\n", val)
 
-		return nil
+		return err
 	},
 }
 
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
 
@@ -10,8 +12,13 @@ func TestVersionCommandSuccess(t *testing.T) {
 	t.Cleanup(func() { buildConfig = nil })
 	SetBuildConfig([]byte("version: 1.2.3"))
 
-	err := versionCmd.RunE(versionCmd, nil)
+	stdout := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(stdout)
+
+	err := versionCmd.RunE(cmd, nil)
 	require.NoError(t, err)
+	require.Equal(t, "hange version 1.2.3\n", stdout.String())
 }
 
 func TestVersionCommandReadError(t *testing.T) {
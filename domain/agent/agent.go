@@ -22,46 +22,63 @@ type agent struct {
 	ep ExplainProcessor
 }
 
-func (o *agent) ExplainFiles(ctx context.Context, files <-chan entities.File) (string, error) {
+func (o *agent) ExplainFiles(
+	ctx context.Context, files <-chan entities.File, opts entity.ExplainOptions,
+) (entities.Completion, error) {
 	defer o.ep.Cleanup(ctx)
 
-	if err := o.ep.ProcessFiles(ctx, files); err != nil {
-		return "", err
+	if err := o.ep.ProcessFiles(ctx, files, opts); err != nil {
+		return entities.Completion{}, err
 	}
 
 	return o.ep.ExecuteExplainRequest(ctx)
 }
 
-func (o *agent) CreateCommitMessage(ctx context.Context, data entity.CommitData) (string, error) {
-	if err := o.validateCommitParams(data); err != nil {
-		return "", err
+func (o *agent) CreateCommitMessage(ctx context.Context, data entity.CommitData) (entities.Completion, error) {
+	warnings, err := o.validateCommitParams(data)
+	if err != nil {
+		return entities.Completion{}, err
 	}
 
 	slog.Info("Commit data is sufficient. Waiting for LLM processing...")
 	defer slog.Info("LLM finished processing")
 
-	return o.cp.GenCommitMessage(ctx, data)
+	c, err := o.cp.GenCommitMessage(ctx, data)
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	c.Warnings = append(warnings, c.Warnings...)
+
+	return c, nil
 }
 
 var ErrProvidedEmptyInput = errors.New("provided empty input")
 var ErrNoStatusProvided = errors.New("either status or staged status should be provided")
 
-func (o *agent) validateCommitParams(data entity.CommitData) error {
+// validateCommitParams returns warnings about missing data that reduces quality of the response.
+func (o *agent) validateCommitParams(data entity.CommitData) ([]string, error) {
 	if data.Status == "" && data.StagedStatus == "" {
-		return ErrNoStatusProvided
+		return nil, ErrNoStatusProvided
 	}
 
+	var warnings []string
+
 	if data.Status == "" {
-		slog.Warn("Status is not provided, quality of response may be reduced")
+		warnings = append(warnings, "Status is not provided, quality of response may be reduced")
 	}
 
 	if data.StagedStatus == "" {
-		slog.Warn("Staged status is not provided, quality of response may be reduced")
+		warnings = append(warnings, "Staged status is not provided, quality of response may be reduced")
 	}
 
 	if data.Diff == "" {
-		return fmt.Errorf("%w: git diff. LLM can't construct commit message", ErrProvidedEmptyInput)
+		return nil, fmt.Errorf("%w: git diff. LLM can't construct commit message", ErrProvidedEmptyInput)
+	}
+
+	for _, w := range warnings {
+		slog.Warn(w)
 	}
 
-	return nil
+	return warnings, nil
 }
@@ -19,15 +19,15 @@ func TestExplainFilesSuccess(t *testing.T) {
 	files := make(chan entities.File)
 	close(files)
 
-	ep.EXPECT().ProcessFiles(mock.Anything, mock.Anything).Return(nil)
-	ep.EXPECT().ExecuteExplainRequest(mock.Anything).Return("ok", nil)
+	ep.EXPECT().ProcessFiles(mock.Anything, mock.Anything, entity.ExplainOptions{}).Return(nil)
+	ep.EXPECT().ExecuteExplainRequest(mock.Anything).Return(entities.Completion{Text: "ok"}, nil)
 	ep.EXPECT().Cleanup(mock.Anything)
 
 	uc := newTestAgent(nil, ep)
 
-	result, err := uc.ExplainFiles(context.Background(), files)
+	result, err := uc.ExplainFiles(context.Background(), files, entity.ExplainOptions{})
 	require.NoError(t, err)
-	require.Equal(t, "ok", result)
+	require.Equal(t, "ok", result.Text)
 }
 
 func TestExplainFilesUploadFails(t *testing.T) {
@@ -38,12 +38,12 @@ func TestExplainFilesUploadFails(t *testing.T) {
 
 	uploadErr := errors.New("upload failed")
 
-	ep.EXPECT().ProcessFiles(mock.Anything, mock.Anything).Return(uploadErr)
+	ep.EXPECT().ProcessFiles(mock.Anything, mock.Anything, entity.ExplainOptions{}).Return(uploadErr)
 	ep.EXPECT().Cleanup(mock.Anything)
 
 	uc := newTestAgent(nil, ep)
 
-	result, err := uc.ExplainFiles(context.Background(), files)
+	result, err := uc.ExplainFiles(context.Background(), files, entity.ExplainOptions{})
 	require.ErrorIs(t, err, uploadErr)
 	require.Empty(t, result)
 	ep.AssertNotCalled(t, "ExecuteExplainRequest", mock.Anything)
@@ -59,11 +59,30 @@ func TestCreateCommitMessage(t *testing.T) {
 			Diff:         "diff content",
 		}
 
-		cp.EXPECT().GenCommitMessage(mock.Anything, data).Return("commit message", nil)
+		cp.EXPECT().GenCommitMessage(mock.Anything, data).Return(entities.Completion{Text: "commit message"}, nil)
 
 		result, err := newTestAgent(cp, nil).CreateCommitMessage(context.Background(), data)
 		require.NoError(t, err)
-		require.Equal(t, "commit message", result)
+		require.Equal(t, "commit message", result.Text)
+		require.Empty(t, result.Warnings)
+	})
+
+	t.Run("adds warnings about missing data", func(t *testing.T) {
+		cp := commitprocessor_mock.NewMockCommitProcessor(t)
+		data := entity.CommitData{
+			StagedStatus: "staged files",
+			Diff:         "diff content",
+		}
+
+		cp.EXPECT().GenCommitMessage(mock.Anything, data).Return(entities.Completion{
+			Text:     "commit message",
+			Warnings: []string{"incomplete"},
+		}, nil)
+
+		result, err := newTestAgent(cp, nil).CreateCommitMessage(context.Background(), data)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Status is not provided, quality of response may be reduced", "incomplete"},
+			result.Warnings)
 	})
 
 	t.Run("fails validation when statuses missing", func(t *testing.T) {
@@ -128,7 +147,7 @@ func TestValidateCommitParams(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			err := validateCommitParamsForTest(tt.data)
+			_, err := validateCommitParamsForTest(tt.data)
 			if tt.err == nil {
 				require.NoError(t, err)
 				return
@@ -145,6 +164,6 @@ func newTestAgent(cp CommitProcessor, ep ExplainProcessor) *agent {
 }
 
 // validateCommitParamsForTest exposes validation logic for external tests.
-func validateCommitParamsForTest(data entity.CommitData) error {
+func validateCommitParamsForTest(data entity.CommitData) ([]string, error) {
 	return (&agent{}).validateCommitParams(data)
 }
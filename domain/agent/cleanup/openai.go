@@ -0,0 +1,143 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/agent/explain"
+	"golang.org/x/sync/errgroup"
+)
+
+// deleteConcurrency limits parallel delete requests, so rate limits are not hit
+const deleteConcurrency = 8
+
+// NewOpenAIJanitor creates a janitor of vector stores and files created by the explain command.
+func NewOpenAIJanitor(client *openai.Client) agent.Janitor {
+	return &openAIJanitor{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+type openAIJanitor struct {
+	client *openai.Client
+	now    func() time.Time
+}
+
+// FindOrphans lists vector stores by the name prefix. Files are found by the expiration set on upload
+// and by membership in the found vector stores. Files of other tools are not expected to match both
+// the purpose and the expiration.
+func (j *openAIJanitor) FindOrphans(ctx context.Context, olderThan time.Duration) ([]entity.RemoteResource, error) {
+	cutoff := j.now().Add(-olderThan)
+
+	var resources []entity.RemoteResource
+
+	storeFiles := map[string]bool{}
+
+	stores := j.client.VectorStores.ListAutoPaging(ctx, openai.VectorStoreListParams{})
+	for stores.Next() {
+		vs := stores.Current()
+
+		createdAt := time.Unix(vs.CreatedAt, 0)
+		if !strings.HasPrefix(vs.Name, explain.VectorStorePrefix) || createdAt.After(cutoff) {
+			continue
+		}
+
+		resources = append(resources, entity.RemoteResource{
+			Kind:      entity.RemoteVectorStore,
+			ID:        vs.ID,
+			Name:      vs.Name,
+			CreatedAt: createdAt,
+		})
+
+		files := j.client.VectorStores.Files.ListAutoPaging(ctx, vs.ID, openai.VectorStoreFileListParams{})
+		for files.Next() {
+			storeFiles[files.Current().ID] = true
+		}
+
+		if err := files.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list files of vector store %s: %w", vs.ID, err)
+		}
+	}
+
+	if err := stores.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list vector stores: %w", err)
+	}
+
+	files := j.client.Files.ListAutoPaging(ctx, openai.FileListParams{
+		Purpose: openai.String(string(openai.FilePurposeUserData)),
+	})
+	for files.Next() {
+		f := files.Current()
+
+		createdAt := time.Unix(f.CreatedAt, 0)
+		if !(storeFiles[f.ID] || isExplainFile(f)) || createdAt.After(cutoff) {
+			continue
+		}
+
+		resources = append(resources, entity.RemoteResource{
+			Kind:      entity.RemoteFile,
+			ID:        f.ID,
+			Name:      f.Filename,
+			CreatedAt: createdAt,
+		})
+	}
+
+	if err := files.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return resources, nil
+}
+
+func isExplainFile(f openai.FileObject) bool {
+	return f.ExpiresAt != 0 && time.Duration(f.ExpiresAt-f.CreatedAt)*time.Second == explain.FileTTL
+}
+
+func (j *openAIJanitor) Delete(ctx context.Context, resources []entity.RemoteResource) []entity.CleanupResult {
+	results := make([]entity.CleanupResult, len(resources))
+
+	eg := errgroup.Group{}
+	eg.SetLimit(deleteConcurrency)
+
+	for i, r := range resources {
+		eg.Go(func() error {
+			results[i] = entity.CleanupResult{Resource: r, Err: j.delete(ctx, r)}
+			return nil
+		})
+	}
+
+	_ = eg.Wait()
+
+	return results
+}
+
+// delete treats a missing resource as deleted, it may expire after it is listed
+func (j *openAIJanitor) delete(ctx context.Context, r entity.RemoteResource) error {
+	var err error
+
+	switch r.Kind {
+	case entity.RemoteVectorStore:
+		_, err = j.client.VectorStores.Delete(ctx, r.ID)
+	case entity.RemoteFile:
+		_, err = j.client.Files.Delete(ctx, r.ID)
+	default:
+		return fmt.Errorf("unknown remote resource kind %q", r.Kind)
+	}
+
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		slog.Debug("Remote resource is already deleted", r.LogAttrs()...)
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,143 @@
+package cleanup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+)
+
+// now is a fixed time of the test. Old resources are created an hour before it, recent ones a minute before.
+var now = time.Unix(1_800_000_000, 0)
+
+func TestOpenAIJanitor_FindOrphans(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/vector_stores":
+			writeList(t, w, `[
+				{"id":"vs_old","object":"vector_store","name":"hange_1799996400","created_at":1799996400},
+				{"id":"vs_recent","object":"vector_store","name":"hange_1799999940","created_at":1799999940},
+				{"id":"vs_other","object":"vector_store","name":"docs","created_at":1799996400}
+			]`)
+		case "/vector_stores/vs_old/files":
+			writeList(t, w, `[{"id":"file_in_store","object":"vector_store.file","vector_store_id":"vs_old"}]`)
+		case "/vector_stores/vs_recent/files":
+			writeList(t, w, `[]`)
+		case "/files":
+			require.Equal(t, "user_data", r.URL.Query().Get("purpose"))
+			writeList(t, w, `[
+				{"id":"file_in_store","object":"file","filename":"a.go","created_at":1799996400},
+				{"id":"file_ttl","object":"file","filename":"b.go","created_at":1799996400,"expires_at":1800000000},
+				{"id":"file_recent","object":"file","filename":"c.go","created_at":1799999940,"expires_at":1800003540},
+				{"id":"file_other","object":"file","filename":"d.pdf","created_at":1799996400}
+			]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	j := newTestJanitor(server.URL)
+
+	resources, err := j.FindOrphans(context.Background(), time.Minute*30)
+	require.NoError(t, err)
+
+	ids := make([]string, len(resources))
+	for i, r := range resources {
+		ids[i] = r.ID
+	}
+
+	assert.Equal(t, []string{"vs_old", "file_in_store", "file_ttl"}, ids)
+	assert.Equal(t, entity.RemoteResource{
+		Kind:      entity.RemoteVectorStore,
+		ID:        "vs_old",
+		Name:      "hange_1799996400",
+		CreatedAt: now.Add(-time.Hour),
+	}, resources[0])
+
+	// zero age includes resources of runs in progress
+	resources, err = j.FindOrphans(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Len(t, resources, 5)
+}
+
+func TestOpenAIJanitor_Delete(t *testing.T) {
+	t.Parallel()
+
+	mu := sync.Mutex{}
+	deleted := map[string]bool{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/vector_stores/vs_1", "/files/file_1":
+			mu.Lock()
+			deleted[r.URL.Path] = true
+			mu.Unlock()
+
+			_, _ = w.Write([]byte(`{"deleted":true}`))
+		case "/files/file_expired":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"message":"No such File object"}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"bad request"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	resources := []entity.RemoteResource{
+		{Kind: entity.RemoteVectorStore, ID: "vs_1"},
+		{Kind: entity.RemoteFile, ID: "file_1"},
+		{Kind: entity.RemoteFile, ID: "file_expired"},
+		{Kind: entity.RemoteFile, ID: "file_broken"},
+	}
+
+	results := newTestJanitor(server.URL).Delete(context.Background(), resources)
+	require.Len(t, results, len(resources))
+
+	for i, r := range results {
+		assert.Equal(t, resources[i], r.Resource)
+	}
+
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.NoError(t, results[2].Err, "missing resources are deleted already")
+	assert.Error(t, results[3].Err)
+	assert.Equal(t, map[string]bool{"/vector_stores/vs_1": true, "/files/file_1": true}, deleted)
+}
+
+func newTestJanitor(baseURL string) *openAIJanitor {
+	client := openai.NewClient(
+		option.WithBaseURL(baseURL),
+		option.WithAPIKey("sk-test"),
+		option.WithMaxRetries(0),
+	)
+
+	j := NewOpenAIJanitor(&client).(*openAIJanitor)
+	j.now = func() time.Time { return now }
+
+	return j
+}
+
+func writeList(t *testing.T, w http.ResponseWriter, data string) {
+	t.Helper()
+
+	_, err := w.Write([]byte(`{"object":"list","has_more":false,"data":` + data + `}`))
+	require.NoError(t, err)
+}
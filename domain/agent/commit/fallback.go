@@ -0,0 +1,47 @@
+package commit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+// NewFallbackCommitProcessor creates a processor which uses fallback if primary fails, e.g. the API is down.
+// The failure is returned as a warning of the completion.
+func NewFallbackCommitProcessor(primary, fallback agent.CommitProcessor) agent.CommitProcessor {
+	return &fallbackCommitProcessor{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+type fallbackCommitProcessor struct {
+	primary  agent.CommitProcessor
+	fallback agent.CommitProcessor
+}
+
+func (cp *fallbackCommitProcessor) GenCommitMessage(ctx context.Context, data entity.CommitData) (entities.Completion, error) {
+	c, err := cp.primary.GenCommitMessage(ctx, data)
+	if err == nil || errors.Is(err, context.Canceled) || ctx.Err() != nil {
+		return c, err
+	}
+
+	slog.Warn("LLM failed, the commit message is generated offline", logging.Err(err))
+
+	warning := fmt.Sprintf("LLM failed, the commit message is generated offline: %s", err)
+
+	c, err = cp.fallback.GenCommitMessage(ctx, data)
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	c.Warnings = append(c.Warnings, warning)
+
+	return c, nil
+}
@@ -0,0 +1,304 @@
+package commit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// HeuristicModel is the model of messages generated without LLM.
+const HeuristicModel = "heuristic"
+
+// maxSubjectLength is the common limit of a commit subject, lists are shortened to fit it
+const maxSubjectLength = 72
+
+// maxListed is the number of listed identifiers and packages before "and N more"
+const maxListed = 3
+
+var errNoFileChanges = errors.New("no file changes found in the diff")
+
+var (
+	goDeclRegexp   = regexp.MustCompile(`^(?:func|type|var|const)\s+([A-Z]\w*)`)
+	goMethodRegexp = regexp.MustCompile(`^func\s+\(\s*\w*\s*\*?([A-Z]\w*)[^)]*\)\s*([A-Z]\w*)`)
+)
+
+// NewHeuristicCommitProcessor creates a processor deriving a message from the staged patch without LLM:
+// the dominant operation, touched packages and new or removed exported Go identifiers. It is deterministic.
+func NewHeuristicCommitProcessor(conventional bool) agent.CommitProcessor {
+	return &heuristicCommitProcessor{conventional: conventional}
+}
+
+type heuristicCommitProcessor struct {
+	conventional bool
+}
+
+type changeOp string
+
+const (
+	opModified changeOp = "update"
+	opAdded    changeOp = "add"
+	opDeleted  changeOp = "remove"
+	opRenamed  changeOp = "rename"
+)
+
+type fileChange struct {
+	path    string
+	oldPath string
+	op      changeOp
+}
+
+// diffSummary is what the message is derived from. Identifiers are in the order of appearance.
+type diffSummary struct {
+	files   []fileChange
+	added   []string
+	removed []string
+}
+
+func (cp *heuristicCommitProcessor) GenCommitMessage(_ context.Context, data entity.CommitData) (entities.Completion, error) {
+	s := parseDiff(data.Diff)
+	if len(s.files) == 0 {
+		return entities.Completion{}, errNoFileChanges
+	}
+
+	var msg string
+
+	for listed := maxListed; listed > 0; listed-- {
+		msg = cp.message(s, listed)
+		if len(msg) <= maxSubjectLength {
+			break
+		}
+	}
+
+	return entities.Completion{Text: msg, Model: HeuristicModel}, nil
+}
+
+func (cp *heuristicCommitProcessor) message(s diffSummary, listed int) string {
+	packages := touchedPackages(s.files)
+	where := joinList(packages, listed)
+
+	var desc string
+
+	switch op := dominantOp(s.files); {
+	case len(s.added) > 0 || len(s.removed) > 0:
+		var parts []string
+
+		if len(s.added) > 0 {
+			parts = append(parts, "add "+joinList(s.added, listed))
+		}
+
+		if len(s.removed) > 0 {
+			parts = append(parts, "remove "+joinList(s.removed, listed))
+		}
+
+		desc = strings.Join(parts, "; ")
+	case op == opRenamed && len(s.files) == 1:
+		desc = fmt.Sprintf("rename %s to %s", s.files[0].oldPath, s.files[0].path)
+	default:
+		desc = fmt.Sprintf("%s %s", op, where)
+	}
+
+	identifiers := len(s.added) > 0 || len(s.removed) > 0
+
+	if cp.conventional {
+		// a file in the root is not a scope
+		scope := ""
+		if len(packages) == 1 && path.Dir(s.files[0].path) != "." {
+			scope = "(" + path.Base(packages[0]) + ")"
+		}
+
+		return fmt.Sprintf("%s%s: %s", conventionalType(s), scope, desc)
+	}
+
+	if identifiers {
+		return where + ": " + desc
+	}
+
+	return strings.ToUpper(desc[:1]) + desc[1:]
+}
+
+// parseDiff reads file headers and changed lines of a unified diff produced by git
+func parseDiff(diff string) diffSummary {
+	s := diffSummary{}
+	plus := map[string]bool{}
+	minus := map[string]bool{}
+
+	var plusOrder, minusOrder []string
+
+	var current *fileChange
+
+	for line := range strings.Lines(diff) {
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldPath, newPath := parseDiffHeader(line)
+			s.files = append(s.files, fileChange{path: newPath, oldPath: oldPath, op: opModified})
+			current = &s.files[len(s.files)-1]
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "new file mode"):
+			current.op = opAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			current.op = opDeleted
+		case strings.HasPrefix(line, "rename from "):
+			current.op = opRenamed
+			current.oldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			current.path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "+") && strings.HasSuffix(current.path, ".go"):
+			if id := goIdentifier(line[1:]); id != "" && !plus[id] {
+				plus[id] = true
+				plusOrder = append(plusOrder, id)
+			}
+		case strings.HasPrefix(line, "-") && strings.HasSuffix(current.path, ".go"):
+			if id := goIdentifier(line[1:]); id != "" && !minus[id] {
+				minus[id] = true
+				minusOrder = append(minusOrder, id)
+			}
+		}
+	}
+
+	// an identifier on both sides is changed, e.g. by a new signature
+	for _, id := range plusOrder {
+		if !minus[id] {
+			s.added = append(s.added, id)
+		}
+	}
+
+	for _, id := range minusOrder {
+		if !plus[id] {
+			s.removed = append(s.removed, id)
+		}
+	}
+
+	return s
+}
+
+// parseDiffHeader splits "diff --git a/<old> b/<new>". Paths with spaces are split at " b/".
+func parseDiffHeader(line string) (string, string) {
+	paths := strings.TrimPrefix(line, "diff --git ")
+
+	oldPath, newPath, ok := strings.Cut(paths, " b/")
+	if !ok {
+		return paths, paths
+	}
+
+	return strings.TrimPrefix(oldPath, "a/"), newPath
+}
+
+// goIdentifier returns an exported identifier declared by the line. Methods of exported types are named Type.Method.
+func goIdentifier(line string) string {
+	if m := goMethodRegexp.FindStringSubmatch(line); m != nil {
+		return m[1] + "." + m[2]
+	}
+
+	if m := goDeclRegexp.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+
+	return ""
+}
+
+// touchedPackages returns sorted directories of the files. Files in the root are named by themselves.
+func touchedPackages(files []fileChange) []string {
+	var packages []string
+
+	for _, f := range files {
+		dir := path.Dir(f.path)
+		if dir == "." {
+			dir = f.path
+		}
+
+		if !slices.Contains(packages, dir) {
+			packages = append(packages, dir)
+		}
+	}
+
+	slices.Sort(packages)
+
+	return packages
+}
+
+// dominantOp is the operation of more than half of the files, otherwise an update
+func dominantOp(files []fileChange) changeOp {
+	counts := map[changeOp]int{}
+	for _, f := range files {
+		counts[f.op]++
+	}
+
+	for op, n := range counts {
+		if n*2 > len(files) {
+			return op
+		}
+	}
+
+	return opModified
+}
+
+func conventionalType(s diffSummary) string {
+	switch {
+	case allFiles(s.files, isDocsFile):
+		return "docs"
+	case allFiles(s.files, isTestFile):
+		return "test"
+	case allFiles(s.files, isCIFile):
+		return "ci"
+	case allFiles(s.files, isBuildFile):
+		return "build"
+	case len(s.added) > 0 || dominantOp(s.files) == opAdded:
+		return "feat"
+	case len(s.removed) > 0 || dominantOp(s.files) == opDeleted || dominantOp(s.files) == opRenamed:
+		return "refactor"
+	default:
+		return "chore"
+	}
+}
+
+func allFiles(files []fileChange, match func(string) bool) bool {
+	for _, f := range files {
+		if !match(f.path) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDocsFile(p string) bool {
+	return strings.HasPrefix(p, "docs/") || slices.Contains([]string{".md", ".rst", ".txt"}, path.Ext(p))
+}
+
+func isTestFile(p string) bool {
+	return strings.HasSuffix(p, "_test.go") || strings.Contains("/"+p, "/testdata/")
+}
+
+func isCIFile(p string) bool {
+	return strings.HasPrefix(p, ".github/") || p == ".gitlab-ci.yml"
+}
+
+func isBuildFile(p string) bool {
+	return slices.Contains([]string{"Makefile", "go.mod", "go.sum", "Dockerfile", ".goreleaser.yaml"}, path.Base(p))
+}
+
+// joinList joins up to listed items: "a", "a and b", "a, b and 3 more"
+func joinList(items []string, listed int) string {
+	if len(items) > listed {
+		return strings.Join(items[:listed], ", ") + fmt.Sprintf(" and %d more", len(items)-listed)
+	}
+
+	if len(items) == 1 {
+		return items[0]
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
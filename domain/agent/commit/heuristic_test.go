@@ -0,0 +1,181 @@
+package commit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	commitprocessor_mock "github.com/yaroslav-koval/hange/mocks/commitprocessor"
+)
+
+const addedPackageDiff = `diff --git a/pkg/retry/retry.go b/pkg/retry/retry.go
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/pkg/retry/retry.go
+@@ -0,0 +1,12 @@
++package retry
++
++type Policy struct{}
++
++func (p *Policy) Next() bool { return false }
++
++func Do(p Policy, f func() error) error { return f() }
++
++func backoff() {}
+`
+
+const removedFileDiff = `diff --git a/cmd/legacy.go b/cmd/legacy.go
+deleted file mode 100644
+index 1111111..0000000
+--- a/cmd/legacy.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package cmd
+-
+-var LegacyCmd = 1
+`
+
+const renamedFileDiff = `diff --git a/docs/old.md b/docs/new.md
+similarity index 100%
+rename from docs/old.md
+rename to docs/new.md
+`
+
+const modifiedDiff = `diff --git a/cmd/root.go b/cmd/root.go
+index 1111111..2222222 100644
+--- a/cmd/root.go
++++ b/cmd/root.go
+@@ -1,3 +1,3 @@
+-func Execute() error {
++func Execute(args []string) error {
+diff --git a/cmd/commit.go b/cmd/commit.go
+index 1111111..2222222 100644
+--- a/cmd/commit.go
++++ b/cmd/commit.go
+@@ -1,1 +1,1 @@
+-	return nil
++	return err
+`
+
+const manyPackagesDiff = `diff --git a/domain/agent/commit/openai.go b/domain/agent/commit/openai.go
+--- a/domain/agent/commit/openai.go
++++ b/domain/agent/commit/openai.go
++func NewVeryLongConstructorNameOfTheCommitProcessor() {}
++func NewVeryLongConstructorNameOfTheExplainProcessor() {}
+diff --git a/domain/agent/explain/openai.go b/domain/agent/explain/openai.go
+--- a/domain/agent/explain/openai.go
++++ b/domain/agent/explain/openai.go
++type AnotherRatherLongExportedTypeName struct{}
+diff --git a/domain/factory/app.go b/domain/factory/app.go
+--- a/domain/factory/app.go
++++ b/domain/factory/app.go
++const YetAnotherConstant = 1
+`
+
+func TestHeuristicCommitProcessor_GenCommitMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		diff         string
+		conventional bool
+		expected     string
+	}{
+		{
+			name:     "added package lists exported identifiers",
+			diff:     addedPackageDiff,
+			expected: "pkg/retry: add Policy, Policy.Next and Do",
+		},
+		{
+			name:         "added package in conventional format",
+			diff:         addedPackageDiff,
+			conventional: true,
+			expected:     "feat(retry): add Policy, Policy.Next and Do",
+		},
+		{
+			name:         "removed file",
+			diff:         removedFileDiff,
+			conventional: true,
+			expected:     "refactor(cmd): remove LegacyCmd",
+		},
+		{
+			name:     "renamed file",
+			diff:     renamedFileDiff,
+			expected: "Rename docs/old.md to docs/new.md",
+		},
+		{
+			name:         "renamed docs in conventional format",
+			diff:         renamedFileDiff,
+			conventional: true,
+			expected:     "docs(docs): rename docs/old.md to docs/new.md",
+		},
+		{
+			name:     "changed signature is an update",
+			diff:     modifiedDiff,
+			expected: "Update cmd",
+		},
+		{
+			name:         "update in conventional format",
+			diff:         modifiedDiff,
+			conventional: true,
+			expected:     "chore(cmd): update cmd",
+		},
+		{
+			name:     "lists are shortened to fit the subject",
+			diff:     manyPackagesDiff,
+			expected: "domain/agent/commit and 2 more: add NewVeryLongConstructorNameOfTheCommitProcessor and 3 more",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cp := NewHeuristicCommitProcessor(tt.conventional)
+
+			c, err := cp.GenCommitMessage(context.Background(), entity.CommitData{Diff: tt.diff})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, c.Text)
+			assert.Equal(t, HeuristicModel, c.Model)
+		})
+	}
+
+	t.Run("empty diff", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewHeuristicCommitProcessor(false).GenCommitMessage(context.Background(), entity.CommitData{})
+		require.ErrorIs(t, err, errNoFileChanges)
+	})
+}
+
+func TestFallbackCommitProcessor_GenCommitMessage(t *testing.T) {
+	t.Parallel()
+
+	data := entity.CommitData{Diff: removedFileDiff}
+
+	t.Run("primary error falls back with a warning", func(t *testing.T) {
+		t.Parallel()
+
+		primary := NewUnavailableCommitProcessor(errors.New("api is down"))
+		cp := NewFallbackCommitProcessor(primary, NewHeuristicCommitProcessor(false))
+
+		c, err := cp.GenCommitMessage(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "cmd: remove LegacyCmd", c.Text)
+		assert.Equal(t, []string{"LLM failed, the commit message is generated offline: api is down"}, c.Warnings)
+	})
+
+	t.Run("cancellation is not a failure", func(t *testing.T) {
+		t.Parallel()
+
+		fallback := commitprocessor_mock.NewMockCommitProcessor(t)
+		cp := NewFallbackCommitProcessor(NewUnavailableCommitProcessor(context.Canceled), fallback)
+
+		_, err := cp.GenCommitMessage(context.Background(), data)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
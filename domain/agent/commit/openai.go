@@ -10,50 +10,79 @@ import (
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/responses"
 	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/completion"
 	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
 )
 
-func NewOpenAICommitProcessor(client *openai.Client) agent.CommitProcessor {
+// NewOpenAICommitProcessor creates a commit processor. Empty model and instruction fall back to the defaults.
+// conventional adds the Conventional Commits requirement to the instruction, a custom one too.
+func NewOpenAICommitProcessor(
+	client *openai.Client, model, instruction string, conventional bool,
+) agent.CommitProcessor {
+	model, instruction = withDefaults(model, instruction, conventional)
+
 	return &openAICommitProcessor{
-		client: client,
+		client:      client,
+		model:       model,
+		instruction: instruction,
 	}
 }
 
-const commitModel = openai.ChatModelGPT5Nano
+// DefaultModel is used if the profile doesn't set a model.
+const DefaultModel = openai.ChatModelGPT5Nano
+
+func withDefaults(model, instruction string, conventional bool) (string, string) {
+	if model == "" {
+		model = DefaultModel
+	}
+
+	if instruction == "" {
+		instruction = systemInstruction
+	}
+
+	if conventional {
+		instruction += conventionalInstruction
+	}
+
+	return model, instruction
+}
 
 var errEmptyOutput = errors.New("empty LLM output")
 
 type openAICommitProcessor struct {
-	client *openai.Client
+	client      *openai.Client
+	model       string
+	instruction string
 }
 
-func (cp *openAICommitProcessor) GenCommitMessage(ctx context.Context, data entity.CommitData) (string, error) {
+func (cp *openAICommitProcessor) GenCommitMessage(ctx context.Context, data entity.CommitData) (entities.Completion, error) {
 	resp, err := cp.client.Responses.New(ctx, responses.ResponseNewParams{
-		Instructions: openai.String(systemInstruction),
+		Instructions: openai.String(cp.instruction),
 		Include: []responses.ResponseIncludable{
 			responses.ResponseIncludableFileSearchCallResults,
 		},
 		Input: responses.ResponseNewParamsInputUnion{
-			OfString: openai.String(cp.buildInput(data)),
+			OfString: openai.String(buildInput(data)),
 		},
-		Model: commitModel,
+		Model: cp.model,
 	})
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
-	slog.Info(fmt.Sprintf("LLM output: %s", resp.OutputText()))
+	slog.Info("LLM output", slog.String("text", resp.OutputText()))
 
 	if resp.Status == responses.ResponseStatusIncomplete {
-		slog.Debug(fmt.Sprintf("Status: %s. Reason: %s",
-			resp.Status, resp.IncompleteDetails.Reason))
+		slog.Debug("Response is incomplete", slog.String("status", string(resp.Status)),
+			slog.String("reason", resp.IncompleteDetails.Reason))
 	}
 
 	if len(resp.OutputText()) == 0 {
-		return "", errEmptyOutput
+		return entities.Completion{}, errEmptyOutput
 	}
 
-	return resp.OutputText(), nil
+	return completion.FromOpenAIResponse(resp), nil
 }
 
 const systemInstruction = `You write Git commit messages.
@@ -65,7 +94,11 @@ Hard requirements:
 - Keep it short and specific (aim <= 72 chars).
 - Summarize the net change across ALL files (what + why), using the diff and reason.`
 
-func (cp *openAICommitProcessor) buildInput(data entity.CommitData) string {
+const conventionalInstruction = `
+- Follow Conventional Commits: "<type>(<optional scope>): <description>", e.g. "fix(auth): refresh expired token".
+- Use one of the types: feat, fix, docs, style, refactor, perf, test, build, ci, chore, revert.`
+
+func buildInput(data entity.CommitData) string {
 	b := strings.Builder{}
 
 	if data.UserInput != "" {
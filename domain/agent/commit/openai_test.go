@@ -58,20 +58,21 @@ func TestOpenAICommitProcessor_GenCommitMessage(t *testing.T) {
 			option.WithHTTPClient(&http.Client{Transport: rt}),
 		)
 
-		cp := &openAICommitProcessor{client: &client}
+		cp := NewOpenAICommitProcessor(&client, "", "", false).(*openAICommitProcessor)
 
 		msg, err := cp.GenCommitMessage(context.Background(), commitData)
 		require.NoError(t, err)
-		require.Equal(t, "commit message", msg)
+		require.Equal(t, "commit message", msg.Text)
+		require.Equal(t, string(DefaultModel), msg.Model)
 		require.NotEmpty(t, capturedBody)
 
-		expectedInput := cp.buildInput(commitData)
+		expectedInput := buildInput(commitData)
 
 		var payload map[string]any
 		require.NoError(t, json.Unmarshal(capturedBody, &payload))
 
 		require.Equal(t, systemInstruction, payload["instructions"])
-		require.Equal(t, string(commitModel), payload["model"])
+		require.Equal(t, string(DefaultModel), payload["model"])
 
 		include, ok := payload["include"].([]any)
 		require.True(t, ok)
@@ -94,14 +95,26 @@ func TestOpenAICommitProcessor_GenCommitMessage(t *testing.T) {
 			option.WithHTTPClient(&http.Client{Transport: rt}),
 		)
 
-		cp := &openAICommitProcessor{client: &client}
+		cp := NewOpenAICommitProcessor(&client, "", "", false).(*openAICommitProcessor)
 
 		msg, err := cp.GenCommitMessage(context.Background(), commitData)
 		require.Error(t, err)
-		require.Empty(t, msg)
+		require.Empty(t, msg.Text)
 	})
 }
 
+func TestNewOpenAICommitProcessor_Conventional(t *testing.T) {
+	t.Parallel()
+
+	client := openai.NewClient()
+
+	cp := NewOpenAICommitProcessor(&client, "", "Be brief.", true).(*openAICommitProcessor)
+	require.Equal(t, "Be brief."+conventionalInstruction, cp.instruction)
+
+	cp = NewOpenAICommitProcessor(&client, "", "", false).(*openAICommitProcessor)
+	require.Equal(t, systemInstruction, cp.instruction)
+}
+
 func newStubResponse(t *testing.T, output string) []byte {
 	t.Helper()
 
@@ -112,7 +125,7 @@ func newStubResponse(t *testing.T, output string) []byte {
 		IncompleteDetails:  responses.ResponseIncompleteDetails{},
 		Instructions:       responses.ResponseInstructionsUnion{},
 		Metadata:           shared.Metadata{},
-		Model:              shared.ResponsesModel(commitModel),
+		Model:              shared.ResponsesModel(DefaultModel),
 		Object:             constant.Response("response"),
 		Output:             []responses.ResponseOutputItemUnion{newOutputMessage(output)},
 		ParallelToolCalls:  false,
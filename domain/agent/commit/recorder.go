@@ -0,0 +1,42 @@
+package commit
+
+import (
+	"context"
+	"io"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// NewRecorderCommitProcessor creates a processor which prints the request of the OpenAI processor
+// with the same settings instead of sending it.
+func NewRecorderCommitProcessor(w io.Writer, model, instruction string, conventional bool) agent.CommitProcessor {
+	model, instruction = withDefaults(model, instruction, conventional)
+
+	return &recorderCommitProcessor{
+		w:           w,
+		model:       model,
+		instruction: instruction,
+	}
+}
+
+type recorderCommitProcessor struct {
+	w           io.Writer
+	model       string
+	instruction string
+}
+
+func (cp *recorderCommitProcessor) GenCommitMessage(_ context.Context, data entity.CommitData) (entities.Completion, error) {
+	err := dryrun.Print(cp.w, dryrun.Payload{
+		Model:        cp.model,
+		Instructions: cp.instruction,
+		Input:        buildInput(data),
+	})
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	return entities.Completion{}, dryrun.ErrDryRun
+}
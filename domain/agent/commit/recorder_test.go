@@ -0,0 +1,28 @@
+package commit
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+)
+
+func TestRecorderCommitProcessor(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	data := entity.CommitData{UserInput: "task", Diff: "diff content"}
+
+	_, err := NewRecorderCommitProcessor(buf, "gpt-5-mini", "", true).GenCommitMessage(context.Background(), data)
+	require.ErrorIs(t, err, dryrun.ErrDryRun)
+
+	out := buf.String()
+	assert.Contains(t, out, "Model: gpt-5-mini\n")
+	assert.Contains(t, out, systemInstruction+conventionalInstruction)
+	assert.Contains(t, out, buildInput(data))
+	assert.Contains(t, out, "estimated input cost $")
+}
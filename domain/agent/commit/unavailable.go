@@ -0,0 +1,23 @@
+package commit
+
+import (
+	"context"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// NewUnavailableCommitProcessor creates a processor failing with err, e.g. if the token can't be read.
+// It defers the failure until a message is requested, so commands not generating messages still run.
+func NewUnavailableCommitProcessor(err error) agent.CommitProcessor {
+	return &unavailableCommitProcessor{err: err}
+}
+
+type unavailableCommitProcessor struct {
+	err error
+}
+
+func (cp *unavailableCommitProcessor) GenCommitMessage(context.Context, entity.CommitData) (entities.Completion, error) {
+	return entities.Completion{}, cp.err
+}
@@ -0,0 +1,28 @@
+package completion
+
+import (
+	"fmt"
+
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// FromOpenAIResponse converts a response of OpenAI Responses API into a Completion.
+// An incomplete response is not an error, but it produces a warning.
+func FromOpenAIResponse(resp *responses.Response) entities.Completion {
+	c := entities.Completion{
+		Text:  resp.OutputText(),
+		Model: string(resp.Model),
+		Usage: entities.Usage{
+			InputTokens:  resp.Usage.InputTokens,
+			OutputTokens: resp.Usage.OutputTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+		},
+	}
+
+	if resp.Status == responses.ResponseStatusIncomplete {
+		c.Warnings = append(c.Warnings, fmt.Sprintf("response is incomplete: %s", resp.IncompleteDetails.Reason))
+	}
+
+	return c
+}
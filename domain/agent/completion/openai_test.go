@@ -0,0 +1,52 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/openai/openai-go/v3/shared/constant"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+func TestFromOpenAIResponse(t *testing.T) {
+	t.Parallel()
+
+	newResponse := func(status responses.ResponseStatus) *responses.Response {
+		return &responses.Response{
+			Model:  "gpt-5-nano",
+			Status: status,
+			Output: []responses.ResponseOutputItemUnion{
+				{
+					Type: "message",
+					Content: []responses.ResponseOutputMessageContentUnion{
+						{Text: "text", Type: string(constant.OutputText("output_text"))},
+					},
+				},
+			},
+			Usage: responses.ResponseUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+			IncompleteDetails: responses.ResponseIncompleteDetails{
+				Reason: "max_output_tokens",
+			},
+		}
+	}
+
+	t.Run("completed", func(t *testing.T) {
+		t.Parallel()
+
+		c := FromOpenAIResponse(newResponse(responses.ResponseStatusCompleted))
+		assert.Equal(t, entities.Completion{
+			Text:  "text",
+			Model: "gpt-5-nano",
+			Usage: entities.Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+		}, c)
+	})
+
+	t.Run("incomplete produces a warning", func(t *testing.T) {
+		t.Parallel()
+
+		c := FromOpenAIResponse(newResponse(responses.ResponseStatusIncomplete))
+		assert.Equal(t, "text", c.Text)
+		assert.Equal(t, []string{"response is incomplete: max_output_tokens"}, c.Warnings)
+	})
+}
@@ -0,0 +1,108 @@
+package dryrun
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// ErrDryRun stops a run after its payload is printed. Commands treat it as a success.
+var ErrDryRun = errors.New("dry run, nothing is sent")
+
+// bytesPerToken is an average of GPT tokenizers for English text and code
+const bytesPerToken = 4
+
+// inputPrices are USD per 1M input tokens of the models hange defaults to and their neighbours
+var inputPrices = map[string]float64{
+	"gpt-5":        1.25,
+	"gpt-5-mini":   0.25,
+	"gpt-5-nano":   0.05,
+	"gpt-4.1":      2,
+	"gpt-4.1-mini": 0.4,
+	"gpt-4.1-nano": 0.1,
+	"gpt-4o":       2.5,
+	"gpt-4o-mini":  0.15,
+}
+
+// Payload is everything a request would send to the provider.
+type Payload struct {
+	Model        string
+	Instructions string
+	Input        string
+	// Files are uploaded before the request
+	Files []File
+}
+
+type File struct {
+	Path string
+	Size int
+}
+
+// Bytes returns the size of the instructions, the input and the files.
+func (p Payload) Bytes() int {
+	size := len(p.Instructions) + len(p.Input)
+
+	for _, f := range p.Files {
+		size += f.Size
+	}
+
+	return size
+}
+
+// EstimateTokens is a rough estimate, the real count depends on the tokenizer of the model.
+func EstimateTokens(bytes int) int {
+	return (bytes + bytesPerToken - 1) / bytesPerToken
+}
+
+// EstimateCost returns a cost in USD of the tokens sent as input. It is false for a model of unknown price.
+func EstimateCost(model string, tokens int) (float64, bool) {
+	price, ok := inputPrices[model]
+	if !ok {
+		return 0, false
+	}
+
+	return float64(tokens) * price / 1_000_000, true
+}
+
+// Print writes the payload in a human-readable form.
+func Print(w io.Writer, p Payload) error {
+	_, err := fmt.Fprintf(w, "Model: %s\n\nInstructions:\n%s\n\nInput:\n%s\n\n", p.Model, p.Instructions, p.Input)
+	if err != nil {
+		return err
+	}
+
+	if len(p.Files) > 0 {
+		if _, err = fmt.Fprintf(w, "Files (%d):\n", len(p.Files)); err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+		for _, f := range p.Files {
+			if _, err = fmt.Fprintf(tw, "  %d B\t  %s\n", f.Size, f.Path); err != nil {
+				return err
+			}
+		}
+
+		if err = tw.Flush(); err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	size := p.Bytes()
+	tokens := EstimateTokens(size)
+
+	cost := "unknown for this model"
+	if usd, ok := EstimateCost(p.Model, tokens); ok {
+		cost = fmt.Sprintf("$%.4f", usd)
+	}
+
+	_, err = fmt.Fprintf(w, "Total: %d bytes, ~%d tokens, estimated input cost %s\n", size, tokens, cost)
+
+	return err
+}
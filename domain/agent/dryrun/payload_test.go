@@ -0,0 +1,57 @@
+package dryrun
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Parallel()
+
+	p := Payload{
+		Model:        "gpt-5-nano",
+		Instructions: "explain",
+		Input:        "files: a.go",
+		Files: []File{
+			{Path: "a.go", Size: 4000},
+			{Path: "docs/b.md", Size: 12},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Print(buf, p))
+
+	out := buf.String()
+	assert.Contains(t, out, "Model: gpt-5-nano\n")
+	assert.Contains(t, out, "Instructions:\nexplain\n")
+	assert.Contains(t, out, "Input:\nfiles: a.go\n")
+	assert.Contains(t, out, "Files (2):\n")
+	assert.Contains(t, out, "4000 B  a.go\n")
+	assert.Contains(t, out, "docs/b.md\n")
+	assert.True(t, strings.HasSuffix(out, "Total: 4030 bytes, ~1008 tokens, estimated input cost $0.0001\n"), out)
+}
+
+func TestPrint_UnknownModel(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Print(buf, Payload{Model: "llama3.1", Input: "diff"}))
+
+	assert.NotContains(t, buf.String(), "Files")
+	assert.Contains(t, buf.String(), "Total: 4 bytes, ~1 tokens, estimated input cost unknown for this model\n")
+}
+
+func TestEstimateCost(t *testing.T) {
+	t.Parallel()
+
+	cost, ok := EstimateCost("gpt-5-mini", 2_000_000)
+	require.True(t, ok)
+	assert.InDelta(t, 0.5, cost, 1e-9)
+
+	_, ok = EstimateCost("unknown", 1)
+	assert.False(t, ok)
+}
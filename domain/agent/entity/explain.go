@@ -0,0 +1,31 @@
+package entity
+
+import "github.com/yaroslav-koval/hange/pkg/progress"
+
+// ExplainOptions tune how files are sent to the provider.
+type ExplainOptions struct {
+	// Persistent keeps a vector store of the repository between runs, so only changed files are uploaded.
+	Persistent bool
+	// Fresh rebuilds the persistent vector store from scratch.
+	Fresh bool
+	// Roots are the input paths. A persistent sync removes files missing from the input only under them,
+	// files of other directories are kept unless they are deleted.
+	Roots []string
+	// Progress receives uploads and indexing of the files. Can be nil.
+	Progress progress.Reporter
+}
+
+// SyncState is a local copy of the content of a persistent vector store.
+type SyncState struct {
+	// Repo is a root of the repository the vector store belongs to.
+	Repo          string `json:"repo"`
+	VectorStoreID string `json:"vector_store_id,omitempty"`
+	// Files are keyed by their paths.
+	Files map[string]SyncedFile `json:"files,omitempty"`
+}
+
+type SyncedFile struct {
+	ID string `json:"id"`
+	// Hash is a SHA-256 of the content.
+	Hash string `json:"hash"`
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+type RemoteResourceKind string
+
+const (
+	RemoteVectorStore RemoteResourceKind = "vector store"
+	RemoteFile        RemoteResourceKind = "file"
+)
+
+// RemoteResource is an object created by hange on the provider's side, e.g. an uploaded file.
+type RemoteResource struct {
+	Kind      RemoteResourceKind
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+// LogAttrs returns attributes of the resource for a log record. The ID is a vector_store_id or a file_id,
+// so records of a vector store can be found by one key.
+func (r RemoteResource) LogAttrs() []any {
+	key := logging.KeyFileID
+	if r.Kind == RemoteVectorStore {
+		key = logging.KeyVectorStoreID
+	}
+
+	attrs := []any{slog.String(key, r.ID)}
+	if r.Name != "" {
+		attrs = append(attrs, slog.String("name", r.Name))
+	}
+
+	return attrs
+}
+
+// CleanupResult is a result of deletion of a remote resource. Err is nil if the resource is deleted.
+type CleanupResult struct {
+	Resource RemoteResource
+	Err      error
+}
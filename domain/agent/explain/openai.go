@@ -14,20 +14,59 @@ import (
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/responses"
 	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/completion"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
 	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+	"github.com/yaroslav-koval/hange/pkg/progress"
 	"golang.org/x/sync/errgroup"
 )
 
-const explanationModel = openai.ChatModelGPT5Nano
+// DefaultModel is used if the profile doesn't set a model.
+const DefaultModel = openai.ChatModelGPT5Nano
+
+const defaultContentType = "text/plain"
+
+// Remote resources of a run are recognized by these values, so they can be found if Cleanup never runs.
+const (
+	// VectorStorePrefix starts a name of a vector store, the creation time follows it.
+	VectorStorePrefix = "hange_"
+	// FileTTL is an expiration of uploaded files counted from their creation.
+	FileTTL = time.Hour
+)
 
 var ErrTooManyAttempts = errors.New("too many attempts")
 var ErrFailedToProcessFiles = errors.New("failed to process files")
 
-func NewOpenAIExplainProcessor(client *openai.Client) agent.ExplainProcessor {
+// NewOpenAIExplainProcessor creates an explain processor. Empty model and instruction fall back to the defaults.
+// Created files and vector stores are recorded to the journal, so they are deleted even if Cleanup never runs.
+// The state keeps a persistent vector store of the repository between runs.
+func NewOpenAIExplainProcessor(
+	client *openai.Client, model, instruction string, journal agent.ResourceJournal, state agent.SyncStateStore,
+) agent.ExplainProcessor {
+	model, instruction = withDefaults(model, instruction)
+
 	return &explainProcessor{
-		client: client,
-		mutex:  &sync.RWMutex{},
+		client:      client,
+		model:       model,
+		instruction: instruction,
+		journal:     journal,
+		state:       state,
+		progress:    progress.Discard,
+		mutex:       &sync.RWMutex{},
+	}
+}
+
+func withDefaults(model, instruction string) (string, string) {
+	if model == "" {
+		model = DefaultModel
+	}
+
+	if instruction == "" {
+		instruction = explainInstruction
 	}
+
+	return model, instruction
 }
 
 const explainInstruction = `You are a senior software engineer and codebase explainer.
@@ -60,17 +99,51 @@ const explainPrompt = `You are given the following project files. Explain them f
 
 Files: `
 
+func buildInput(fileNames []string) string {
+	return explainPrompt + strings.Join(fileNames, ", ")
+}
+
 // TODO take values of files/vectorStore expiration from env
 
 type explainProcessor struct {
 	client      *openai.Client
-	files       []*openai.FileObject
+	model       string
+	instruction string
+	journal     agent.ResourceJournal
+	state       agent.SyncStateStore
+	// files are deleted by Cleanup
+	files       []uploadedFile
 	vectorStore *openai.VectorStore
-	mutex       *sync.RWMutex
+	// keepStore is set when the vector store is persistent, Cleanup doesn't delete it
+	keepStore bool
+	// fileNames are the names of synced files, the names of uploaded files are used if it is nil
+	fileNames []string
+	progress  progress.Reporter
+	mutex     *sync.RWMutex
+}
+
+// uploadedFile is a file of the run with the path it is uploaded from. The file name echoed by the API
+// may differ from the path, so the path identifies the file.
+type uploadedFile struct {
+	path string
+	*openai.FileObject
 }
 
-func (ep *explainProcessor) ProcessFiles(ctx context.Context, files <-chan entities.File) error {
-	if err := ep.uploadFiles(ctx, files); err != nil {
+func (ep *explainProcessor) ProcessFiles(
+	ctx context.Context, files <-chan entities.File, opts entity.ExplainOptions,
+) error {
+	ep.progress = progress.OrDiscard(opts.Progress)
+
+	// a processor of a long-lived app is reused, resources of the previous run are cleaned up already
+	ep.mutex.Lock()
+	ep.files, ep.vectorStore, ep.keepStore, ep.fileNames = nil, nil, false, nil
+	ep.mutex.Unlock()
+
+	if opts.Persistent || opts.Fresh {
+		return ep.syncFiles(ctx, files, opts)
+	}
+
+	if err := ep.uploadFiles(ctx, files, FileTTL); err != nil {
 		return err
 	}
 
@@ -81,7 +154,7 @@ func (ep *explainProcessor) ProcessFiles(ctx context.Context, files <-chan entit
 	return nil
 }
 
-func (ep *explainProcessor) uploadFiles(ctx context.Context, files <-chan entities.File) error {
+func (ep *explainProcessor) uploadFiles(ctx context.Context, files <-chan entities.File, ttl time.Duration) error {
 	eg, ctx := errgroup.WithContext(ctx)
 
 	consumed := false
@@ -97,46 +170,78 @@ func (ep *explainProcessor) uploadFiles(ctx context.Context, files <-chan entiti
 				break
 			}
 
-			eg.Go(func() error {
-				params := openai.FileNewParams{
-					File:    openai.File(bytes.NewReader(f.Data), f.Path, "text/plain"),
-					Purpose: openai.FilePurposeUserData,
-				}
-
-				// TODO make a fix PR in SDK. ExpiresAfter bug in SDK. It aligns field by dot: "expires_after.anchor: created_at"
-				// https://github.com/openai/openai-go/issues/563?utm_source=chatgpt.com
-				// params.ExpiresAfter = openai.FileNewParamsExpiresAfter{
-				//   Seconds: 1 * hourInSeconds,
-				// }
+			ep.progress.Expect(progress.StageUpload, 1)
 
-				// expiration is needed to avoid user's manual cleanup
-				params.SetExtraFields(map[string]any{
-					"expires_after[anchor]":  "created_at",
-					"expires_after[seconds]": strconv.Itoa(60 * 60),
-				})
-
-				fileResp, err := ep.client.Files.New(ctx, params)
-				if err != nil {
+			eg.Go(func() error {
+				if err := ep.uploadFile(ctx, f, ttl); err != nil {
 					return err
 				}
 
-				slog.Debug(fmt.Sprintf("File created:\n%s\n", fileResp.RawJSON()))
-
-				ep.mutex.Lock()
-				defer ep.mutex.Unlock()
-				ep.files = append(ep.files, fileResp)
+				ep.progress.Done(progress.StageUpload, 1, int64(len(f.Data)))
 
 				return nil
 			})
 		}
 	}
 
-	return eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	ep.progress.Finish(progress.StageUpload)
+
+	return nil
+}
+
+// uploadFile uploads a file with the expiration counted from its creation and adds it to the files of the run
+func (ep *explainProcessor) uploadFile(ctx context.Context, f entities.File, ttl time.Duration) error {
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+
+	params := openai.FileNewParams{
+		File:    openai.File(bytes.NewReader(f.Data), f.Path, contentType),
+		Purpose: openai.FilePurposeUserData,
+	}
+
+	// TODO make a fix PR in SDK. ExpiresAfter bug in SDK. It aligns field by dot: "expires_after.anchor: created_at"
+	// https://github.com/openai/openai-go/issues/563?utm_source=chatgpt.com
+	// params.ExpiresAfter = openai.FileNewParamsExpiresAfter{
+	//   Seconds: 1 * hourInSeconds,
+	// }
+
+	// expiration is needed to avoid user's manual cleanup
+	params.SetExtraFields(map[string]any{
+		"expires_after[anchor]":  "created_at",
+		"expires_after[seconds]": strconv.Itoa(int(ttl.Seconds())),
+	})
+
+	fileResp, err := ep.client.Files.New(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("File created", slog.String(logging.KeyFileID, fileResp.ID),
+		slog.String(logging.KeyFile, fileResp.Filename))
+
+	ep.record(entity.RemoteResource{
+		Kind:      entity.RemoteFile,
+		ID:        fileResp.ID,
+		Name:      fileResp.Filename,
+		CreatedAt: time.Unix(fileResp.CreatedAt, 0),
+	})
+
+	ep.mutex.Lock()
+	defer ep.mutex.Unlock()
+	ep.files = append(ep.files, uploadedFile{path: f.Path, FileObject: fileResp})
+
+	return nil
 }
 
 func (ep *explainProcessor) createVectorStore(ctx context.Context) error {
 	vs, err := ep.client.VectorStores.New(ctx, openai.VectorStoreNewParams{
-		Name:             openai.String("hange_" + strconv.Itoa(int(time.Now().UTC().Unix()))),
+		Name:             openai.String(VectorStorePrefix + strconv.Itoa(int(time.Now().UTC().Unix()))),
 		Metadata:         nil,
 		ChunkingStrategy: openai.FileChunkingStrategyParamUnion{},
 		ExpiresAfter: openai.VectorStoreNewParamsExpiresAfter{
@@ -147,7 +252,14 @@ func (ep *explainProcessor) createVectorStore(ctx context.Context) error {
 		return err
 	}
 
-	slog.Info("Waiting for vector store processing...")
+	ep.record(entity.RemoteResource{
+		Kind:      entity.RemoteVectorStore,
+		ID:        vs.ID,
+		Name:      vs.Name,
+		CreatedAt: time.Unix(vs.CreatedAt, 0),
+	})
+
+	slog.Info("Waiting for vector store processing...", slog.String(logging.KeyVectorStoreID, vs.ID))
 
 	vs, err = retry(
 		ctx,
@@ -173,8 +285,7 @@ func (ep *explainProcessor) createVectorStore(ctx context.Context) error {
 	ep.vectorStore = vs
 	ep.mutex.Unlock()
 
-	slog.Info("Vector store created")
-	slog.Debug(vs.RawJSON())
+	slog.Info("Vector store created", slog.String(logging.KeyVectorStoreID, vs.ID))
 
 	ep.mutex.RLock()
 
@@ -194,7 +305,12 @@ func (ep *explainProcessor) createVectorStore(ctx context.Context) error {
 		return err
 	}
 
-	slog.Info("Started files batch processing...")
+	slog.Info("Started files batch processing...", slog.String(logging.KeyVectorStoreID, vs.ID),
+		slog.Int64("files", filesCount))
+
+	ep.progress.Expect(progress.StageIndex, int(filesCount))
+
+	var indexed int64
 
 	// wait until files are processed
 	vs, err = retry(ctx, func() (*openai.VectorStore, bool, error) {
@@ -203,7 +319,11 @@ func (ep *explainProcessor) createVectorStore(ctx context.Context) error {
 			return nil, false, err
 		}
 
-		slog.Debug(fmt.Sprintf("Files processing status: %v\n%s\n", vs.Status, vs.FileCounts.RawJSON()))
+		slog.Debug("Files processing status", slog.String(logging.KeyVectorStoreID, vs.ID),
+			slog.String("status", string(vs.Status)), slog.Int64("completed", vs.FileCounts.Completed),
+			slog.Int64("in_progress", vs.FileCounts.InProgress), slog.Int64("failed", vs.FileCounts.Failed))
+
+		ep.reportIndexed(vs.FileCounts.Total-vs.FileCounts.InProgress, &indexed)
 
 		if (vs.FileCounts.Total - vs.FileCounts.InProgress) == filesCount {
 			return vs, true, nil
@@ -219,11 +339,21 @@ func (ep *explainProcessor) createVectorStore(ctx context.Context) error {
 		return ErrFailedToProcessFiles
 	}
 
-	slog.Info("File batch is uploaded to vector store")
+	ep.progress.Finish(progress.StageIndex)
+
+	slog.Info("File batch is uploaded to vector store", slog.String(logging.KeyVectorStoreID, vs.ID))
 
 	return nil
 }
 
+// reportIndexed reports files processed by the vector store since the previous poll
+func (ep *explainProcessor) reportIndexed(processed int64, reported *int64) {
+	if processed > *reported {
+		ep.progress.Done(progress.StageIndex, int(processed-*reported), 0)
+		*reported = processed
+	}
+}
+
 // 0 attempts means infinite polling
 func retry[T any](ctx context.Context, f func() (*T, bool, error), interval time.Duration, attempts int) (*T, error) {
 	attemptsCounter := 0
@@ -247,6 +377,9 @@ func retry[T any](ctx context.Context, f func() (*T, bool, error), interval time
 				return nil, ErrTooManyAttempts
 			}
 
+			slog.Debug("Not ready yet, polling again", slog.Int(logging.KeyAttempt, attemptsCounter),
+				slog.Duration(logging.KeyDuration, interval))
+
 			time.Sleep(interval)
 		}
 	}
@@ -275,9 +408,10 @@ func (ep *explainProcessor) Cleanup(ctx context.Context) {
 
 			_, err := ep.client.Files.Delete(ctx, f.ID)
 			if err != nil {
-				slog.Error(fmt.Sprintf("Failed to delete file by id %s: %s", f.ID, err))
+				slog.Error("Failed to delete file", slog.String(logging.KeyFileID, f.ID), logging.Err(err))
 			} else {
-				slog.Debug(fmt.Sprintf("File is deleted by id %s", f.ID))
+				slog.Debug("File is deleted", slog.String(logging.KeyFileID, f.ID))
+				ep.markDeleted(entity.RemoteResource{Kind: entity.RemoteFile, ID: f.ID, Name: f.Filename})
 			}
 		})
 	}
@@ -286,15 +420,21 @@ func (ep *explainProcessor) Cleanup(ctx context.Context) {
 		ep.mutex.RLock()
 		defer ep.mutex.RUnlock()
 
-		if ep.vectorStore == nil {
+		if ep.vectorStore == nil || ep.keepStore {
 			return
 		}
 
 		_, err := ep.client.VectorStores.Delete(ctx, ep.vectorStore.ID)
 		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to delete vector store by id %s: %s", ep.vectorStore.ID, err))
+			slog.Error("Failed to delete vector store", slog.String(logging.KeyVectorStoreID, ep.vectorStore.ID),
+				logging.Err(err))
 		} else {
-			slog.Debug(fmt.Sprintf("Vector store is deleted by id %s", ep.vectorStore.ID))
+			slog.Debug("Vector store is deleted", slog.String(logging.KeyVectorStoreID, ep.vectorStore.ID))
+			ep.markDeleted(entity.RemoteResource{
+				Kind: entity.RemoteVectorStore,
+				ID:   ep.vectorStore.ID,
+				Name: ep.vectorStore.Name,
+			})
 		}
 	})
 
@@ -303,27 +443,43 @@ func (ep *explainProcessor) Cleanup(ctx context.Context) {
 	slog.Info("Data cleanup is finished")
 }
 
-func (ep *explainProcessor) ExecuteExplainRequest(ctx context.Context) (string, error) {
+// record doesn't fail the run, the resource is deleted by Cleanup or by expiration anyway
+func (ep *explainProcessor) record(r entity.RemoteResource) {
+	if err := ep.journal.Created(r); err != nil {
+		slog.Warn("Failed to record a resource to the journal", append(r.LogAttrs(), logging.Err(err))...)
+	}
+}
+
+func (ep *explainProcessor) markDeleted(r entity.RemoteResource) {
+	if err := ep.journal.Deleted(r); err != nil {
+		slog.Warn("Failed to record a deletion to the journal", append(r.LogAttrs(), logging.Err(err))...)
+	}
+}
+
+func (ep *explainProcessor) ExecuteExplainRequest(ctx context.Context) (entities.Completion, error) {
 	ep.mutex.RLock()
 
-	fileNames := make([]string, len(ep.files))
-	for i, f := range ep.files {
-		fileNames[i] = f.Filename
+	fileNames := ep.fileNames
+	if fileNames == nil {
+		fileNames = make([]string, len(ep.files))
+		for i, f := range ep.files {
+			fileNames[i] = f.path
+		}
 	}
 
 	ep.mutex.RUnlock()
 
-	input := explainPrompt + strings.Join(fileNames, ", ")
+	input := buildInput(fileNames)
 
 	slog.Info("Calling explanation model...")
 
 	resp, err := ep.client.Responses.New(ctx, responses.ResponseNewParams{
-		Instructions: openai.String(explainInstruction),
+		Instructions: openai.String(ep.instruction),
 		Include: []responses.ResponseIncludable{
 			responses.ResponseIncludableFileSearchCallResults,
 		},
 		Input: responses.ResponseNewParamsInputUnion{OfString: openai.String(input)},
-		Model: explanationModel,
+		Model: ep.model,
 		Tools: []responses.ToolUnionParam{
 			{
 				OfFileSearch: &responses.FileSearchToolParam{
@@ -333,8 +489,8 @@ func (ep *explainProcessor) ExecuteExplainRequest(ctx context.Context) (string,
 		},
 	})
 	if err != nil {
-		return "", err
+		return entities.Completion{}, err
 	}
 
-	return resp.OutputText(), nil
+	return completion.FromOpenAIResponse(resp), nil
 }
@@ -16,8 +16,13 @@ import (
 	"github.com/openai/openai-go/v3/responses"
 	"github.com/openai/openai-go/v3/shared"
 	"github.com/openai/openai-go/v3/shared/constant"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
 	"github.com/yaroslav-koval/hange/domain/entities"
+	reporter_mock "github.com/yaroslav-koval/hange/mocks/reporter"
+	resourcejournal_mock "github.com/yaroslav-koval/hange/mocks/resourcejournal"
+	"github.com/yaroslav-koval/hange/pkg/progress"
 )
 
 func TestExplainProcessor_uploadFiles(t *testing.T) {
@@ -51,21 +56,36 @@ func TestExplainProcessor_uploadFiles(t *testing.T) {
 	filesCh <- entities.File{Path: "two.md", Data: []byte("two")}
 	close(filesCh)
 
-	ep := newTestExplainProcessor(client)
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Created(mock.MatchedBy(func(r entity.RemoteResource) bool {
+		return r.Kind == entity.RemoteFile
+	})).Return(nil).Times(2)
+
+	reporter := reporter_mock.NewMockReporter(t)
+	reporter.EXPECT().Expect(progress.StageUpload, 1).Times(2)
+	reporter.EXPECT().Done(progress.StageUpload, 1, int64(3)).Times(2)
+	reporter.EXPECT().Finish(progress.StageUpload).Once()
+
+	ep := newTestExplainProcessor(t, client)
+	ep.journal = journal
+	ep.progress = reporter
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	err := ep.uploadFiles(ctx, filesCh)
+	err := ep.uploadFiles(ctx, filesCh, FileTTL)
 	require.NoError(t, err)
 
 	require.Len(t, ep.files, 2)
 
-	var ids []string
+	var ids, paths []string
 	for _, f := range ep.files {
 		ids = append(ids, f.ID)
+		paths = append(paths, f.path)
 	}
 	require.ElementsMatch(t, []string{"file_1", "file_2"}, ids)
+	// the API echoes other file names, the local paths are kept
+	require.ElementsMatch(t, []string{"one.go", "two.md"}, paths)
 
 	mu.Lock()
 	require.Equal(t, 2, counter)
@@ -85,9 +105,9 @@ func TestExplainProcessor_uploadFiles_contextCancelled(t *testing.T) {
 		t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
 	})
 
-	ep := newTestExplainProcessor(client)
+	ep := newTestExplainProcessor(t, client)
 
-	err := ep.uploadFiles(ctx, filesCh)
+	err := ep.uploadFiles(ctx, filesCh, FileTTL)
 	require.ErrorIs(t, err, context.Canceled)
 	require.Empty(t, ep.files)
 }
@@ -95,9 +115,9 @@ func TestExplainProcessor_uploadFiles_contextCancelled(t *testing.T) {
 func TestExplainProcessor_createVectorStore(t *testing.T) {
 	t.Parallel()
 
-	testFiles := []*openai.FileObject{
-		{ID: "file_a"},
-		{ID: "file_b"},
+	testFiles := []uploadedFile{
+		{FileObject: &openai.FileObject{ID: "file_a"}},
+		{FileObject: &openai.FileObject{ID: "file_b"}},
 	}
 
 	var receivedFileIDs []string
@@ -139,7 +159,7 @@ func TestExplainProcessor_createVectorStore(t *testing.T) {
 		}
 	})
 
-	ep := newTestExplainProcessor(client)
+	ep := newTestExplainProcessor(t, client)
 	ep.files = testFiles
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -155,9 +175,9 @@ func TestExplainProcessor_createVectorStore(t *testing.T) {
 func TestExplainProcessor_createVectorStore_waitsForFileProcessing(t *testing.T) {
 	t.Parallel()
 
-	testFiles := []*openai.FileObject{
-		{ID: "file_a"},
-		{ID: "file_b"},
+	testFiles := []uploadedFile{
+		{FileObject: &openai.FileObject{ID: "file_a"}},
+		{FileObject: &openai.FileObject{ID: "file_b"}},
 	}
 
 	var (
@@ -217,8 +237,14 @@ func TestExplainProcessor_createVectorStore_waitsForFileProcessing(t *testing.T)
 		}
 	})
 
-	ep := newTestExplainProcessor(client)
+	reporter := reporter_mock.NewMockReporter(t)
+	reporter.EXPECT().Expect(progress.StageIndex, len(testFiles)).Once()
+	reporter.EXPECT().Done(progress.StageIndex, len(testFiles), int64(0)).Once()
+	reporter.EXPECT().Finish(progress.StageIndex).Once()
+
+	ep := newTestExplainProcessor(t, client)
 	ep.files = testFiles
+	ep.progress = reporter
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -234,9 +260,9 @@ func TestExplainProcessor_createVectorStore_waitsForFileProcessing(t *testing.T)
 func TestExplainProcessor_createVectorStore_returnsErrorOnFailedFiles(t *testing.T) {
 	t.Parallel()
 
-	testFiles := []*openai.FileObject{
-		{ID: "file_a"},
-		{ID: "file_b"},
+	testFiles := []uploadedFile{
+		{FileObject: &openai.FileObject{ID: "file_a"}},
+		{FileObject: &openai.FileObject{ID: "file_b"}},
 	}
 
 	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
@@ -275,7 +301,7 @@ func TestExplainProcessor_createVectorStore_returnsErrorOnFailedFiles(t *testing
 		}
 	})
 
-	ep := newTestExplainProcessor(client)
+	ep := newTestExplainProcessor(t, client)
 	ep.files = testFiles
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -288,8 +314,8 @@ func TestExplainProcessor_createVectorStore_returnsErrorOnFailedFiles(t *testing
 func TestExplainProcessor_createVectorStore_returnsErrWhenVectorStoreStuck(t *testing.T) {
 	t.Parallel()
 
-	testFiles := []*openai.FileObject{
-		{ID: "file_a"},
+	testFiles := []uploadedFile{
+		{FileObject: &openai.FileObject{ID: "file_a"}},
 	}
 
 	var getCalls int
@@ -309,7 +335,7 @@ func TestExplainProcessor_createVectorStore_returnsErrWhenVectorStoreStuck(t *te
 		}
 	})
 
-	ep := newTestExplainProcessor(client)
+	ep := newTestExplainProcessor(t, client)
 	ep.files = testFiles
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -373,10 +399,16 @@ func TestExplainProcessor_Cleanup(t *testing.T) {
 		}
 	})
 
-	ep := newTestExplainProcessor(client)
-	ep.files = []*openai.FileObject{
-		{ID: "file_a"},
-		{ID: "file_b"},
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Deleted(entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_a"}).Return(nil)
+	journal.EXPECT().Deleted(entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_b"}).Return(nil)
+	journal.EXPECT().Deleted(entity.RemoteResource{Kind: entity.RemoteVectorStore, ID: "vs_cleanup"}).Return(nil)
+
+	ep := newTestExplainProcessor(t, client)
+	ep.journal = journal
+	ep.files = []uploadedFile{
+		{FileObject: &openai.FileObject{ID: "file_a"}},
+		{FileObject: &openai.FileObject{ID: "file_b"}},
 	}
 	ep.vectorStore = &openai.VectorStore{ID: "vs_cleanup"}
 
@@ -435,10 +467,10 @@ func TestExplainProcessor_ExecuteExplainRequest(t *testing.T) {
 		require.NoError(t, json.NewEncoder(w).Encode(newResponsePayload("generated explanation")))
 	})
 
-	ep := newTestExplainProcessor(client)
-	ep.files = []*openai.FileObject{
-		{ID: "file_a", Filename: fileNames[0]},
-		{ID: "file_b", Filename: fileNames[1]},
+	ep := newTestExplainProcessor(t, client)
+	ep.files = []uploadedFile{
+		{path: fileNames[0], FileObject: &openai.FileObject{ID: "file_a"}},
+		{path: fileNames[1], FileObject: &openai.FileObject{ID: "file_b"}},
 	}
 	ep.vectorStore = &openai.VectorStore{ID: "vs_exec"}
 
@@ -447,7 +479,7 @@ func TestExplainProcessor_ExecuteExplainRequest(t *testing.T) {
 
 	result, err := ep.ExecuteExplainRequest(ctx)
 	require.NoError(t, err)
-	require.Equal(t, "generated explanation", result)
+	require.Equal(t, "generated explanation", result.Text)
 }
 
 func newTestClient(t *testing.T, handler http.HandlerFunc) *openai.Client {
@@ -500,7 +532,7 @@ func newResponsePayload(text string) responses.Response {
 			OfString: explainInstruction,
 		},
 		Metadata:          shared.Metadata{},
-		Model:             explanationModel,
+		Model:             DefaultModel,
 		Object:            "response",
 		ParallelToolCalls: false,
 		Temperature:       0,
@@ -596,9 +628,14 @@ func TestRetry(t *testing.T) {
 	})
 }
 
-func newTestExplainProcessor(client *openai.Client) *explainProcessor {
-	return &explainProcessor{
-		client: client,
-		mutex:  &sync.RWMutex{},
-	}
+// newTestExplainProcessor accepts any journal calls, tests checking them replace the journal
+func newTestExplainProcessor(t *testing.T, client *openai.Client) *explainProcessor {
+	t.Helper()
+
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Created(mock.Anything).Return(nil).Maybe()
+	journal.EXPECT().Deleted(mock.Anything).Return(nil).Maybe()
+	journal.EXPECT().Adopted(mock.Anything).Return(nil).Maybe()
+
+	return NewOpenAIExplainProcessor(client, "", "", journal, nil).(*explainProcessor)
 }
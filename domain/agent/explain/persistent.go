@@ -0,0 +1,448 @@
+package explain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+const (
+	// PersistentStorePrefix starts a name of a persistent vector store, the repository name follows it.
+	// It differs from VectorStorePrefix, so cleanup of leftovers doesn't delete persistent stores.
+	PersistentStorePrefix = "hange-repo_"
+	// PersistentFileTTL is the longest expiration of files. An expired file is uploaded again by the next sync.
+	PersistentFileTTL = 30 * 24 * time.Hour
+	// persistentStoreIdleDays deletes a vector store of a repository which is not explained anymore
+	persistentStoreIdleDays = 30
+)
+
+// ErrOutsideRepo is returned for a file outside the repository of the persistent vector store. The state
+// belongs to the repository of the working directory, it can't refer to files of another one.
+var ErrOutsideRepo = errors.New("file is outside the repository of the persistent vector store")
+
+// syncFiles makes the persistent vector store of the repository mirror the files. Unchanged files are kept,
+// changed and new ones are uploaded. Files missing from the input are removed if they are under the roots of
+// the input or deleted from the disk, so explaining another directory keeps the files of the previous one.
+// The state is saved before obsolete files are removed, so an interrupted sync never refers to deleted files.
+func (ep *explainProcessor) syncFiles(
+	ctx context.Context, files <-chan entities.File, opts entity.ExplainOptions,
+) error {
+	state, err := ep.state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load vector store state: %w", err)
+	}
+
+	if state.Files == nil {
+		state.Files = map[string]entity.SyncedFile{}
+	}
+
+	if opts.Fresh && state.VectorStoreID != "" {
+		slog.Info("Rebuilding vector store of the repository...", slog.String(logging.KeyVectorStoreID, state.VectorStoreID))
+		ep.dropStore(ctx, &state)
+	}
+
+	vs, created, err := ep.openStore(ctx, &state)
+	if err != nil {
+		return err
+	}
+
+	attached, err := ep.attachedFiles(ctx, vs.ID)
+	if err != nil {
+		return err
+	}
+
+	hashes, changed, err := collectChanges(ctx, files, state, attached)
+	if err != nil {
+		return err
+	}
+
+	roots, err := repoRoots(state.Repo, opts.Roots)
+	if err != nil {
+		return err
+	}
+
+	var obsolete []entity.RemoteResource
+
+	kept := 0
+
+	for path, f := range state.Files {
+		hash, ok := hashes[path]
+
+		switch {
+		case ok && hash == f.Hash && attached[f.ID]:
+			continue
+		case !ok && attached[f.ID] && !underRoots(path, roots) && onDisk(state.Repo, path):
+			kept++
+			continue
+		}
+
+		obsolete = append(obsolete, entity.RemoteResource{Kind: entity.RemoteFile, ID: f.ID, Name: path})
+		delete(state.Files, path)
+	}
+
+	uploaded, err := ep.uploadChanged(ctx, vs.ID, changed)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range uploaded {
+		state.Files[f.path] = entity.SyncedFile{ID: f.ID, Hash: hashes[f.path]}
+	}
+
+	state.VectorStoreID = vs.ID
+
+	if err = ep.state.Save(state); err != nil {
+		return fmt.Errorf("failed to save vector store state: %w", err)
+	}
+
+	ep.adopt(vs, created, uploaded)
+	ep.removeFiles(ctx, vs.ID, obsolete, attached)
+
+	fileNames := slices.Sorted(maps.Keys(hashes))
+
+	ep.mutex.Lock()
+	ep.fileNames = fileNames
+	ep.mutex.Unlock()
+
+	slog.Info("Vector store is synced", slog.String(logging.KeyVectorStoreID, vs.ID), slog.Int("uploaded", len(uploaded)),
+		slog.Int("removed", len(obsolete)), slog.Int("unchanged", len(hashes)-len(uploaded)), slog.Int("kept", kept))
+
+	return nil
+}
+
+// collectChanges reads the files and returns hashes of all of them and the files to upload.
+// Paths are made relative to the repository root, so a run from a subdirectory finds the same files.
+func collectChanges(
+	ctx context.Context, files <-chan entities.File, state entity.SyncState, attached map[string]bool,
+) (map[string]string, []entities.File, error) {
+	hashes := map[string]string{}
+
+	var changed []entities.File
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("failed to read files: %w", context.Canceled)
+		case f, ok := <-files:
+			if !ok {
+				return hashes, changed, nil
+			}
+
+			path, err := repoPath(state.Repo, f.Path)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			f.Path = path
+
+			sum := sha256.Sum256(f.Data)
+			hash := hex.EncodeToString(sum[:])
+			hashes[f.Path] = hash
+
+			if synced, ok := state.Files[f.Path]; ok && synced.Hash == hash && attached[synced.ID] {
+				continue
+			}
+
+			changed = append(changed, f)
+		}
+	}
+}
+
+func repoPath(repo, path string) (string, error) {
+	if repo == "" {
+		return path, nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(repo, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %s, run it from the repository of the file", ErrOutsideRepo, path)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// repoRoots makes the roots relative to the repository, as the paths of the state
+func repoRoots(repo string, roots []string) ([]string, error) {
+	res := make([]string, 0, len(roots))
+
+	for _, root := range roots {
+		path, err := repoPath(repo, root)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, path)
+	}
+
+	return res, nil
+}
+
+func underRoots(path string, roots []string) bool {
+	for _, root := range roots {
+		if root == "." || path == root || strings.HasPrefix(path, root+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// onDisk reports whether the file of the state still exists. An entry of an archive exists while the archive does.
+func onDisk(repo, path string) bool {
+	full := filepath.Join(repo, filepath.FromSlash(path))
+
+	if _, err := os.Lstat(full); err == nil {
+		return true
+	}
+
+	for p := filepath.Dir(full); p != repo && p != filepath.Dir(p); p = filepath.Dir(p) {
+		if info, err := os.Lstat(p); err == nil {
+			return info.Mode().IsRegular()
+		}
+	}
+
+	return false
+}
+
+// openStore returns the vector store of the state. A new one is created if there is none or it is expired.
+func (ep *explainProcessor) openStore(ctx context.Context, state *entity.SyncState) (*openai.VectorStore, bool, error) {
+	if state.VectorStoreID != "" {
+		vs, err := ep.client.VectorStores.Get(ctx, state.VectorStoreID)
+		if err != nil && !isNotFound(err) {
+			return nil, false, err
+		}
+
+		if err == nil && vs.Status != openai.VectorStoreStatusExpired {
+			ep.mutex.Lock()
+			ep.vectorStore = vs
+			ep.keepStore = true
+			ep.mutex.Unlock()
+
+			return vs, false, nil
+		}
+
+		slog.Info("Vector store of the repository is expired, creating a new one...",
+			slog.String(logging.KeyVectorStoreID, state.VectorStoreID))
+		ep.dropStore(ctx, state)
+	}
+
+	vs, err := ep.client.VectorStores.New(ctx, openai.VectorStoreNewParams{
+		Name: openai.String(PersistentStorePrefix + filepath.Base(state.Repo)),
+		ExpiresAfter: openai.VectorStoreNewParamsExpiresAfter{
+			Days: persistentStoreIdleDays,
+		},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	ep.record(storeResource(vs))
+
+	// the store is deleted by Cleanup until the state refers to it
+	ep.mutex.Lock()
+	ep.vectorStore = vs
+	ep.mutex.Unlock()
+
+	slog.Info("Vector store of the repository is created", slog.String(logging.KeyVectorStoreID, vs.ID))
+
+	return vs, true, nil
+}
+
+// dropStore deletes the vector store and the files of the state. Failures are recorded to the journal,
+// so the resources are deleted by a sweep of leftovers.
+func (ep *explainProcessor) dropStore(ctx context.Context, state *entity.SyncState) {
+	for path, f := range state.Files {
+		ep.deleteRemote(ctx, entity.RemoteResource{Kind: entity.RemoteFile, ID: f.ID, Name: path})
+	}
+
+	ep.deleteRemote(ctx, entity.RemoteResource{Kind: entity.RemoteVectorStore, ID: state.VectorStoreID})
+
+	state.VectorStoreID = ""
+	state.Files = map[string]entity.SyncedFile{}
+}
+
+// attachedFiles returns IDs of files which are processed by the vector store. Other files are uploaded again.
+func (ep *explainProcessor) attachedFiles(ctx context.Context, vectorStoreID string) (map[string]bool, error) {
+	attached := map[string]bool{}
+
+	files := ep.client.VectorStores.Files.ListAutoPaging(ctx, vectorStoreID, openai.VectorStoreFileListParams{})
+	for files.Next() {
+		if f := files.Current(); f.Status == openai.VectorStoreFileStatusCompleted {
+			attached[f.ID] = true
+		}
+	}
+
+	if err := files.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list files of vector store %s: %w", vectorStoreID, err)
+	}
+
+	return attached, nil
+}
+
+// uploadChanged uploads the files and waits until the vector store processes them
+func (ep *explainProcessor) uploadChanged(
+	ctx context.Context, vectorStoreID string, changed []entities.File,
+) ([]uploadedFile, error) {
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	files := make(chan entities.File, len(changed))
+	for _, f := range changed {
+		files <- f
+	}
+
+	close(files)
+
+	if err := ep.uploadFiles(ctx, files, PersistentFileTTL); err != nil {
+		return nil, err
+	}
+
+	ep.mutex.RLock()
+	uploaded := slices.Clone(ep.files)
+	ep.mutex.RUnlock()
+
+	fileIDs := make([]string, len(uploaded))
+	for i, f := range uploaded {
+		fileIDs[i] = f.ID
+	}
+
+	batch, err := ep.client.VectorStores.FileBatches.New(ctx, vectorStoreID, openai.VectorStoreFileBatchNewParams{
+		FileIDs: fileIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Started processing of changed files...", slog.String(logging.KeyVectorStoreID, vectorStoreID),
+		slog.Int("files", len(uploaded)))
+
+	ep.progress.Expect(progress.StageIndex, len(uploaded))
+
+	var indexed int64
+
+	batch, err = retry(ctx, func() (*openai.VectorStoreFileBatch, bool, error) {
+		b, err := ep.client.VectorStores.FileBatches.Get(ctx, vectorStoreID, batch.ID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		slog.Debug("Files processing status", slog.String(logging.KeyVectorStoreID, vectorStoreID),
+			slog.String("status", string(b.Status)), slog.Int64("completed", b.FileCounts.Completed),
+			slog.Int64("in_progress", b.FileCounts.InProgress), slog.Int64("failed", b.FileCounts.Failed))
+
+		ep.reportIndexed(b.FileCounts.Total-b.FileCounts.InProgress, &indexed)
+
+		return b, b.Status != openai.VectorStoreFileBatchStatusInProgress, nil
+	}, time.Second, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.FileCounts.Failed != 0 || batch.Status != openai.VectorStoreFileBatchStatusCompleted {
+		return nil, ErrFailedToProcessFiles
+	}
+
+	ep.progress.Finish(progress.StageIndex)
+
+	return uploaded, nil
+}
+
+// adopt marks resources referred by the saved state as kept, so Cleanup and sweeps of leftovers don't delete them
+func (ep *explainProcessor) adopt(vs *openai.VectorStore, created bool, uploaded []uploadedFile) {
+	resources := make([]entity.RemoteResource, 0, len(uploaded)+1)
+
+	for _, f := range uploaded {
+		resources = append(resources, entity.RemoteResource{Kind: entity.RemoteFile, ID: f.ID, Name: f.path})
+	}
+
+	if created {
+		resources = append(resources, storeResource(vs))
+	}
+
+	ep.mutex.Lock()
+	ep.files = nil
+	ep.keepStore = true
+	ep.mutex.Unlock()
+
+	for _, r := range resources {
+		if err := ep.journal.Adopted(r); err != nil {
+			slog.Warn("Failed to record an adoption to the journal", append(r.LogAttrs(), logging.Err(err))...)
+		}
+	}
+}
+
+// removeFiles detaches obsolete files from the vector store before deleting them,
+// so the store stops answering with their content at once
+func (ep *explainProcessor) removeFiles(
+	ctx context.Context, vectorStoreID string, files []entity.RemoteResource, attached map[string]bool,
+) {
+	for _, f := range files {
+		if attached[f.ID] {
+			_, err := ep.client.VectorStores.Files.Delete(ctx, vectorStoreID, f.ID)
+			if err != nil && !isNotFound(err) {
+				slog.Warn("Failed to detach file from vector store", slog.String(logging.KeyFileID, f.ID),
+					slog.String(logging.KeyVectorStoreID, vectorStoreID), logging.Err(err))
+			}
+		}
+
+		ep.deleteRemote(ctx, f)
+	}
+}
+
+// deleteRemote treats a missing resource as deleted. A resource which failed to be deleted is recorded
+// to the journal, so a sweep of leftovers retries.
+func (ep *explainProcessor) deleteRemote(ctx context.Context, r entity.RemoteResource) {
+	var err error
+
+	switch r.Kind {
+	case entity.RemoteVectorStore:
+		_, err = ep.client.VectorStores.Delete(ctx, r.ID)
+	default:
+		_, err = ep.client.Files.Delete(ctx, r.ID)
+	}
+
+	if err != nil && !isNotFound(err) {
+		slog.Warn("Failed to delete a remote resource", append(r.LogAttrs(), logging.Err(err))...)
+		ep.record(r)
+
+		return
+	}
+
+	slog.Debug("Remote resource is deleted", r.LogAttrs()...)
+}
+
+func storeResource(vs *openai.VectorStore) entity.RemoteResource {
+	return entity.RemoteResource{
+		Kind:      entity.RemoteVectorStore,
+		ID:        vs.ID,
+		Name:      vs.Name,
+		CreatedAt: time.Unix(vs.CreatedAt, 0),
+	}
+}
+
+func isNotFound(err error) bool {
+	var apiErr *openai.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
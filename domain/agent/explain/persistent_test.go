@@ -0,0 +1,328 @@
+package explain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/agent/syncstate"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	resourcejournal_mock "github.com/yaroslav-koval/hange/mocks/resourcejournal"
+	syncstatestore_mock "github.com/yaroslav-koval/hange/mocks/syncstatestore"
+)
+
+func TestExplainProcessor_syncFiles(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /vector_stores/vs_repo":
+			_, _ = w.Write([]byte(`{"id":"vs_repo","object":"vector_store","status":"completed"}`))
+		case "GET /vector_stores/vs_repo/files":
+			writeTestList(t, w, `[
+				{"id":"file_a","object":"vector_store.file","status":"completed"},
+				{"id":"file_b","object":"vector_store.file","status":"completed"},
+				{"id":"file_c","object":"vector_store.file","status":"completed"}
+			]`)
+		case "POST /files":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			name := r.MultipartForm.File["file"][0].Filename
+			require.Equal(t, "2592000", r.FormValue("expires_after[seconds]"))
+
+			// the echoed file name differs from the uploaded one, the state is keyed by the local path
+			_, _ = w.Write([]byte(`{"id":"new_` + name + `","object":"file","filename":"upload.txt"}`))
+		case "POST /vector_stores/vs_repo/file_batches":
+			_, _ = w.Write([]byte(`{"id":"batch_1","object":"vector_store.files_batch","status":"in_progress"}`))
+		case "GET /vector_stores/vs_repo/file_batches/batch_1":
+			_, _ = w.Write([]byte(`{"id":"batch_1","object":"vector_store.files_batch","status":"completed",
+				"file_counts":{"completed":2,"total":2}}`))
+		case "DELETE /vector_stores/vs_repo/files/file_b", "DELETE /vector_stores/vs_repo/files/file_c",
+			"DELETE /files/file_b", "DELETE /files/file_c":
+			_, _ = w.Write([]byte(`{"deleted":true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	state := syncstatestore_mock.NewMockSyncStateStore(t)
+	state.EXPECT().Load().Return(entity.SyncState{
+		VectorStoreID: "vs_repo",
+		Files: map[string]entity.SyncedFile{
+			"a.go": {ID: "file_a", Hash: testHash("a")},
+			"b.go": {ID: "file_b", Hash: testHash("old b")},
+			"c.go": {ID: "file_c", Hash: testHash("c")},
+		},
+	}, nil)
+	state.EXPECT().Save(entity.SyncState{
+		VectorStoreID: "vs_repo",
+		Files: map[string]entity.SyncedFile{
+			"a.go": {ID: "file_a", Hash: testHash("a")},
+			"b.go": {ID: "new_b.go", Hash: testHash("b")},
+			"d.go": {ID: "new_d.go", Hash: testHash("d")},
+		},
+	}).Return(nil)
+
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Created(mock.Anything).Return(nil).Times(2)
+	journal.EXPECT().Adopted(mock.MatchedBy(func(r entity.RemoteResource) bool {
+		return r.Kind == entity.RemoteFile && strings.HasPrefix(r.ID, "new_")
+	})).Return(nil).Times(2)
+
+	ep := newTestExplainProcessor(t, client)
+	ep.journal = journal
+	ep.state = state
+
+	files := make(chan entities.File, 3)
+	files <- entities.File{Path: "a.go", Data: []byte("a")}
+	files <- entities.File{Path: "b.go", Data: []byte("b")}
+	files <- entities.File{Path: "d.go", Data: []byte("d")}
+	close(files)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, ep.ProcessFiles(ctx, files, entity.ExplainOptions{Persistent: true}))
+
+	assert.Equal(t, []string{"a.go", "b.go", "d.go"}, ep.fileNames)
+	assert.Equal(t, "vs_repo", ep.vectorStoreID())
+
+	mu.Lock()
+	assert.Contains(t, requests, "DELETE /vector_stores/vs_repo/files/file_c")
+	assert.Contains(t, requests, "DELETE /files/file_b")
+	mu.Unlock()
+
+	// neither the kept store nor the adopted files are deleted
+	ep.Cleanup(context.Background())
+}
+
+func TestExplainProcessor_syncFiles_fresh(t *testing.T) {
+	t.Parallel()
+
+	var deleted []string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "DELETE /files/file_a", "DELETE /vector_stores/vs_old":
+			deleted = append(deleted, r.URL.Path)
+			_, _ = w.Write([]byte(`{"deleted":true}`))
+		case "POST /vector_stores":
+			_, _ = w.Write([]byte(`{"id":"vs_new","object":"vector_store","name":"hange-repo_project"}`))
+		case "GET /vector_stores/vs_new/files":
+			writeTestList(t, w, `[]`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	repo := filepath.Join(t.TempDir(), "project")
+
+	state := syncstatestore_mock.NewMockSyncStateStore(t)
+	state.EXPECT().Load().Return(entity.SyncState{
+		Repo:          repo,
+		VectorStoreID: "vs_old",
+		Files:         map[string]entity.SyncedFile{"a.go": {ID: "file_a", Hash: testHash("a")}},
+	}, nil)
+	state.EXPECT().Save(entity.SyncState{
+		Repo:          repo,
+		VectorStoreID: "vs_new",
+		Files:         map[string]entity.SyncedFile{},
+	}).Return(nil)
+
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Created(mock.MatchedBy(func(r entity.RemoteResource) bool {
+		return r.ID == "vs_new"
+	})).Return(nil)
+	journal.EXPECT().Adopted(mock.MatchedBy(func(r entity.RemoteResource) bool {
+		return r.ID == "vs_new" && r.Name == PersistentStorePrefix+"project"
+	})).Return(nil)
+
+	ep := newTestExplainProcessor(t, client)
+	ep.journal = journal
+	ep.state = state
+
+	files := make(chan entities.File)
+	close(files)
+
+	require.NoError(t, ep.ProcessFiles(context.Background(), files, entity.ExplainOptions{Fresh: true}))
+	assert.Equal(t, []string{"/files/file_a", "/vector_stores/vs_old"}, deleted)
+	assert.True(t, ep.keepStore)
+}
+
+func TestExplainProcessor_syncFiles_disjointInputs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		uploaded int
+		attached = map[string]bool{}
+		deleted  []string
+	)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch path := r.Method + " " + r.URL.Path; {
+		case path == "POST /vector_stores":
+			_, _ = w.Write([]byte(`{"id":"vs_repo","object":"vector_store","name":"hange-repo_project"}`))
+		case path == "GET /vector_stores/vs_repo":
+			_, _ = w.Write([]byte(`{"id":"vs_repo","object":"vector_store","status":"completed"}`))
+		case path == "GET /vector_stores/vs_repo/files":
+			var files []string
+			for id := range attached {
+				files = append(files, `{"id":"`+id+`","object":"vector_store.file","status":"completed"}`)
+			}
+
+			writeTestList(t, w, "["+strings.Join(files, ",")+"]")
+		case path == "POST /files":
+			uploaded++
+			_, _ = w.Write([]byte(`{"id":"file_` + strconv.Itoa(uploaded) + `","object":"file"}`))
+		case path == "POST /vector_stores/vs_repo/file_batches":
+			var batch struct {
+				FileIDs []string `json:"file_ids"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+
+			for _, id := range batch.FileIDs {
+				attached[id] = true
+			}
+
+			_, _ = w.Write([]byte(`{"id":"batch","object":"vector_store.files_batch","status":"completed"}`))
+		case path == "GET /vector_stores/vs_repo/file_batches/batch":
+			_, _ = w.Write([]byte(`{"id":"batch","object":"vector_store.files_batch","status":"completed"}`))
+		case strings.HasPrefix(path, "DELETE /vector_stores/vs_repo/files/"):
+			delete(attached, strings.TrimPrefix(r.URL.Path, "/vector_stores/vs_repo/files/"))
+			_, _ = w.Write([]byte(`{"deleted":true}`))
+		case strings.HasPrefix(path, "DELETE /files/"):
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/files/"))
+			_, _ = w.Write([]byte(`{"deleted":true}`))
+		default:
+			t.Errorf("unexpected request %s", path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	repo := filepath.Join(t.TempDir(), "project")
+	for _, dir := range []string{"cmd", "docs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, dir), 0o755))
+	}
+
+	main := filepath.Join(repo, "cmd", "main.go")
+	readme := filepath.Join(repo, "docs", "readme.md")
+
+	require.NoError(t, os.WriteFile(main, []byte("main"), 0o644))
+	require.NoError(t, os.WriteFile(readme, []byte("readme"), 0o644))
+
+	ep := newTestExplainProcessor(t, client)
+	ep.state = syncstate.NewFileSyncStateStore(filepath.Join(t.TempDir(), "state.json"), repo)
+
+	run := func(root, path string) entity.SyncState {
+		t.Helper()
+
+		files := make(chan entities.File, 1)
+		files <- entities.File{Path: path, Data: []byte(filepath.Base(path))}
+		close(files)
+
+		opts := entity.ExplainOptions{Persistent: true, Roots: []string{root}}
+		require.NoError(t, ep.ProcessFiles(context.Background(), files, opts))
+		ep.Cleanup(context.Background())
+
+		state, err := ep.state.Load()
+		require.NoError(t, err)
+
+		return state
+	}
+
+	run(filepath.Dir(main), main)
+	state := run(filepath.Dir(readme), readme)
+
+	// the files of the first input are kept, nothing is uploaded again
+	assert.Equal(t, []string{"cmd/main.go", "docs/readme.md"}, slices.Sorted(maps.Keys(state.Files)))
+	assert.Empty(t, deleted)
+	assert.Equal(t, 2, uploaded)
+
+	// a file deleted from the disk is removed by a sync of another input
+	require.NoError(t, os.Remove(main))
+
+	state = run(filepath.Dir(readme), readme)
+
+	assert.Equal(t, []string{"docs/readme.md"}, slices.Sorted(maps.Keys(state.Files)))
+	assert.Equal(t, []string{"file_1"}, deleted)
+	assert.Equal(t, 2, uploaded)
+}
+
+func TestOnDisk(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "release.zip"), nil, 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(repo, "cmd"), 0o755))
+
+	assert.True(t, onDisk(repo, "release.zip"))
+	// an entry of an archive exists while the archive does
+	assert.True(t, onDisk(repo, "release.zip/cmd/main.go"))
+	assert.False(t, onDisk(repo, "cmd/main.go"))
+	assert.False(t, onDisk(repo, "docs/readme.md"))
+}
+
+func TestRepoPath(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+
+	path, err := repoPath(repo, filepath.Join(repo, "cmd", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "cmd/main.go", path)
+
+	path, err = repoPath("", "main.go")
+	require.NoError(t, err)
+	assert.Equal(t, "main.go", path)
+
+	// a state of one repository can't refer to files of another one
+	_, err = repoPath(repo, filepath.Join(filepath.Dir(repo), "other", "main.go"))
+	require.ErrorIs(t, err, ErrOutsideRepo)
+}
+
+func testHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeTestList(t *testing.T, w http.ResponseWriter, data string) {
+	t.Helper()
+
+	_, err := w.Write([]byte(`{"object":"list","has_more":false,"data":` + data + `}`))
+	require.NoError(t, err)
+}
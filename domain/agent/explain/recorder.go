@@ -0,0 +1,86 @@
+package explain
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// NewRecorderExplainProcessor creates a processor which prints the files and the request of the OpenAI processor
+// with the same settings instead of uploading and sending them.
+func NewRecorderExplainProcessor(w io.Writer, model, instruction string) agent.ExplainProcessor {
+	model, instruction = withDefaults(model, instruction)
+
+	return &recorderExplainProcessor{
+		w:           w,
+		model:       model,
+		instruction: instruction,
+	}
+}
+
+type recorderExplainProcessor struct {
+	w           io.Writer
+	model       string
+	instruction string
+	files       []dryrun.File
+	persistent  bool
+}
+
+func (ep *recorderExplainProcessor) ProcessFiles(
+	ctx context.Context, files <-chan entities.File, opts entity.ExplainOptions,
+) error {
+	ep.persistent = opts.Persistent || opts.Fresh
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to read files: %w", context.Canceled)
+		case f, ok := <-files:
+			if !ok {
+				return nil
+			}
+
+			ep.files = append(ep.files, dryrun.File{Path: f.Path, Size: len(f.Data)})
+		}
+	}
+}
+
+func (ep *recorderExplainProcessor) ExecuteExplainRequest(_ context.Context) (entities.Completion, error) {
+	// files are read concurrently, the order is restored for a stable output
+	slices.SortFunc(ep.files, func(a, b dryrun.File) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	fileNames := make([]string, len(ep.files))
+	for i, f := range ep.files {
+		fileNames[i] = f.Path
+	}
+
+	err := dryrun.Print(ep.w, dryrun.Payload{
+		Model:        ep.model,
+		Instructions: ep.instruction,
+		Input:        buildInput(fileNames),
+		Files:        ep.files,
+	})
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	if ep.persistent {
+		_, err = fmt.Fprintln(ep.w, "Persistent vector store: only changed files are uploaded, the estimate counts all files")
+		if err != nil {
+			return entities.Completion{}, err
+		}
+	}
+
+	return entities.Completion{}, dryrun.ErrDryRun
+}
+
+func (ep *recorderExplainProcessor) Cleanup(context.Context) {}
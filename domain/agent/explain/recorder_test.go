@@ -0,0 +1,37 @@
+package explain
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+func TestRecorderExplainProcessor(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	ep := NewRecorderExplainProcessor(buf, "", "")
+
+	files := make(chan entities.File, 2)
+	files <- entities.File{Path: "b.go", Data: []byte("package b")}
+	files <- entities.File{Path: "a.go", Data: []byte("package a")}
+	close(files)
+
+	require.NoError(t, ep.ProcessFiles(context.Background(), files, entity.ExplainOptions{Persistent: true}))
+
+	_, err := ep.ExecuteExplainRequest(context.Background())
+	require.ErrorIs(t, err, dryrun.ErrDryRun)
+
+	out := buf.String()
+	assert.Contains(t, out, "Model: "+DefaultModel)
+	assert.Contains(t, out, explainInstruction)
+	assert.Contains(t, out, buildInput([]string{"a.go", "b.go"}))
+	assert.Contains(t, out, "Files (2):")
+	assert.Contains(t, out, "Persistent vector store")
+}
@@ -0,0 +1,29 @@
+package explain
+
+import (
+	"context"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// NewUnavailableExplainProcessor creates a processor failing with err, e.g. if the token can't be read.
+// It defers the failure until files are explained, so commands not explaining files still run.
+func NewUnavailableExplainProcessor(err error) agent.ExplainProcessor {
+	return &unavailableExplainProcessor{err: err}
+}
+
+type unavailableExplainProcessor struct {
+	err error
+}
+
+func (ep *unavailableExplainProcessor) ProcessFiles(context.Context, <-chan entities.File, entity.ExplainOptions) error {
+	return ep.err
+}
+
+func (ep *unavailableExplainProcessor) ExecuteExplainRequest(context.Context) (entities.Completion, error) {
+	return entities.Completion{}, ep.err
+}
+
+func (ep *unavailableExplainProcessor) Cleanup(context.Context) {}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/pkg/atomicfile"
+)
+
+// FileName is a name of the health file in the config directory.
+const FileName = "health.json"
+
+// DefaultCooldown is a time an unhealthy backend is skipped for if the config doesn't set it.
+const DefaultCooldown = 5 * time.Minute
+
+// NewFileBackendHealth creates a health store in a JSON file, so cooldowns outlive a run.
+// Concurrent runs are not coordinated, a lost mark only makes a backend be tried once more.
+func NewFileBackendHealth(path string, cooldown time.Duration) agent.BackendHealth {
+	return &fileBackendHealth{
+		path:     path,
+		cooldown: cooldown,
+		now:      time.Now,
+		mutex:    &sync.Mutex{},
+	}
+}
+
+type fileBackendHealth struct {
+	path     string
+	cooldown time.Duration
+	now      func() time.Time
+	mutex    *sync.Mutex
+}
+
+type state struct {
+	Backends map[string]backendState `json:"backends"`
+}
+
+type backendState struct {
+	UnhealthyUntil time.Time `json:"unhealthy_until"`
+	Error          string    `json:"error,omitempty"`
+}
+
+func (h *fileBackendHealth) Healthy(backend string) (bool, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	s, err := h.load()
+	if err != nil {
+		return true, err
+	}
+
+	b, ok := s.Backends[backend]
+
+	return !ok || !h.now().Before(b.UnhealthyUntil), nil
+}
+
+func (h *fileBackendHealth) MarkUnhealthy(backend string, cause error) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	s, err := h.load()
+	if err != nil {
+		return err
+	}
+
+	b := backendState{UnhealthyUntil: h.now().Add(h.cooldown).UTC()}
+	if cause != nil {
+		b.Error = cause.Error()
+	}
+
+	s.Backends[backend] = b
+
+	return h.save(s)
+}
+
+func (h *fileBackendHealth) MarkHealthy(backend string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	s, err := h.load()
+	if err != nil {
+		return err
+	}
+
+	// the file is not written by runs of healthy backends
+	if _, ok := s.Backends[backend]; !ok {
+		return nil
+	}
+
+	delete(s.Backends, backend)
+
+	return h.save(s)
+}
+
+func (h *fileBackendHealth) load() (state, error) {
+	s := state{}
+
+	data, err := os.ReadFile(h.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return s, err
+	}
+
+	if err == nil {
+		if err = json.Unmarshal(data, &s); err != nil {
+			return s, fmt.Errorf("failed to parse %s: %w", h.path, err)
+		}
+	}
+
+	if s.Backends == nil {
+		s.Backends = map[string]backendState{}
+	}
+
+	return s, nil
+}
+
+func (h *fileBackendHealth) save(s state) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(h.path, data, 0600)
+}
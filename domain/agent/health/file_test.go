@@ -0,0 +1,64 @@
+package health
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBackendHealth(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	h := NewFileBackendHealth(path, time.Minute).(*fileBackendHealth)
+	h.now = func() time.Time { return now }
+
+	healthy, err := h.Healthy("work")
+	require.NoError(t, err)
+	assert.True(t, healthy)
+
+	require.NoError(t, h.MarkHealthy("work"))
+	assert.NoFileExists(t, path, "healthy backends are not written")
+
+	require.NoError(t, h.MarkUnhealthy("work", errors.New("503 Service Unavailable")))
+
+	healthy, err = h.Healthy("work")
+	require.NoError(t, err)
+	assert.False(t, healthy)
+
+	healthy, err = h.Healthy("local")
+	require.NoError(t, err)
+	assert.True(t, healthy)
+
+	// another run reads the file
+	other := NewFileBackendHealth(path, time.Minute).(*fileBackendHealth)
+	other.now = func() time.Time { return now.Add(time.Minute) }
+
+	healthy, err = other.Healthy("work")
+	require.NoError(t, err)
+	assert.True(t, healthy, "the cooldown is over")
+
+	require.NoError(t, h.MarkHealthy("work"))
+
+	healthy, err = h.Healthy("work")
+	require.NoError(t, err)
+	assert.True(t, healthy)
+}
+
+func TestFileBackendHealth_Broken(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+
+	healthy, err := NewFileBackendHealth(path, time.Minute).Healthy("work")
+	require.ErrorContains(t, err, path)
+	assert.True(t, healthy)
+}
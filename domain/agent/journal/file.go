@@ -0,0 +1,223 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/pkg/atomicfile"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+// FileName is a name of the journal in the config directory.
+const FileName = "journal.jsonl"
+
+const (
+	opCreated = "created"
+	opDeleted = "deleted"
+	// opAdopted resolves a created resource like opDeleted, but the resource is kept by a persistent vector store
+	opAdopted = "adopted"
+)
+
+const (
+	// maxAge is an expiration of vector stores. Older resources are leftovers even if their run can't be checked,
+	// e.g. it is on another host sharing the config directory.
+	maxAge = 24 * time.Hour
+	// compactSize is a size of the journal after which deleted resources are removed from it
+	compactSize = 64 << 10 // 64 KiB
+)
+
+// NewFileJournal creates an append-only JSONL journal. Resources of other profiles are kept in the journal,
+// but they are not leftovers of this profile, since they can't be deleted with its token.
+func NewFileJournal(path, profileName string) agent.ResourceJournal {
+	host, _ := os.Hostname()
+
+	return &fileJournal{
+		path:    path,
+		profile: profileName,
+		pid:     os.Getpid(),
+		host:    host,
+		now:     time.Now,
+		mutex:   &sync.Mutex{},
+	}
+}
+
+type fileJournal struct {
+	path    string
+	profile string
+	pid     int
+	host    string
+	now     func() time.Time
+	mutex   *sync.Mutex
+}
+
+type record struct {
+	Op        string                    `json:"op"`
+	Kind      entity.RemoteResourceKind `json:"kind"`
+	ID        string                    `json:"id"`
+	Name      string                    `json:"name,omitempty"`
+	CreatedAt time.Time                 `json:"created_at,omitzero"`
+	Profile   string                    `json:"profile"`
+	PID       int                       `json:"pid"`
+	Host      string                    `json:"host"`
+	Time      time.Time                 `json:"time"`
+}
+
+func (j *fileJournal) Created(r entity.RemoteResource) error {
+	return j.append(j.newRecord(opCreated, r))
+}
+
+func (j *fileJournal) Deleted(r entity.RemoteResource) error {
+	return j.append(j.newRecord(opDeleted, r))
+}
+
+func (j *fileJournal) Adopted(r entity.RemoteResource) error {
+	return j.append(j.newRecord(opAdopted, r))
+}
+
+func (j *fileJournal) Leftovers() ([]entity.RemoteResource, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	pending := pendingRecords(data)
+
+	if len(data) > compactSize {
+		j.compact(pending)
+	}
+
+	var leftovers []entity.RemoteResource
+
+	for _, rec := range pending {
+		if rec.Profile == j.profile && j.isLeftover(rec) {
+			leftovers = append(leftovers, entity.RemoteResource{
+				Kind:      rec.Kind,
+				ID:        rec.ID,
+				Name:      rec.Name,
+				CreatedAt: rec.CreatedAt,
+			})
+		}
+	}
+
+	return leftovers, nil
+}
+
+func (j *fileJournal) isLeftover(rec record) bool {
+	if j.now().Sub(rec.Time) > maxAge {
+		return true
+	}
+
+	if rec.Host != j.host || rec.PID == j.pid {
+		return false
+	}
+
+	return !processAlive(rec.PID)
+}
+
+// pendingRecords returns created records without a deletion or adoption, ordered by time.
+// A line broken by a crash is skipped.
+func pendingRecords(data []byte) []record {
+	pending := map[string]record{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			slog.Debug("Skipping a broken journal line", logging.Err(err))
+			continue
+		}
+
+		switch rec.Op {
+		case opCreated:
+			pending[rec.ID] = rec
+		case opDeleted, opAdopted:
+			delete(pending, rec.ID)
+		default:
+		}
+	}
+
+	records := slices.Collect(maps.Values(pending))
+
+	slices.SortFunc(records, func(a, b record) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return records
+}
+
+// compact rewrites the journal with pending records only. A record appended by another process while
+// the journal is rewritten may be lost, then the resource is deleted by its expiration.
+func (j *fileJournal) compact(pending []record) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+
+	for _, rec := range pending {
+		if err := enc.Encode(rec); err != nil {
+			slog.Warn("Failed to compact journal", logging.Err(err))
+			return
+		}
+	}
+
+	if err := atomicfile.WriteFile(j.path, buf.Bytes(), 0600); err != nil {
+		slog.Warn("Failed to compact journal", logging.Err(err))
+	}
+}
+
+func (j *fileJournal) newRecord(op string, r entity.RemoteResource) record {
+	return record{
+		Op:        op,
+		Kind:      r.Kind,
+		ID:        r.ID,
+		Name:      r.Name,
+		CreatedAt: r.CreatedAt,
+		Profile:   j.profile,
+		PID:       j.pid,
+		Host:      j.host,
+		Time:      j.now().UTC(),
+	}
+}
+
+// append writes a record by a single write to a file opened in append mode, so records of concurrent runs
+// are not mixed. The file is synced, so the record survives a crash right after it.
+func (j *fileJournal) append(rec record) (err error) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return f.Sync()
+}
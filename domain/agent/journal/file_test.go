@@ -0,0 +1,159 @@
+package journal
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+)
+
+// deadPID is above the PID limit of any OS
+const deadPID = math.MaxInt32
+
+func TestFileJournal_Leftovers(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	j := newTestJournal(path, "work")
+
+	now := j.now()
+	host := j.host
+
+	crashed := entity.RemoteResource{Kind: entity.RemoteVectorStore, ID: "vs_crashed", Name: "hange_1"}
+	writeRecords(t, path,
+		record{Op: opCreated, Kind: crashed.Kind, ID: crashed.ID, Name: crashed.Name, Profile: "work", PID: deadPID,
+			Host: host, Time: now},
+		// deleted by its run
+		record{Op: opCreated, Kind: entity.RemoteFile, ID: "file_deleted", Profile: "work", PID: deadPID, Host: host,
+			Time: now},
+		record{Op: opDeleted, Kind: entity.RemoteFile, ID: "file_deleted", Profile: "work", PID: deadPID, Host: host,
+			Time: now},
+		// the token of another profile can't delete it
+		record{Op: opCreated, Kind: entity.RemoteFile, ID: "file_home", Profile: "home", PID: deadPID, Host: host,
+			Time: now},
+		// the run on another host can't be checked until the resource expires
+		record{Op: opCreated, Kind: entity.RemoteFile, ID: "file_remote", Profile: "work", PID: deadPID, Host: "other",
+			Time: now},
+		record{Op: opCreated, Kind: entity.RemoteFile, ID: "file_expired", Profile: "work", PID: deadPID, Host: "other",
+			Time: now.Add(-maxAge - time.Minute)},
+	)
+
+	// a line broken by a crash is skipped
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"created","id":"fi`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// resources of this run are not leftovers
+	require.NoError(t, j.Created(entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_running"}))
+
+	leftovers, err := j.Leftovers()
+	require.NoError(t, err)
+
+	ids := make([]string, len(leftovers))
+	for i, r := range leftovers {
+		ids[i] = r.ID
+	}
+
+	assert.ElementsMatch(t, []string{"vs_crashed", "file_expired"}, ids)
+
+	require.NoError(t, j.Deleted(crashed))
+
+	leftovers, err = j.Leftovers()
+	require.NoError(t, err)
+	require.Len(t, leftovers, 1)
+	assert.Equal(t, "file_expired", leftovers[0].ID)
+}
+
+func TestFileJournal_Adopted(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	j := newTestJournal(path, "work")
+
+	kept := entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_kept"}
+	writeRecords(t, path, record{Op: opCreated, Kind: kept.Kind, ID: kept.ID, Profile: "work", PID: deadPID,
+		Host: j.host, Time: j.now()})
+
+	require.NoError(t, j.Adopted(kept))
+
+	leftovers, err := j.Leftovers()
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestFileJournal_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	leftovers, err := newTestJournal(filepath.Join(t.TempDir(), FileName), "work").Leftovers()
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestFileJournal_Compact(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	j := newTestJournal(path, "work")
+
+	for j.size(t) <= compactSize {
+		r := entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_" + strings.Repeat("x", 100)}
+		require.NoError(t, j.Created(r))
+		require.NoError(t, j.Deleted(r))
+	}
+
+	pending := entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_pending"}
+	require.NoError(t, j.Created(pending))
+
+	_, err := j.Leftovers()
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+	assert.Contains(t, string(data), "file_pending")
+}
+
+func newTestJournal(path, profileName string) *fileJournal {
+	j := NewFileJournal(path, profileName).(*fileJournal)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	j.now = func() time.Time { return now }
+
+	return j
+}
+
+func (j *fileJournal) size(t *testing.T) int64 {
+	t.Helper()
+
+	info, err := os.Stat(j.path)
+	if os.IsNotExist(err) {
+		return 0
+	}
+
+	require.NoError(t, err)
+
+	return info.Size()
+}
+
+func writeRecords(t *testing.T, path string, records ...record) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+
+	enc := json.NewEncoder(f)
+	for _, rec := range records {
+		require.NoError(t, enc.Encode(rec))
+	}
+
+	require.NoError(t, f.Close())
+}
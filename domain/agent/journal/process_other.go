@@ -0,0 +1,17 @@
+//go:build !unix
+
+package journal
+
+import "os"
+
+// processAlive relies on FindProcess opening a handle of the process where signals are not available.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	_ = p.Release()
+
+	return true
+}
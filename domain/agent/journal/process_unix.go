@@ -0,0 +1,15 @@
+//go:build unix
+
+package journal
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive sends no signal, it only checks the process exists. EPERM means it exists, but belongs to another user.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
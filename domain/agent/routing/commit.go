@@ -0,0 +1,57 @@
+package routing
+
+import (
+	"context"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// NewCommitRouter creates a processor trying the backends in order until one answers.
+// Only a retriable error moves to the next backend, others are returned at once.
+func NewCommitRouter(backends []agent.CommitBackend, health agent.BackendHealth) (agent.CommitProcessor, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoBackends
+	}
+
+	return &commitRouter{
+		router:   router{health: health},
+		backends: backends,
+	}, nil
+}
+
+type commitRouter struct {
+	router
+	backends []agent.CommitBackend
+}
+
+func (r *commitRouter) GenCommitMessage(ctx context.Context, data entity.CommitData) (entities.Completion, error) {
+	names := make([]string, len(r.backends))
+	for i, b := range r.backends {
+		names[i] = b.Name
+	}
+
+	var lastErr error
+
+	for _, i := range r.order(names) {
+		b := r.backends[i]
+
+		c, err := b.Processor.GenCommitMessage(ctx, data)
+		if err == nil {
+			r.answered(b.Name)
+			c.Backend = b.Name
+
+			return c, nil
+		}
+
+		if !Retriable(ctx, err) {
+			return entities.Completion{}, err
+		}
+
+		r.failed(b.Name, err)
+		lastErr = err
+	}
+
+	return entities.Completion{}, allFailed(lastErr)
+}
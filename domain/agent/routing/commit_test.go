@@ -0,0 +1,118 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	backendhealth_mock "github.com/yaroslav-koval/hange/mocks/backendhealth"
+	commitprocessor_mock "github.com/yaroslav-koval/hange/mocks/commitprocessor"
+)
+
+func TestCommitRouter_GenCommitMessage(t *testing.T) {
+	t.Parallel()
+
+	data := entity.CommitData{Diff: "+x"}
+
+	t.Run("fails over on a retriable error", func(t *testing.T) {
+		t.Parallel()
+
+		work := commitprocessor_mock.NewMockCommitProcessor(t)
+		work.EXPECT().GenCommitMessage(mock.Anything, data).Return(entities.Completion{}, testAPIError(http.StatusBadGateway))
+
+		local := commitprocessor_mock.NewMockCommitProcessor(t)
+		local.EXPECT().GenCommitMessage(mock.Anything, data).Return(entities.Completion{Text: "Add x"}, nil)
+
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy(mock.Anything).Return(true, nil).Times(2)
+		health.EXPECT().MarkUnhealthy("work", mock.Anything).Return(nil)
+		health.EXPECT().MarkHealthy("local").Return(nil)
+
+		r, err := NewCommitRouter([]agent.CommitBackend{
+			{Name: "work", Processor: work},
+			{Name: "local", Processor: local},
+		}, health)
+		require.NoError(t, err)
+
+		c, err := r.GenCommitMessage(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, entities.Completion{Text: "Add x", Backend: "local"}, c)
+	})
+
+	t.Run("backend cooling down is tried last", func(t *testing.T) {
+		t.Parallel()
+
+		work := commitprocessor_mock.NewMockCommitProcessor(t)
+
+		local := commitprocessor_mock.NewMockCommitProcessor(t)
+		local.EXPECT().GenCommitMessage(mock.Anything, data).Return(entities.Completion{Text: "Add x"}, nil)
+
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy("work").Return(false, nil)
+		health.EXPECT().Healthy("local").Return(true, nil)
+		health.EXPECT().MarkHealthy("local").Return(nil)
+
+		r, err := NewCommitRouter([]agent.CommitBackend{
+			{Name: "work", Processor: work},
+			{Name: "local", Processor: local},
+		}, health)
+		require.NoError(t, err)
+
+		c, err := r.GenCommitMessage(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "local", c.Backend)
+	})
+
+	t.Run("other errors are returned at once", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := errors.New("invalid input")
+
+		work := commitprocessor_mock.NewMockCommitProcessor(t)
+		work.EXPECT().GenCommitMessage(mock.Anything, data).Return(entities.Completion{}, invalid)
+
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy(mock.Anything).Return(true, nil).Times(2)
+
+		r, err := NewCommitRouter([]agent.CommitBackend{
+			{Name: "work", Processor: work},
+			{Name: "local", Processor: commitprocessor_mock.NewMockCommitProcessor(t)},
+		}, health)
+		require.NoError(t, err)
+
+		_, err = r.GenCommitMessage(context.Background(), data)
+		require.ErrorIs(t, err, invalid)
+	})
+
+	t.Run("all backends failed", func(t *testing.T) {
+		t.Parallel()
+
+		work := commitprocessor_mock.NewMockCommitProcessor(t)
+		work.EXPECT().GenCommitMessage(mock.Anything, data).Return(entities.Completion{}, context.DeadlineExceeded)
+
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy("work").Return(true, nil)
+		health.EXPECT().MarkUnhealthy("work", context.DeadlineExceeded).Return(nil)
+
+		r, err := NewCommitRouter([]agent.CommitBackend{{Name: "work", Processor: work}}, health)
+		require.NoError(t, err)
+
+		_, err = r.GenCommitMessage(context.Background(), data)
+		require.ErrorIs(t, err, ErrAllBackendsFailed)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("no backends", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCommitRouter(nil, backendhealth_mock.NewMockBackendHealth(t))
+		require.ErrorIs(t, err, ErrNoBackends)
+	})
+}
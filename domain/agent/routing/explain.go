@@ -0,0 +1,167 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+var ErrFilesNotProcessed = errors.New("files are not processed by any backend")
+
+// NewExplainRouter creates a processor which fails over to the next backend if processing files or
+// the request fails with a retriable error. Files are kept in memory if there are several backends,
+// since the next backend uploads them again. A failed backend is cleaned up before the next one is tried.
+func NewExplainRouter(backends []agent.ExplainBackend, health agent.BackendHealth) (agent.ExplainProcessor, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoBackends
+	}
+
+	return &explainRouter{
+		router:   router{health: health},
+		backends: backends,
+		current:  -1,
+	}, nil
+}
+
+type explainRouter struct {
+	router
+	backends []agent.ExplainBackend
+	opts     entity.ExplainOptions
+	files    []entities.File
+	// pending are indexes of backends which are not tried yet
+	pending []int
+	// current is an index of the backend which processed the files, -1 if none did
+	current int
+	lastErr error
+}
+
+func (r *explainRouter) ProcessFiles(
+	ctx context.Context, files <-chan entities.File, opts entity.ExplainOptions,
+) error {
+	names := make([]string, len(r.backends))
+	for i, b := range r.backends {
+		names[i] = b.Name
+	}
+
+	// a router of a long-lived app is reused, nothing of the previous run is kept
+	r.opts = opts
+	r.pending = r.order(names)
+	r.files = nil
+	r.current = -1
+	r.lastErr = nil
+
+	if len(r.backends) == 1 {
+		return r.processNext(ctx, files)
+	}
+
+	if err := r.buffer(ctx, files); err != nil {
+		return err
+	}
+
+	return r.processNext(ctx, nil)
+}
+
+func (r *explainRouter) ExecuteExplainRequest(ctx context.Context) (entities.Completion, error) {
+	for r.current != -1 {
+		b := r.backends[r.current]
+
+		c, err := b.Processor.ExecuteExplainRequest(ctx)
+		if err == nil {
+			r.answered(b.Name)
+			c.Backend = b.Name
+
+			return c, nil
+		}
+
+		if !Retriable(ctx, err) {
+			return entities.Completion{}, err
+		}
+
+		r.failover(ctx, b, err)
+
+		if err = r.processNext(ctx, nil); err != nil {
+			return entities.Completion{}, err
+		}
+	}
+
+	return entities.Completion{}, ErrFilesNotProcessed
+}
+
+func (r *explainRouter) Cleanup(ctx context.Context) {
+	if r.current != -1 {
+		r.backends[r.current].Processor.Cleanup(ctx)
+	}
+}
+
+// processNext processes the files by the next pending backend which succeeds. The files are read
+// from the channel if it is set, otherwise from the buffer.
+func (r *explainRouter) processNext(ctx context.Context, files <-chan entities.File) error {
+	for len(r.pending) > 0 {
+		i := r.pending[0]
+		r.pending = r.pending[1:]
+		b := r.backends[i]
+
+		if files == nil {
+			files = r.replay()
+		}
+
+		// the backend is cleaned up on any error, since it may have uploaded some files
+		r.current = i
+
+		err := b.Processor.ProcessFiles(ctx, files, r.opts)
+		if err == nil {
+			return nil
+		}
+
+		if !Retriable(ctx, err) {
+			return err
+		}
+
+		r.failover(ctx, b, err)
+		files = nil
+	}
+
+	if r.lastErr == nil {
+		return ErrFilesNotProcessed
+	}
+
+	return allFailed(r.lastErr)
+}
+
+func (r *explainRouter) failover(ctx context.Context, b agent.ExplainBackend, err error) {
+	r.failed(b.Name, err)
+	b.Processor.Cleanup(ctx)
+
+	r.current = -1
+	r.lastErr = err
+}
+
+func (r *explainRouter) buffer(ctx context.Context, files <-chan entities.File) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to read files: %w", context.Canceled)
+		case f, ok := <-files:
+			if !ok {
+				return nil
+			}
+
+			r.files = append(r.files, f)
+		}
+	}
+}
+
+func (r *explainRouter) replay() <-chan entities.File {
+	files := make(chan entities.File, len(r.files))
+	for _, f := range r.files {
+		files <- f
+	}
+
+	close(files)
+
+	return files
+}
@@ -0,0 +1,204 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	backendhealth_mock "github.com/yaroslav-koval/hange/mocks/backendhealth"
+	explainprocessor_mock "github.com/yaroslav-koval/hange/mocks/explainprocessor"
+)
+
+func TestExplainRouter(t *testing.T) {
+	t.Parallel()
+
+	opts := entity.ExplainOptions{Persistent: true}
+
+	// consume reads the files like a real processor, so a replay to the next backend is checked
+	consume := func(got *[]string) func(context.Context, <-chan entities.File, entity.ExplainOptions) error {
+		return func(_ context.Context, files <-chan entities.File, _ entity.ExplainOptions) error {
+			for f := range files {
+				*got = append(*got, f.Path)
+			}
+
+			return nil
+		}
+	}
+
+	t.Run("fails over while processing files and the request", func(t *testing.T) {
+		t.Parallel()
+
+		var workFiles, localFiles, cloudFiles []string
+
+		work := explainprocessor_mock.NewMockExplainProcessor(t)
+		work.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).RunAndReturn(
+			func(ctx context.Context, files <-chan entities.File, o entity.ExplainOptions) error {
+				_ = consume(&workFiles)(ctx, files, o)
+				return testAPIError(http.StatusServiceUnavailable)
+			})
+		work.EXPECT().Cleanup(mock.Anything).Return()
+
+		local := explainprocessor_mock.NewMockExplainProcessor(t)
+		local.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).RunAndReturn(consume(&localFiles))
+		local.EXPECT().ExecuteExplainRequest(mock.Anything).
+			Return(entities.Completion{}, testAPIError(http.StatusTooManyRequests))
+		local.EXPECT().Cleanup(mock.Anything).Return()
+
+		cloud := explainprocessor_mock.NewMockExplainProcessor(t)
+		cloud.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).RunAndReturn(consume(&cloudFiles))
+		cloud.EXPECT().ExecuteExplainRequest(mock.Anything).Return(entities.Completion{Text: "explained"}, nil)
+		cloud.EXPECT().Cleanup(mock.Anything).Return().Once()
+
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy(mock.Anything).Return(true, nil).Times(3)
+		health.EXPECT().MarkUnhealthy("work", mock.Anything).Return(nil)
+		health.EXPECT().MarkUnhealthy("local", mock.Anything).Return(nil)
+		health.EXPECT().MarkHealthy("cloud").Return(nil)
+
+		r, err := NewExplainRouter([]agent.ExplainBackend{
+			{Name: "work", Processor: work},
+			{Name: "local", Processor: local},
+			{Name: "cloud", Processor: cloud},
+		}, health)
+		require.NoError(t, err)
+
+		files := make(chan entities.File, 2)
+		files <- entities.File{Path: "a.go"}
+		files <- entities.File{Path: "b.go"}
+		close(files)
+
+		require.NoError(t, r.ProcessFiles(context.Background(), files, opts))
+
+		c, err := r.ExecuteExplainRequest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, entities.Completion{Text: "explained", Backend: "cloud"}, c)
+
+		r.Cleanup(context.Background())
+
+		expected := []string{"a.go", "b.go"}
+		assert.Equal(t, expected, workFiles)
+		assert.Equal(t, expected, localFiles)
+		assert.Equal(t, expected, cloudFiles)
+	})
+
+	t.Run("skips a backend without a token", func(t *testing.T) {
+		t.Parallel()
+
+		var cloudFiles []string
+
+		work := explainprocessor_mock.NewMockExplainProcessor(t)
+		work.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).
+			Return(fmt.Errorf("%w: token is not found", ErrUnavailable))
+		work.EXPECT().Cleanup(mock.Anything).Return()
+
+		cloud := explainprocessor_mock.NewMockExplainProcessor(t)
+		cloud.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).RunAndReturn(consume(&cloudFiles))
+		cloud.EXPECT().ExecuteExplainRequest(mock.Anything).Return(entities.Completion{Text: "explained"}, nil)
+
+		// the backend without a token isn't marked unhealthy, it is tried first once the token is set
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy(mock.Anything).Return(true, nil).Times(2)
+		health.EXPECT().MarkHealthy("cloud").Return(nil)
+
+		r, err := NewExplainRouter([]agent.ExplainBackend{
+			{Name: "work", Processor: work},
+			{Name: "cloud", Processor: cloud},
+		}, health)
+		require.NoError(t, err)
+
+		files := make(chan entities.File, 1)
+		files <- entities.File{Path: "a.go"}
+		close(files)
+
+		require.NoError(t, r.ProcessFiles(context.Background(), files, opts))
+
+		c, err := r.ExecuteExplainRequest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, entities.Completion{Text: "explained", Backend: "cloud"}, c)
+		assert.Equal(t, []string{"a.go"}, cloudFiles)
+	})
+
+	t.Run("single backend reads the channel", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+
+		work := explainprocessor_mock.NewMockExplainProcessor(t)
+		work.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).RunAndReturn(consume(&got))
+		work.EXPECT().ExecuteExplainRequest(mock.Anything).Return(entities.Completion{}, testAPIError(http.StatusBadGateway))
+		work.EXPECT().Cleanup(mock.Anything).Return().Once()
+
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy("work").Return(true, nil)
+		health.EXPECT().MarkUnhealthy("work", mock.Anything).Return(nil)
+
+		r, err := NewExplainRouter([]agent.ExplainBackend{{Name: "work", Processor: work}}, health)
+		require.NoError(t, err)
+
+		files := make(chan entities.File, 1)
+		files <- entities.File{Path: "a.go"}
+		close(files)
+
+		require.NoError(t, r.ProcessFiles(context.Background(), files, opts))
+
+		_, err = r.ExecuteExplainRequest(context.Background())
+		require.ErrorIs(t, err, ErrAllBackendsFailed)
+
+		// the failed backend is already cleaned up
+		r.Cleanup(context.Background())
+		assert.Equal(t, []string{"a.go"}, got)
+	})
+
+	t.Run("reused router forgets the previous run", func(t *testing.T) {
+		t.Parallel()
+
+		var workFiles, cloudFiles []string
+
+		work := explainprocessor_mock.NewMockExplainProcessor(t)
+		work.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).RunAndReturn(
+			func(ctx context.Context, files <-chan entities.File, o entity.ExplainOptions) error {
+				_ = consume(&workFiles)(ctx, files, o)
+				return testAPIError(http.StatusServiceUnavailable)
+			}).Times(2)
+		work.EXPECT().Cleanup(mock.Anything).Return().Times(2)
+
+		cloud := explainprocessor_mock.NewMockExplainProcessor(t)
+		cloud.EXPECT().ProcessFiles(mock.Anything, mock.Anything, opts).RunAndReturn(consume(&cloudFiles)).Times(2)
+		cloud.EXPECT().ExecuteExplainRequest(mock.Anything).Return(entities.Completion{Text: "explained"}, nil).Times(2)
+		cloud.EXPECT().Cleanup(mock.Anything).Return().Times(2)
+
+		health := backendhealth_mock.NewMockBackendHealth(t)
+		health.EXPECT().Healthy(mock.Anything).Return(true, nil)
+		health.EXPECT().MarkUnhealthy("work", mock.Anything).Return(nil)
+		health.EXPECT().MarkHealthy("cloud").Return(nil)
+
+		r, err := NewExplainRouter([]agent.ExplainBackend{
+			{Name: "work", Processor: work},
+			{Name: "cloud", Processor: cloud},
+		}, health)
+		require.NoError(t, err)
+
+		for _, path := range []string{"a.go", "b.go"} {
+			files := make(chan entities.File, 1)
+			files <- entities.File{Path: path}
+			close(files)
+
+			require.NoError(t, r.ProcessFiles(context.Background(), files, opts))
+
+			_, err = r.ExecuteExplainRequest(context.Background())
+			require.NoError(t, err)
+
+			r.Cleanup(context.Background())
+		}
+
+		assert.Equal(t, []string{"a.go", "b.go"}, workFiles)
+		assert.Equal(t, []string{"a.go", "b.go"}, cloudFiles)
+	})
+}
@@ -0,0 +1,100 @@
+// Package routing tries processors of several backends in order and fails over on retriable errors.
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+var (
+	ErrNoBackends        = errors.New("no backends to route requests to")
+	ErrAllBackendsFailed = errors.New("all backends failed")
+	// ErrUnavailable is matched by errors of a backend which can't be used at all, e.g. its token can't be read.
+	ErrUnavailable = errors.New("backend is unavailable")
+)
+
+// Retriable reports whether another backend may succeed: an unavailable backend, a timeout, a network failure,
+// a 5xx or 429 response. A cancellation of the run is not retriable.
+func Retriable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, ErrUnavailable) {
+		return true
+	}
+
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
+
+// router keeps the health of backends shared by the commit and explain routers
+type router struct {
+	health agent.BackendHealth
+}
+
+// order returns indexes of the backends to try: healthy ones first, then the ones cooling down as a last resort
+func (r router) order(names []string) []int {
+	var healthy, cooling []int
+
+	for i, name := range names {
+		ok, err := r.health.Healthy(name)
+		if err != nil {
+			slog.Debug("Failed to read health of backend", slog.String(logging.KeyProfile, name), logging.Err(err))
+		}
+
+		if ok {
+			healthy = append(healthy, i)
+			continue
+		}
+
+		slog.Debug("Backend is cooling down after a failure, it is tried last", slog.String(logging.KeyProfile, name))
+
+		cooling = append(cooling, i)
+	}
+
+	return append(healthy, cooling...)
+}
+
+func (r router) failed(name string, err error) {
+	slog.Warn("Backend failed, trying the next one", slog.String(logging.KeyProfile, name), logging.Err(err))
+
+	if errors.Is(err, ErrUnavailable) {
+		// nothing was sent to the backend, so its health is unknown
+		return
+	}
+
+	if markErr := r.health.MarkUnhealthy(name, err); markErr != nil {
+		slog.Warn("Failed to record health of backend", slog.String(logging.KeyProfile, name), logging.Err(markErr))
+	}
+}
+
+func (r router) answered(name string) {
+	slog.Debug("Answered by backend", slog.String(logging.KeyProfile, name))
+
+	if err := r.health.MarkHealthy(name); err != nil {
+		slog.Warn("Failed to record health of backend", slog.String(logging.KeyProfile, name), logging.Err(err))
+	}
+}
+
+func allFailed(err error) error {
+	return fmt.Errorf("%w, the last one: %w", ErrAllBackendsFailed, err)
+}
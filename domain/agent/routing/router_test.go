@@ -0,0 +1,69 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetriable(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected bool
+	}{
+		{name: "5xx", ctx: context.Background(), err: testAPIError(http.StatusBadGateway), expected: true},
+		{name: "429", ctx: context.Background(), err: testAPIError(http.StatusTooManyRequests), expected: true},
+		{name: "4xx", ctx: context.Background(), err: testAPIError(http.StatusUnauthorized)},
+		{
+			name:     "timeout",
+			ctx:      context.Background(),
+			err:      fmt.Errorf("request: %w", context.DeadlineExceeded),
+			expected: true,
+		},
+		{
+			name:     "network",
+			ctx:      context.Background(),
+			err:      &net.OpError{Op: "dial", Err: errors.New("refused")},
+			expected: true,
+		},
+		{
+			name:     "unavailable",
+			ctx:      context.Background(),
+			err:      fmt.Errorf("%w: token is not found", ErrUnavailable),
+			expected: true,
+		},
+		{name: "canceled", ctx: context.Background(), err: context.Canceled},
+		{name: "canceled run", ctx: canceled, err: testAPIError(http.StatusBadGateway)},
+		{name: "other", ctx: context.Background(), err: errors.New("invalid input")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, Retriable(tt.ctx, tt.err))
+		})
+	}
+}
+
+func testAPIError(status int) error {
+	return &openai.Error{
+		StatusCode: status,
+		Request:    httptest.NewRequest(http.MethodPost, "http://example.com/responses", nil),
+		Response:   &http.Response{StatusCode: status},
+	}
+}
@@ -2,17 +2,66 @@ package agent
 
 import (
 	"context"
+	"time"
 
 	"github.com/yaroslav-koval/hange/domain/agent/entity"
 	"github.com/yaroslav-koval/hange/domain/entities"
 )
 
 type ExplainProcessor interface {
-	ProcessFiles(context.Context, <-chan entities.File) error
-	ExecuteExplainRequest(context.Context) (string, error)
+	ProcessFiles(context.Context, <-chan entities.File, entity.ExplainOptions) error
+	ExecuteExplainRequest(context.Context) (entities.Completion, error)
 	Cleanup(context.Context)
 }
 
 type CommitProcessor interface {
-	GenCommitMessage(context.Context, entity.CommitData) (string, error)
+	GenCommitMessage(context.Context, entity.CommitData) (entities.Completion, error)
+}
+
+// Janitor deletes remote resources left by runs that were killed before their cleanup.
+type Janitor interface {
+	// FindOrphans lists resources created by hange at least olderThan ago.
+	FindOrphans(ctx context.Context, olderThan time.Duration) ([]entity.RemoteResource, error)
+	// Delete removes resources concurrently. A failure of one resource doesn't stop the others.
+	Delete(ctx context.Context, resources []entity.RemoteResource) []entity.CleanupResult
+}
+
+// ResourceJournal records remote resources, so the ones of crashed runs can be deleted by the next run.
+type ResourceJournal interface {
+	// Created records a resource. It is called before the resource is used.
+	Created(entity.RemoteResource) error
+	// Deleted records a deletion of the resource.
+	Deleted(entity.RemoteResource) error
+	// Adopted records that the resource is kept by a persistent vector store, so it is not a leftover.
+	Adopted(entity.RemoteResource) error
+	// Leftovers returns resources which are not deleted by runs that are not running anymore.
+	Leftovers() ([]entity.RemoteResource, error)
+}
+
+// SyncStateStore keeps a state of a persistent vector store between runs.
+type SyncStateStore interface {
+	// Load returns a state without a vector store if nothing is saved.
+	Load() (entity.SyncState, error)
+	Save(entity.SyncState) error
+}
+
+// BackendHealth remembers backends which failed with a retriable error, so routers skip them for a cooldown.
+type BackendHealth interface {
+	// Healthy reports whether the backend is not cooling down after a failure.
+	Healthy(backend string) (bool, error)
+	MarkUnhealthy(backend string, cause error) error
+	// MarkHealthy ends the cooldown of the backend.
+	MarkHealthy(backend string) error
+}
+
+// CommitBackend is a commit processor of a profile. Routers try backends in order.
+type CommitBackend struct {
+	Name      string
+	Processor CommitProcessor
+}
+
+// ExplainBackend is an explain processor of a profile. Routers try backends in order.
+type ExplainBackend struct {
+	Name      string
+	Processor ExplainProcessor
 }
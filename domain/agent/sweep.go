@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+// SweepLeftovers deletes resources of crashed runs recorded by the journal. Deleted resources are marked in the
+// journal, the others are tried again by the next run. It returns the number of deleted resources.
+func SweepLeftovers(ctx context.Context, journal ResourceJournal, janitor Janitor) (int, error) {
+	leftovers, err := journal.Leftovers()
+	if err != nil || len(leftovers) == 0 {
+		return 0, err
+	}
+
+	deleted := 0
+
+	for _, r := range janitor.Delete(ctx, leftovers) {
+		if r.Err != nil {
+			slog.Debug("Failed to delete a resource left by a previous run",
+				append(r.Resource.LogAttrs(), logging.Err(r.Err))...)
+			continue
+		}
+
+		if err = journal.Deleted(r.Resource); err != nil {
+			return deleted, err
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	janitor_mock "github.com/yaroslav-koval/hange/mocks/janitor"
+	resourcejournal_mock "github.com/yaroslav-koval/hange/mocks/resourcejournal"
+)
+
+func TestSweepLeftovers(t *testing.T) {
+	vs := entity.RemoteResource{Kind: entity.RemoteVectorStore, ID: "vs_1"}
+	file := entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_1"}
+
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Leftovers().Return([]entity.RemoteResource{vs, file}, nil)
+	// a failed resource stays in the journal for the next run
+	journal.EXPECT().Deleted(vs).Return(nil)
+
+	janitor := janitor_mock.NewMockJanitor(t)
+	janitor.EXPECT().Delete(mock.Anything, []entity.RemoteResource{vs, file}).Return([]entity.CleanupResult{
+		{Resource: vs},
+		{Resource: file, Err: errors.New("rate limited")},
+	})
+
+	deleted, err := SweepLeftovers(context.Background(), journal, janitor)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+}
+
+func TestSweepLeftoversNothingToDo(t *testing.T) {
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Leftovers().Return(nil, nil)
+
+	deleted, err := SweepLeftovers(context.Background(), journal, janitor_mock.NewMockJanitor(t))
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
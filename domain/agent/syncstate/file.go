@@ -0,0 +1,63 @@
+package syncstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/pkg/atomicfile"
+)
+
+// NewFileSyncStateStore creates a store of the state of the repository in a JSON file.
+// Concurrent syncs of one repository are not coordinated, the last saved state wins.
+func NewFileSyncStateStore(path, repo string) agent.SyncStateStore {
+	return &fileSyncStateStore{
+		path: path,
+		repo: repo,
+	}
+}
+
+type fileSyncStateStore struct {
+	path string
+	repo string
+}
+
+func (s *fileSyncStateStore) Load() (entity.SyncState, error) {
+	state := entity.SyncState{}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return state, err
+	}
+
+	if err == nil {
+		if err = json.Unmarshal(data, &state); err != nil {
+			return state, fmt.Errorf("failed to parse %s: %w", s.path, err)
+		}
+	}
+
+	state.Repo = s.repo
+
+	if state.Files == nil {
+		state.Files = map[string]entity.SyncedFile{}
+	}
+
+	return state, nil
+}
+
+func (s *fileSyncStateStore) Save(state entity.SyncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,44 @@
+package syncstate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+)
+
+func TestFileSyncStateStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "stores", "repo.json")
+	s := NewFileSyncStateStore(path, "/src/project")
+
+	state, err := s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, entity.SyncState{Repo: "/src/project", Files: map[string]entity.SyncedFile{}}, state)
+
+	state.VectorStoreID = "vs_1"
+	state.Files["main.go"] = entity.SyncedFile{ID: "file_1", Hash: "abc"}
+	require.NoError(t, s.Save(state))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, state, loaded)
+}
+
+func TestFileSyncStateStore_Broken(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "repo.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+
+	_, err := NewFileSyncStateStore(path, "/src/project").Load()
+	require.ErrorContains(t, err, path)
+}
@@ -1,38 +1,56 @@
 package auth
 
 import (
+	"context"
+
 	"github.com/yaroslav-koval/hange/domain/crypt"
 )
 
 type Auth interface {
 	SaveToken(authToken string) error
 	GetToken() (string, error)
+	// DeleteToken removes the stored token. A token of a credential helper is not affected.
+	DeleteToken() error
+	// VerifyToken checks the token with a lightweight call of the provider.
+	VerifyToken(ctx context.Context) error
+	// TokenSource describes where the token comes from, e.g. a config field or a credential helper.
+	TokenSource() string
 }
 
 func NewAuth(
 	tokenStorer TokenStorer,
 	tokenFetcher TokenFetcher,
+	tokenVerifier TokenVerifier,
 	encryptor crypt.Encryptor,
 	decryptor crypt.Decryptor) Auth {
 	return &service{
-		tokenStorer:  tokenStorer,
-		tokenFetcher: tokenFetcher,
-		encryptor:    encryptor,
-		decryptor:    decryptor,
+		tokenStorer:   tokenStorer,
+		tokenFetcher:  tokenFetcher,
+		tokenVerifier: tokenVerifier,
+		encryptor:     encryptor,
+		decryptor:     decryptor,
 	}
 }
 
 type TokenStorer interface {
 	Store(token string) error
+	Delete() error
 }
 
 type TokenFetcher interface {
 	Fetch() (string, error)
+	// Source describes where the token is fetched from.
+	Source() string
+}
+
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) error
 }
 
 type service struct {
-	tokenStorer  TokenStorer
-	tokenFetcher TokenFetcher
-	encryptor    crypt.Encryptor
-	decryptor    crypt.Decryptor
+	tokenStorer   TokenStorer
+	tokenFetcher  TokenFetcher
+	tokenVerifier TokenVerifier
+	encryptor     crypt.Encryptor
+	decryptor     crypt.Decryptor
 }
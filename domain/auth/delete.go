@@ -0,0 +1,15 @@
+package auth
+
+import "log/slog"
+
+func (s *service) DeleteToken() error {
+	if err := s.tokenStorer.Delete(); err != nil {
+		slog.Info("Failed to delete token")
+
+		return err
+	}
+
+	slog.Info("Authentication token is deleted")
+
+	return nil
+}
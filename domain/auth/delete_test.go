@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	decryptor_mock "github.com/yaroslav-koval/hange/mocks/decryptor"
+	encryptor_mock "github.com/yaroslav-koval/hange/mocks/encryptor"
+	tokenfetcher_mock "github.com/yaroslav-koval/hange/mocks/tokenfetcher"
+	tokenstorer_mock "github.com/yaroslav-koval/hange/mocks/tokenstorer"
+	tokenverifier_mock "github.com/yaroslav-koval/hange/mocks/tokenverifier"
+)
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	deleteErr := errors.New("read-only config")
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "storer error", err: deleteErr, wantErr: deleteErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorer := tokenstorer_mock.NewMockTokenStorer(t)
+			mockStorer.EXPECT().Delete().Return(tt.err)
+
+			auth := NewAuth(
+				mockStorer,
+				tokenfetcher_mock.NewMockTokenFetcher(t),
+				tokenverifier_mock.NewMockTokenVerifier(t),
+				encryptor_mock.NewMockEncryptor(t),
+				decryptor_mock.NewMockDecryptor(t),
+			)
+
+			require.ErrorIs(t, auth.DeleteToken(), tt.wantErr)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	encryptor_mock "github.com/yaroslav-koval/hange/mocks/encryptor"
 	tokenfetcher_mock "github.com/yaroslav-koval/hange/mocks/tokenfetcher"
 	tokenstorer_mock "github.com/yaroslav-koval/hange/mocks/tokenstorer"
+	tokenverifier_mock "github.com/yaroslav-koval/hange/mocks/tokenverifier"
 )
 
 func TestGet(t *testing.T) {
@@ -20,6 +21,7 @@ func TestGet(t *testing.T) {
 	auth := NewAuth(
 		tokenstorer_mock.NewMockTokenStorer(t),
 		mockFetcher,
+		tokenverifier_mock.NewMockTokenVerifier(t),
 		encryptor_mock.NewMockEncryptor(t),
 		mockDecryptor,
 	)
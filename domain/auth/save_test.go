@@ -10,6 +10,7 @@ import (
 	encryptor_mock "github.com/yaroslav-koval/hange/mocks/encryptor"
 	tokenfetcher_mock "github.com/yaroslav-koval/hange/mocks/tokenfetcher"
 	tokenstorer_mock "github.com/yaroslav-koval/hange/mocks/tokenstorer"
+	tokenverifier_mock "github.com/yaroslav-koval/hange/mocks/tokenverifier"
 )
 
 func TestSaveToken(t *testing.T) {
@@ -77,6 +78,7 @@ func TestSaveToken(t *testing.T) {
 			auth := NewAuth(
 				mockStorer,
 				tokenfetcher_mock.NewMockTokenFetcher(t),
+				tokenverifier_mock.NewMockTokenVerifier(t),
 				mockEncryptor,
 				mockDecryptor,
 			)
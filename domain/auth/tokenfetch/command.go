@@ -0,0 +1,136 @@
+package tokenfetch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+const DefaultCommandTimeout = 10 * time.Second
+
+// waitDelay limits the wait for pipes after the command is killed, e.g. if it spawned a daemon holding stdout.
+const waitDelay = time.Second
+
+var ErrCommandFailed = errors.New("token command failed")
+var ErrEmptyCommandOutput = errors.New("token command printed no token")
+
+// CommandConfig describes a credential helper. Command is empty if the helper is not configured.
+type CommandConfig struct {
+	Command string
+	Timeout time.Duration
+}
+
+// ReadCommandConfig reads the credential helper settings of the profile. Timeout falls back to DefaultCommandTimeout.
+func ReadCommandConfig(p profile.Profile) (CommandConfig, error) {
+	cfg := CommandConfig{
+		Command: strings.TrimSpace(p.TokenCommand),
+		Timeout: DefaultCommandTimeout,
+	}
+
+	if p.TokenCommandTimeout != "" {
+		timeout, err := time.ParseDuration(p.TokenCommandTimeout)
+		if err != nil || timeout <= 0 {
+			return CommandConfig{}, fmt.Errorf("%s must be a positive duration, e.g. \"30s\"",
+				consts.ProfilePath(p.Name, consts.ProfileTokenCommandTimeoutField))
+		}
+
+		cfg.Timeout = timeout
+	}
+
+	return cfg, nil
+}
+
+// NewCommandTokenFetcher creates a fetcher that runs a credential helper, e.g. "pass show openai".
+// The first line of stdout is the token. It is cached for the process lifetime, so the helper runs once.
+func NewCommandTokenFetcher(cfg CommandConfig) auth.TokenFetcher {
+	return &commandTokenFetcher{
+		cfg:   cfg,
+		mutex: &sync.Mutex{},
+	}
+}
+
+type commandTokenFetcher struct {
+	cfg   CommandConfig
+	mutex *sync.Mutex
+	token string
+}
+
+func (c *commandTokenFetcher) Fetch() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != "" {
+		return c.token, nil
+	}
+
+	token, err := c.run()
+	if err != nil {
+		return "", err
+	}
+
+	c.token = token
+
+	return c.token, nil
+}
+
+// Source doesn't include the command, it may contain secrets as arguments.
+func (c *commandTokenFetcher) Source() string {
+	return "token command"
+}
+
+func (c *commandTokenFetcher) run() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, c.cfg.Command)
+	cmd.WaitDelay = waitDelay
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// the command itself is not logged, it may contain secrets as arguments
+	slog.Debug("Running token command")
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%w: timed out after %s", ErrCommandFailed, c.cfg.Timeout)
+		}
+
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %w: %s", ErrCommandFailed, err, msg)
+		}
+
+		return "", fmt.Errorf("%w: %w", ErrCommandFailed, err)
+	}
+
+	// helpers like "pass" print metadata after the first line
+	line, _, _ := bufio.NewReader(&stdout).ReadLine()
+
+	token := strings.TrimSpace(string(line))
+	if token == "" {
+		return "", ErrEmptyCommandOutput
+	}
+
+	return token, nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
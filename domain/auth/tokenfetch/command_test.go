@@ -0,0 +1,166 @@
+package tokenfetch
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+func TestCommandTokenFetcher_Fetch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		script    string
+		timeout   time.Duration
+		wantToken string
+		wantErr   error
+		errText   string
+	}{
+		{
+			name:      "reads first line of stdout",
+			script:    "echo '  sk-helper  '\necho 'login: user'\n",
+			wantToken: "sk-helper",
+		},
+		{
+			name:    "non-zero exit code with stderr",
+			script:  "echo 'vault is sealed' >&2\nexit 3\n",
+			wantErr: ErrCommandFailed,
+			errText: "vault is sealed",
+		},
+		{
+			name:    "empty output",
+			script:  "echo\n",
+			wantErr: ErrEmptyCommandOutput,
+		},
+		{
+			name:    "timeout",
+			script:  "sleep 5\necho sk-late\n",
+			timeout: 100 * time.Millisecond,
+			wantErr: ErrCommandFailed,
+			errText: "timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = DefaultCommandTimeout
+			}
+
+			fetcher := NewCommandTokenFetcher(CommandConfig{
+				Command: writeHelper(t, tt.script),
+				Timeout: timeout,
+			})
+
+			token, err := fetcher.Fetch()
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, tt.errText)
+				assert.Empty(t, token)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+		})
+	}
+}
+
+func TestCommandTokenFetcher_FetchCachesToken(t *testing.T) {
+	t.Parallel()
+
+	counter := filepath.Join(t.TempDir(), "calls")
+
+	fetcher := NewCommandTokenFetcher(CommandConfig{
+		Command: writeHelper(t, "echo call >> '"+counter+"'\necho sk-cached\n"),
+		Timeout: DefaultCommandTimeout,
+	})
+
+	for range 3 {
+		token, err := fetcher.Fetch()
+		require.NoError(t, err)
+		assert.Equal(t, "sk-cached", token)
+	}
+
+	calls, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(calls), "call"))
+}
+
+func TestReadCommandConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		profile profile.Profile
+		want    CommandConfig
+		wantErr bool
+	}{
+		{
+			name: "not configured",
+			want: CommandConfig{Timeout: DefaultCommandTimeout},
+		},
+		{
+			name:    "command with default timeout",
+			profile: profile.Profile{TokenCommand: " pass show openai "},
+			want:    CommandConfig{Command: "pass show openai", Timeout: DefaultCommandTimeout},
+		},
+		{
+			name:    "command with timeout",
+			profile: profile.Profile{TokenCommand: "op read op://vault/openai/token", TokenCommandTimeout: "30s"},
+			want:    CommandConfig{Command: "op read op://vault/openai/token", Timeout: 30 * time.Second},
+		},
+		{
+			name:    "invalid timeout",
+			profile: profile.Profile{Name: "work", TokenCommand: "pass show openai", TokenCommandTimeout: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "negative timeout",
+			profile: profile.Profile{TokenCommand: "pass show openai", TokenCommandTimeout: "-1s"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ReadCommandConfig(tt.profile)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// writeHelper creates a fake credential helper script and returns a command running it.
+func writeHelper(t *testing.T, script string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("helper scripts require a POSIX shell")
+	}
+
+	path := filepath.Join(t.TempDir(), "helper.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755))
+
+	return "'" + path + "'"
+}
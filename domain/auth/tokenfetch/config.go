@@ -2,27 +2,33 @@ package tokenfetch
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/yaroslav-koval/hange/domain/auth"
 	"github.com/yaroslav-koval/hange/domain/config"
 	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
 )
 
-func NewConfigTokenFetcher(config config.Configurator) auth.TokenFetcher {
+// NewConfigTokenFetcher creates a fetcher of the profile's stored token.
+// The default profile also reads the token stored by versions before profiles.
+func NewConfigTokenFetcher(config config.Configurator, profileName string) auth.TokenFetcher {
 	return &configTokenFetcher{
-		config: config,
+		config:      config,
+		profileName: profileName,
 	}
 }
 
 type configTokenFetcher struct {
-	config config.Configurator
+	config      config.Configurator
+	profileName string
 }
 
 var ErrTokenNotSet = errors.New("auth token is not set")
 var errInvalidFormat = errors.New("invalid format of token, must be string")
 
 func (c *configTokenFetcher) Fetch() (string, error) {
-	v := c.config.ReadField(consts.AuthTokenPath)
+	v := c.config.ReadField(c.path())
 	if v == nil {
 		return "", ErrTokenNotSet
 	}
@@ -34,3 +40,23 @@ func (c *configTokenFetcher) Fetch() (string, error) {
 
 	return vStr, nil
 }
+
+func (c *configTokenFetcher) Source() string {
+	path := c.path()
+
+	return fmt.Sprintf("config %s (%s)", path, c.config.FieldSource(path))
+}
+
+// path returns the profile's token path. The default profile falls back to the path of versions before profiles.
+func (c *configTokenFetcher) path() string {
+	path := consts.ProfilePath(c.profileName, consts.ProfileTokenField)
+	if c.profileName != profile.DefaultName || c.config.ReadField(path) != nil {
+		return path
+	}
+
+	if c.config.ReadField(consts.AuthTokenPath) != nil {
+		return consts.AuthTokenPath
+	}
+
+	return path
+}
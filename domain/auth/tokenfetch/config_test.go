@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/config"
 	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
 	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
 )
 
@@ -13,23 +15,38 @@ func TestFetch(t *testing.T) {
 
 	cfg := configurator_mock.NewMockConfigurator(t)
 
-	fetcher := NewConfigTokenFetcher(cfg)
+	fetcher := NewConfigTokenFetcher(cfg, "work")
 
-	cfg.EXPECT().ReadField(consts.AuthTokenPath).Return("token-value")
+	cfg.EXPECT().ReadField("profiles.work.token").Return("token-value")
 
 	token, err := fetcher.Fetch()
 	require.NoError(t, err)
 	require.Equal(t, "token-value", token)
 }
 
+func TestFetchDefaultProfileFallsBackToLegacyToken(t *testing.T) {
+	t.Parallel()
+
+	cfg := configurator_mock.NewMockConfigurator(t)
+
+	fetcher := NewConfigTokenFetcher(cfg, profile.DefaultName)
+
+	cfg.EXPECT().ReadField("profiles.default.token").Return(nil)
+	cfg.EXPECT().ReadField(consts.AuthTokenPath).Return("legacy-token")
+
+	token, err := fetcher.Fetch()
+	require.NoError(t, err)
+	require.Equal(t, "legacy-token", token)
+}
+
 func TestNilToken(t *testing.T) {
 	t.Parallel()
 
 	cfg := configurator_mock.NewMockConfigurator(t)
 
-	fetcher := NewConfigTokenFetcher(cfg)
+	fetcher := NewConfigTokenFetcher(cfg, "work")
 
-	cfg.EXPECT().ReadField(consts.AuthTokenPath).Return(nil)
+	cfg.EXPECT().ReadField("profiles.work.token").Return(nil)
 
 	token, err := fetcher.Fetch()
 	require.ErrorIs(t, err, ErrTokenNotSet)
@@ -41,11 +58,35 @@ func TestInvalidFormat(t *testing.T) {
 
 	cfg := configurator_mock.NewMockConfigurator(t)
 
-	fetcher := NewConfigTokenFetcher(cfg)
+	fetcher := NewConfigTokenFetcher(cfg, "work")
 
-	cfg.EXPECT().ReadField(consts.AuthTokenPath).Return(1234)
+	cfg.EXPECT().ReadField("profiles.work.token").Return(1234)
 
 	token, err := fetcher.Fetch()
 	require.ErrorIs(t, err, errInvalidFormat)
 	require.Empty(t, token)
 }
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("profile token", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().FieldSource("profiles.work.token").Return(config.SourceFile)
+
+		require.Equal(t, "config profiles.work.token (file)", NewConfigTokenFetcher(cfg, "work").Source())
+	})
+
+	t.Run("legacy token of default profile", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().ReadField("profiles.default.token").Return(nil)
+		cfg.EXPECT().ReadField(consts.AuthTokenPath).Return("legacy-token")
+		cfg.EXPECT().FieldSource(consts.AuthTokenPath).Return(config.SourceEnv)
+
+		require.Equal(t, "config auth.openai.token (env)", NewConfigTokenFetcher(cfg, profile.DefaultName).Source())
+	})
+}
@@ -0,0 +1,36 @@
+package tokenfetch
+
+import (
+	"github.com/yaroslav-koval/hange/domain/auth"
+)
+
+// NewFuncTokenFetcher creates a fetcher of a token supplied by the caller, e.g. a program embedding hange.
+// An empty token is reported as not set.
+func NewFuncTokenFetcher(fetch func() (string, error), source string) auth.TokenFetcher {
+	return &funcTokenFetcher{
+		fetch:  fetch,
+		source: source,
+	}
+}
+
+type funcTokenFetcher struct {
+	fetch  func() (string, error)
+	source string
+}
+
+func (f *funcTokenFetcher) Fetch() (string, error) {
+	token, err := f.fetch()
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		return "", ErrTokenNotSet
+	}
+
+	return token, nil
+}
+
+func (f *funcTokenFetcher) Source() string {
+	return f.source
+}
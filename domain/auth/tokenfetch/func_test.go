@@ -0,0 +1,23 @@
+package tokenfetch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncTokenFetcher(t *testing.T) {
+	t.Parallel()
+
+	token, err := NewFuncTokenFetcher(func() (string, error) { return "token-value", nil }, "caller").Fetch()
+	require.NoError(t, err)
+	require.Equal(t, "token-value", token)
+
+	_, err = NewFuncTokenFetcher(func() (string, error) { return "", nil }, "caller").Fetch()
+	require.ErrorIs(t, err, ErrTokenNotSet)
+
+	errVault := errors.New("vault is sealed")
+	_, err = NewFuncTokenFetcher(func() (string, error) { return "", errVault }, "caller").Fetch()
+	require.ErrorIs(t, err, errVault)
+}
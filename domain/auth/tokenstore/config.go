@@ -4,22 +4,39 @@ import (
 	"github.com/yaroslav-koval/hange/domain/auth"
 	"github.com/yaroslav-koval/hange/domain/config"
 	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
 )
 
-func NewConfigTokenStorer(config config.Configurator) auth.TokenStorer {
+// NewConfigTokenStorer creates a storer of the profile's token.
+func NewConfigTokenStorer(config config.Configurator, profileName string) auth.TokenStorer {
 	return &configTokenStorer{
-		config: config,
+		config:      config,
+		profileName: profileName,
 	}
 }
 
 type configTokenStorer struct {
-	config config.Configurator
+	config      config.Configurator
+	profileName string
 }
 
 func (c *configTokenStorer) Store(token string) error {
-	if err := c.config.WriteField(consts.AuthTokenPath, token); err != nil {
+	if err := c.config.WriteField(consts.ProfilePath(c.profileName, consts.ProfileTokenField), token); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Delete removes the profile's token. The default profile also removes the token of versions before profiles.
+func (c *configTokenStorer) Delete() error {
+	if err := c.config.DeleteField(consts.ProfilePath(c.profileName, consts.ProfileTokenField)); err != nil {
+		return err
+	}
+
+	if c.profileName == profile.DefaultName {
+		return c.config.DeleteField(consts.AuthTokenPath)
+	}
+
+	return nil
+}
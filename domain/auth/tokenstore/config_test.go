@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
 	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
 )
 
@@ -14,9 +15,9 @@ func TestStore(t *testing.T) {
 
 	cfg := configurator_mock.NewMockConfigurator(t)
 
-	storer := NewConfigTokenStorer(cfg)
+	storer := NewConfigTokenStorer(cfg, "work")
 
-	cfg.EXPECT().WriteField(consts.AuthTokenPath, "token-value").Return(nil)
+	cfg.EXPECT().WriteField("profiles.work.token", "token-value").Return(nil)
 
 	err := storer.Store("token-value")
 	require.NoError(t, err)
@@ -27,11 +28,45 @@ func TestFail(t *testing.T) {
 
 	cfg := configurator_mock.NewMockConfigurator(t)
 
-	storer := NewConfigTokenStorer(cfg)
+	storer := NewConfigTokenStorer(cfg, "work")
 
 	expErr := errors.New("not valid token")
-	cfg.EXPECT().WriteField(consts.AuthTokenPath, "token-value").Return(expErr)
+	cfg.EXPECT().WriteField("profiles.work.token", "token-value").Return(expErr)
 
 	err := storer.Store("token-value")
 	require.ErrorIs(t, err, expErr)
 }
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("named profile", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().DeleteField("profiles.work.token").Return(nil)
+
+		require.NoError(t, NewConfigTokenStorer(cfg, "work").Delete())
+	})
+
+	t.Run("default profile removes legacy token too", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().DeleteField("profiles.default.token").Return(nil)
+		cfg.EXPECT().DeleteField(consts.AuthTokenPath).Return(nil)
+
+		require.NoError(t, NewConfigTokenStorer(cfg, profile.DefaultName).Delete())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		expErr := errors.New("read-only config")
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().DeleteField("profiles.default.token").Return(expErr)
+
+		require.ErrorIs(t, NewConfigTokenStorer(cfg, profile.DefaultName).Delete(), expErr)
+	})
+}
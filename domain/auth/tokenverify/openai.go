@@ -0,0 +1,51 @@
+package tokenverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/yaroslav-koval/hange/domain/auth"
+)
+
+var ErrInvalidToken = errors.New("token is rejected by the provider")
+
+// NewOpenAITokenVerifier creates a verifier listing models, the cheapest authenticated call.
+// Empty baseURL means the OpenAI API.
+func NewOpenAITokenVerifier(baseURL string) auth.TokenVerifier {
+	return &openAITokenVerifier{
+		baseURL: baseURL,
+	}
+}
+
+type openAITokenVerifier struct {
+	baseURL string
+}
+
+func (v *openAITokenVerifier) Verify(ctx context.Context, token string) error {
+	opts := []option.RequestOption{
+		option.WithAPIKey(token),
+	}
+
+	if v.baseURL != "" {
+		opts = append(opts, option.WithBaseURL(v.baseURL))
+	}
+
+	client := openai.NewClient(opts...)
+
+	_, err := client.Models.List(ctx)
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%w: %s", ErrInvalidToken, apiErr.Message)
+	}
+
+	return err
+}
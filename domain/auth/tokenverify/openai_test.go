@@ -0,0 +1,56 @@
+package tokenverify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAITokenVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path != "/models":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Header.Get("Authorization") == "Bearer sk-valid":
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"gpt-5-nano","object":"model"}]}`))
+		case r.Header.Get("Authorization") == "Bearer sk-broken":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"bad request"}}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key provided","code":"invalid_api_key"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	verifier := NewOpenAITokenVerifier(server.URL)
+
+	t.Run("valid token", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, verifier.Verify(context.Background(), "sk-valid"))
+	})
+
+	t.Run("rejected token", func(t *testing.T) {
+		t.Parallel()
+
+		err := verifier.Verify(context.Background(), "sk-revoked")
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.ErrorContains(t, err, "Incorrect API key provided")
+	})
+
+	t.Run("other errors are returned as is", func(t *testing.T) {
+		t.Parallel()
+
+		err := verifier.Verify(context.Background(), "sk-broken")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrInvalidToken)
+	})
+}
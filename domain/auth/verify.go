@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+)
+
+func (s *service) VerifyToken(ctx context.Context) error {
+	token, err := s.GetToken()
+	if err != nil {
+		return err
+	}
+
+	return s.tokenVerifier.Verify(ctx, token)
+}
+
+func (s *service) TokenSource() string {
+	return s.tokenFetcher.Source()
+}
+
+// previewChars is a number of visible chars at each side of a masked token
+const previewChars = 4
+
+// MaskToken returns a preview of the token safe to print, e.g. "sk-p…x9Qz". Short tokens are masked completely.
+func MaskToken(token string) string {
+	runes := []rune(token)
+	if len(runes) <= previewChars*3 {
+		return "****"
+	}
+
+	return string(runes[:previewChars]) + "…" + string(runes[len(runes)-previewChars:])
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	decryptor_mock "github.com/yaroslav-koval/hange/mocks/decryptor"
+	encryptor_mock "github.com/yaroslav-koval/hange/mocks/encryptor"
+	tokenfetcher_mock "github.com/yaroslav-koval/hange/mocks/tokenfetcher"
+	tokenstorer_mock "github.com/yaroslav-koval/hange/mocks/tokenstorer"
+	tokenverifier_mock "github.com/yaroslav-koval/hange/mocks/tokenverifier"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fetchErr := errors.New("token is not set")
+	verifyErr := errors.New("invalid token")
+
+	tests := []struct {
+		name      string
+		fetchErr  error
+		verifyErr error
+		wantErr   error
+	}{
+		{name: "valid token"},
+		{name: "rejected token", verifyErr: verifyErr, wantErr: verifyErr},
+		{name: "missing token is not verified", fetchErr: fetchErr, wantErr: fetchErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockFetcher := tokenfetcher_mock.NewMockTokenFetcher(t)
+			mockDecryptor := decryptor_mock.NewMockDecryptor(t)
+			mockVerifier := tokenverifier_mock.NewMockTokenVerifier(t)
+
+			if tt.fetchErr != nil {
+				mockFetcher.EXPECT().Fetch().Return("", tt.fetchErr)
+			} else {
+				mockFetcher.EXPECT().Fetch().Return("cipher-text", nil)
+				mockDecryptor.EXPECT().Decrypt([]byte("cipher-text")).Return([]byte("secret-value"), nil)
+				mockVerifier.EXPECT().Verify(ctx, "secret-value").Return(tt.verifyErr)
+			}
+
+			auth := NewAuth(
+				tokenstorer_mock.NewMockTokenStorer(t),
+				mockFetcher,
+				mockVerifier,
+				encryptor_mock.NewMockEncryptor(t),
+				mockDecryptor,
+			)
+
+			require.ErrorIs(t, auth.VerifyToken(ctx), tt.wantErr)
+		})
+	}
+}
+
+func TestMaskToken(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "sk-p…x9Qz", MaskToken("sk-proj-abcdefghx9Qz"))
+	assert.Equal(t, "****", MaskToken("sk-short"))
+	assert.Equal(t, "****", MaskToken(""))
+}
@@ -131,7 +131,7 @@ func TestNewCLIConfig(t *testing.T) {
 		tempHomeDir := th.createTempHome(t)
 		defer th.restoreHome(t)
 
-		cfg, err := NewCLIConfig("")
+		cfg, err := NewCLIConfig("", "")
 		require.NoError(t, err)
 		require.NotNil(t, cfg)
 
@@ -146,7 +146,7 @@ func TestNewCLIConfig(t *testing.T) {
 
 		cfgPath := filepath.Join(t.TempDir(), configName)
 
-		_, err := NewCLIConfig(cfgPath)
+		_, err := NewCLIConfig(cfgPath, "")
 		require.Error(t, err)
 		require.True(t, os.IsNotExist(err))
 	})
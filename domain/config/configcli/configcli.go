@@ -2,21 +2,45 @@ package configcli
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/viper"
 	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/migration"
 	"github.com/yaroslav-koval/hange/pkg/consts"
+	"github.com/yaroslav-koval/hange/pkg/logging"
 )
 
-func NewCLIConfig(cfgFile string) (config.Configurator, error) {
+// NewCLIConfig creates a configurator of the user config. A repository config found from workDir up to the git root
+// is merged over it, empty workDir disables the repository config.
+// The config file is migrated first if it is written by an older version.
+func NewCLIConfig(cfgFile, workDir string) (config.Configurator, error) {
+	migrator, err := NewMigrator(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = migrate(migrator); err != nil {
+		return nil, err
+	}
+
 	conf := &viperConfigurator{
 		viper: viper.New(),
 	}
 
-	if err := initCLIConfig(conf.viper, cfgFile); err != nil {
+	if err = initCLIConfig(conf.viper, cfgFile); err != nil {
+		return nil, err
+	}
+
+	if workDir == "" {
+		return conf, nil
+	}
+
+	if err = conf.loadRepoConfig(workDir); err != nil {
 		return nil, err
 	}
 
@@ -25,6 +49,16 @@ func NewCLIConfig(cfgFile string) (config.Configurator, error) {
 
 type viperConfigurator struct {
 	viper *viper.Viper
+	// repo holds allowed fields of the repository config. It is nil if there is no repository config.
+	repo     *viper.Viper
+	repoPath string
+}
+
+// envKeyReplacer maps nested fields to env variables, e.g. auth.openai.token to HANGE_AUTH_OPENAI_TOKEN
+var envKeyReplacer = strings.NewReplacer(".", "_")
+
+func envName(field string) string {
+	return strings.ToUpper(consts.AppName + "_" + envKeyReplacer.Replace(field))
 }
 
 func initCLIConfig(viper *viper.Viper, cfgFile string) error {
@@ -33,9 +67,10 @@ func initCLIConfig(viper *viper.Viper, cfgFile string) error {
 	}
 
 	viper.SetEnvPrefix(consts.AppName)
+	viper.SetEnvKeyReplacer(envKeyReplacer)
 	viper.AutomaticEnv()
 
-	slog.Debug("Using config file: " + viper.ConfigFileUsed())
+	slog.Debug("Using config file", slog.String(logging.KeyFile, viper.ConfigFileUsed()))
 
 	if err := viper.ReadInConfig(); err != nil {
 		return err
@@ -44,20 +79,73 @@ func initCLIConfig(viper *viper.Viper, cfgFile string) error {
 	return nil
 }
 
+// NewMigrator creates a migrator of the config file. The default config file is moved from the location
+// of versions before v0.1.1 too.
+func NewMigrator(cfgFile string) (config.Migrator, error) {
+	if cfgFile != "" {
+		return migration.NewFileMigrator(cfgFile, ""), nil
+	}
+
+	cfgDir, err := defaultConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// the legacy config file has the name of the directory of the current one
+	return migration.NewFileMigrator(filepath.Join(cfgDir, defaultConfigName), cfgDir), nil
+}
+
+const defaultConfigName = "config"
+
+func defaultConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, "."+consts.AppName), nil
+}
+
+func migrate(m config.Migrator) error {
+	res, err := m.Migrate(false)
+	if errors.Is(err, migration.ErrNewerVersion) {
+		slog.Warn("Config is not migrated", logging.Err(err))
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to migrate config: %w", err)
+	}
+
+	// a version is set silently if no value is changed
+	if len(res.Changes) == 0 {
+		return nil
+	}
+
+	slog.Info("Config is migrated", slog.String(logging.KeyFile, res.Path),
+		slog.Int("from_version", res.FromVersion), slog.Int("to_version", res.ToVersion))
+
+	for _, c := range res.Changes {
+		slog.Info("Config migration", slog.String("change", c))
+	}
+
+	if res.Backup != "" {
+		slog.Info("Config backup", slog.String(logging.KeyFile, res.Backup))
+	}
+
+	return nil
+}
+
 func setConfigFileOrDefault(viper *viper.Viper, cfgFile string) error {
 	if cfgFile == "" {
-		home, err := os.UserHomeDir()
+		cfgDir, err := defaultConfigDir()
 		if err != nil {
 			return err
 		}
 
-		cfgDir := filepath.Join(home, "."+consts.AppName)
-
 		if err = os.Mkdir(cfgDir, 0700); err != nil && !errors.Is(err, os.ErrExist) {
 			return err
 		}
 
-		cfgFile = filepath.Join(cfgDir, "config")
+		cfgFile = filepath.Join(cfgDir, defaultConfigName)
 
 		// create a config file if not exists
 		f, err := os.OpenFile(cfgFile, os.O_CREATE, 0700)
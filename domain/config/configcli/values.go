@@ -1,17 +1,138 @@
 package configcli
 
-func (c *viperConfigurator) WriteField(field string, value any) error {
-	c.viper.Set(field, value)
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/yaroslav-koval/hange/domain/config"
+)
 
-	err := c.viper.WriteConfig()
+// WriteField writes the field to the file. Values of env variables are not written, the file is re-read for the update.
+func (c *viperConfigurator) WriteField(field string, value any) error {
+	fv, err := c.fileViper()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	fv.Set(field, value)
+
+	return c.write(fv)
 }
 
+// ReadField reads the field with precedence: env, repository config, user config.
 func (c *viperConfigurator) ReadField(field string) any {
+	if _, ok := os.LookupEnv(envName(field)); !ok && c.repoIsSet(field) {
+		return c.repo.Get(field)
+	}
+
 	// if Viper's AutomaticEnv is enabled, it tries to read value not only from config, but also from environment variables
 	return c.viper.Get(field)
 }
+
+// DeleteField rewrites the config without the field. Viper can't unset a key, so the config is re-created.
+func (c *viperConfigurator) DeleteField(field string) error {
+	fv, err := c.fileViper()
+	if err != nil {
+		return err
+	}
+
+	settings := fv.AllSettings()
+
+	if !deleteNested(settings, strings.Split(strings.ToLower(field), ".")) {
+		return nil
+	}
+
+	v := newFileViper(c.viper.ConfigFileUsed())
+
+	if err = v.MergeConfigMap(settings); err != nil {
+		return err
+	}
+
+	return c.write(v)
+}
+
+func (c *viperConfigurator) FieldSource(field string) config.Source {
+	if _, ok := os.LookupEnv(envName(field)); ok {
+		return config.SourceEnv
+	}
+
+	if c.repoIsSet(field) {
+		return config.SourceRepo
+	}
+
+	if fv, err := c.fileViper(); err == nil && fv.IsSet(field) {
+		return config.SourceFile
+	}
+
+	return config.SourceDefault
+}
+
+func (c *viperConfigurator) FileFields() []string {
+	var fields []string
+
+	if fv, err := c.fileViper(); err == nil {
+		fields = fv.AllKeys()
+	}
+
+	if c.repo != nil {
+		fields = append(fields, c.repo.AllKeys()...)
+	}
+
+	slices.Sort(fields)
+
+	return slices.Compact(fields)
+}
+
+func (c *viperConfigurator) Path() string {
+	return c.viper.ConfigFileUsed()
+}
+
+func (c *viperConfigurator) Reload() error {
+	return c.viper.ReadInConfig()
+}
+
+// fileViper reads the config file only, without env variables.
+func (c *viperConfigurator) fileViper() (*viper.Viper, error) {
+	v := newFileViper(c.viper.ConfigFileUsed())
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// write writes the file and reloads the configurator from it.
+func (c *viperConfigurator) write(fv *viper.Viper) error {
+	if err := fv.WriteConfig(); err != nil {
+		return err
+	}
+
+	return c.Reload()
+}
+
+func newFileViper(path string) *viper.Viper {
+	v := viper.New()
+	v.SetConfigType(string(config.FileTypeYaml))
+	v.SetConfigFile(path)
+
+	return v
+}
+
+func deleteNested(m map[string]any, path []string) bool {
+	if len(path) == 1 {
+		_, ok := m[path[0]]
+		delete(m, path[0])
+
+		return ok
+	}
+
+	child, ok := m[path[0]].(map[string]any)
+	if !ok {
+		return false
+	}
+
+	return deleteNested(child, path[1:])
+}
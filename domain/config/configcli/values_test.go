@@ -2,6 +2,7 @@ package configcli
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -66,3 +67,52 @@ func setUpViperConfig(viper *viper.Viper, cfgPath string) error {
 	viper.SetConfigType(string(config.FileTypeYaml))
 	return viper.ReadInConfig()
 }
+
+func TestDeleteField(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfgPath := tempDir + "/." + consts.AppName
+
+	configContent := "version: v1\nprofiles:\n  work:\n    token: a\n  home:\n    token: b\n"
+
+	require.NoError(t, os.WriteFile(cfgPath, []byte(configContent), 0600))
+
+	conf := &viperConfigurator{viper: viper.New()}
+	require.NoError(t, setUpViperConfig(conf.viper, cfgPath))
+
+	require.NoError(t, conf.DeleteField("profiles.work"))
+	require.NoError(t, conf.DeleteField("profiles.missing"))
+
+	assert.Nil(t, conf.ReadField("profiles.work.token"))
+	assert.Equal(t, "b", conf.ReadField("profiles.home.token"))
+
+	actualFile, err := os.ReadFile(cfgPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(actualFile), "work")
+	assert.Contains(t, string(actualFile), "version: v1")
+}
+
+func TestWriteFieldDoesNotPersistEnv(t *testing.T) {
+	t.Setenv("HANGE_PROFILES_WORK_BASE_URL", "https://env.example.com")
+
+	cfgPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("profiles:\n  work:\n    base_url: https://file.example.com\n"), 0600))
+
+	conf, err := NewCLIConfig(cfgPath, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://env.example.com", conf.ReadField("profiles.work.base_url"))
+	assert.Equal(t, config.SourceEnv, conf.FieldSource("profiles.work.base_url"))
+	assert.Equal(t, config.SourceDefault, conf.FieldSource("profiles.work.provider"))
+
+	require.NoError(t, conf.WriteField("profiles.work.provider", "openai"))
+	assert.Equal(t, config.SourceFile, conf.FieldSource("profiles.work.provider"))
+	// version is written by the migration
+	assert.Equal(t, []string{"profiles.work.base_url", "profiles.work.provider", "version"}, conf.FileFields())
+
+	actualFile, err := os.ReadFile(cfgPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(actualFile), "https://file.example.com")
+	assert.NotContains(t, string(actualFile), "https://env.example.com")
+}
@@ -4,7 +4,31 @@ type FileType string
 
 const FileTypeYaml FileType = "yaml"
 
+// Source is where a field value comes from.
+type Source string
+
+const (
+	SourceFile Source = "file"
+	SourceEnv  Source = "env"
+	// SourceRepo is a repository config, see RepoPath.
+	SourceRepo Source = "repo"
+	// SourceDefault means the field is not set, so a default value applies.
+	SourceDefault Source = "default"
+)
+
 type Configurator interface {
 	WriteField(field string, value any) error
 	ReadField(field string) any
+	// DeleteField removes a field with all nested fields. Deleting a missing field is not an error.
+	DeleteField(field string) error
+	// FieldSource tells where the value of ReadField comes from. Env wins over repository config, it wins over file.
+	FieldSource(field string) Source
+	// FileFields lists leaf fields set in the user and repository config files.
+	FileFields() []string
+	// Path of the user config file. Writes go to it.
+	Path() string
+	// RepoPath of the repository config file. It is empty if there is none.
+	RepoPath() string
+	// Reload re-reads the config file, e.g. after it is edited by a user.
+	Reload() error
 }
@@ -1,5 +1,70 @@
 package consts
 
+import "strings"
+
 const (
+	// VersionPath is a version of the config layout. It is written by migrations.
+	VersionPath = "version"
+
+	// AuthTokenPath is a token path of versions before profiles. It is still read for the default profile.
 	AuthTokenPath = "auth.openai.token"
+	// AuthTokenCommandPath and AuthTokenCommandTimeoutPath are the credential helper of versions before profiles.
+	// They are moved to the default profile by a migration.
+	AuthTokenCommandPath        = "auth.openai.token_command"
+	AuthTokenCommandTimeoutPath = "auth.openai.token_command_timeout"
+
+	// ActiveProfilePath is a name of the profile used if no profile is selected by a flag or env.
+	// It is not "profile", because the config reads HANGE_<KEY> envs and HANGE_PROFILE must not be written to it.
+	ActiveProfilePath = "active_profile"
+	ProfilesPath      = "profiles"
+)
+
+// Fields of a profile, relative to ProfilePath.
+const (
+	ProfileProviderField = "provider"
+	ProfileBaseURLField  = "base_url"
+	ProfileTokenField    = "token"
+	// ProfileTokenCommandField is a shell command printing the token to stdout. It replaces the stored token if set.
+	ProfileTokenCommandField = "token_command"
+	// ProfileTokenCommandTimeoutField is a timeout of the token command, e.g. "30s".
+	ProfileTokenCommandTimeoutField = "token_command_timeout"
+	ProfileCommitModelField         = "models.commit"
+	ProfileExplainModelField        = "models.explain"
+	ProfileCommitPromptField        = "prompts.commit"
+	ProfileExplainPromptField       = "prompts.explain"
+)
+
+// ProfilePath returns a path of the profile section or of its field, e.g. "profiles.work.token".
+func ProfilePath(name string, field ...string) string {
+	return strings.Join(append([]string{ProfilesPath, name}, field...), ".")
+}
+
+// Settings applying to every profile. A repository config can set them.
+const (
+	// IgnorePath is a list of gitignore-style patterns skipped by explain.
+	IgnorePath = "ignore"
+	// CommitConventionalPath makes commit messages follow Conventional Commits.
+	CommitConventionalPath = "commit.conventional"
+	// ExplainPersistentPath keeps a vector store of the repository between explain runs.
+	ExplainPersistentPath = "explain.persistent"
+	// CommitModelPath and others fill the fields the active profile doesn't set.
+	CommitModelPath   = "models.commit"
+	ExplainModelPath  = "models.explain"
+	CommitPromptPath  = "prompts.commit"
+	ExplainPromptPath = "prompts.explain"
 )
+
+// Routing of requests between profiles. They are not read from a repository config,
+// so a repository can't send the code to another provider.
+const (
+	// RoutingCommitPath is an ordered list of profiles tried by commit-msg. The active profile is used if it is empty.
+	RoutingCommitPath = "routing.commit"
+	// RoutingExplainPath is an ordered list of profiles tried by explain.
+	RoutingExplainPath = "routing.explain"
+	// RoutingCooldownPath is a time a profile is tried last for after a retriable failure, e.g. "5m".
+	RoutingCooldownPath = "routing.cooldown"
+)
+
+// PluginsTokenPath is a list of plugins given the token of the profile. It is not read from a repository config,
+// so a repository can't pass the token to a plugin.
+const PluginsTokenPath = "plugins.token"
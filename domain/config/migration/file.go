@@ -0,0 +1,143 @@
+package migration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/pkg/atomicfile"
+)
+
+const filePerm = 0600
+
+// NewFileMigrator creates a migrator of the config file at path. A legacyPath file is moved to path first,
+// e.g. ~/.hange of versions before v0.1.1 to ~/.hange/config. Empty legacyPath disables the move.
+func NewFileMigrator(path, legacyPath string) config.Migrator {
+	return &fileMigrator{
+		path:       path,
+		legacyPath: legacyPath,
+	}
+}
+
+type fileMigrator struct {
+	path       string
+	legacyPath string
+}
+
+func (m *fileMigrator) Migrate(dryRun bool) (config.MigrationResult, error) {
+	res := config.MigrationResult{Path: m.path, ToVersion: CurrentVersion()}
+	src := m.path
+
+	if m.legacyPath != "" && isFile(m.legacyPath) {
+		res.Changes = append(res.Changes, fmt.Sprintf("move %s to %s", m.legacyPath, m.path))
+		// a dry run reads the layout from the old location
+		src = m.legacyPath
+
+		if !dryRun {
+			backup, err := m.moveLegacy()
+			if err != nil {
+				return res, err
+			}
+
+			res.Backup = backup
+			src = m.path
+		}
+	}
+
+	data, err := os.ReadFile(src)
+	if errors.Is(err, os.ErrNotExist) {
+		// nothing to migrate, a new config gets the current version on the next run
+		res.FromVersion = res.ToVersion
+		return res, nil
+	} else if err != nil {
+		return res, err
+	}
+
+	settings, err := parse(data)
+	if err != nil {
+		return res, fmt.Errorf("failed to parse %s: %w", src, err)
+	}
+
+	from, changes, err := apply(settings)
+	res.FromVersion = from
+	res.Changes = append(res.Changes, changes...)
+
+	if err != nil || dryRun || from == res.ToVersion {
+		return res, err
+	}
+
+	// the file is rewritten, so comments and the order of keys are lost even if no value is changed
+	if res.Backup == "" && len(bytes.TrimSpace(data)) > 0 {
+		res.Backup = fmt.Sprintf("%s.v%d.bak", m.path, from)
+
+		if err = atomicfile.WriteFile(res.Backup, data, filePerm); err != nil {
+			return res, fmt.Errorf("failed to back up config: %w", err)
+		}
+	}
+
+	return res, write(m.path, settings)
+}
+
+// moveLegacy renames the legacy file to a backup and copies it to the new location. The new location may be
+// inside a directory of the same name as the legacy file.
+func (m *fileMigrator) moveLegacy() (string, error) {
+	data, err := os.ReadFile(m.legacyPath)
+	if err != nil {
+		return "", err
+	}
+
+	backup := m.legacyPath + ".bak"
+
+	if err = os.Rename(m.legacyPath, backup); err != nil {
+		return "", fmt.Errorf("failed to back up config: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return "", err
+	}
+
+	return backup, atomicfile.WriteFile(m.path, data, filePerm)
+}
+
+func parse(data []byte) (map[string]any, error) {
+	v := newViper()
+
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	return v.AllSettings(), nil
+}
+
+func write(path string, settings map[string]any) error {
+	v := newViper()
+
+	if err := v.MergeConfigMap(settings); err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := v.WriteConfigTo(buf); err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(path, buf.Bytes(), filePerm)
+}
+
+func newViper() *viper.Viper {
+	v := viper.New()
+	v.SetConfigType(string(config.FileTypeYaml))
+
+	return v
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,208 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyConfig = "auth:\n  openai:\n    token: hange:v1:abc\nactive_profile: work\n"
+
+func TestFileMigrator_Layout(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(legacyConfig), 0600))
+
+	m := NewFileMigrator(path, "")
+
+	res, err := m.Migrate(true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.FromVersion)
+	assert.Equal(t, CurrentVersion(), res.ToVersion)
+	assert.Equal(t, []string{"move auth.openai.token to profiles.default.token"}, res.Changes)
+	assert.Empty(t, res.Backup)
+
+	// a dry run doesn't write
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfig, string(data))
+
+	res, err = m.Migrate(false)
+	require.NoError(t, err)
+	assert.Equal(t, path+".v0.bak", res.Backup)
+
+	backup, err := os.ReadFile(res.Backup)
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfig, string(backup))
+
+	settings := readSettings(t, path)
+	assert.Equal(t, map[string]any{
+		"version":        CurrentVersion(),
+		"active_profile": "work",
+		"profiles":       map[string]any{"default": map[string]any{"token": "hange:v1:abc"}},
+	}, settings)
+
+	// the second run has nothing to do
+	res, err = m.Migrate(false)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion(), res.FromVersion)
+	assert.Empty(t, res.Changes)
+}
+
+func TestFileMigrator_KeepsTokenOfDefaultProfile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config")
+	content := legacyConfig + "profiles:\n  default:\n    token: hange:v1:new\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	res, err := NewFileMigrator(path, "").Migrate(false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"keep auth.openai.token, profiles.default.token is already set"}, res.Changes)
+
+	settings := readSettings(t, path)
+	assert.Equal(t, map[string]any{"openai": map[string]any{"token": "hange:v1:abc"}}, settings["auth"])
+}
+
+func TestFileMigrator_TokenCommand(t *testing.T) {
+	t.Parallel()
+
+	const helper = "    token_command: op read op://work/token\n    token_command_timeout: 30s\n"
+
+	tests := []struct {
+		name        string
+		content     string
+		wantChanges []string
+		wantProfile map[string]any
+	}{
+		{
+			name:    "config before versions",
+			content: "auth:\n  openai:\n    token: hange:v1:abc\n" + helper,
+			wantChanges: []string{
+				"move auth.openai.token to profiles.default.token",
+				"move auth.openai.token_command to profiles.default.token_command",
+				"move auth.openai.token_command_timeout to profiles.default.token_command_timeout",
+			},
+			wantProfile: map[string]any{
+				"token":                 "hange:v1:abc",
+				"token_command":         "op read op://work/token",
+				"token_command_timeout": "30s",
+			},
+		},
+		{
+			// version 1 moved the token only, the token command stayed where it was
+			name:    "config of version 1",
+			content: "version: 1\nauth:\n  openai:\n" + helper,
+			wantChanges: []string{
+				"move auth.openai.token_command to profiles.default.token_command",
+				"move auth.openai.token_command_timeout to profiles.default.token_command_timeout",
+			},
+			wantProfile: map[string]any{
+				"token_command":         "op read op://work/token",
+				"token_command_timeout": "30s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			res, err := NewFileMigrator(path, "").Migrate(false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanges, res.Changes)
+
+			settings := readSettings(t, path)
+			assert.NotContains(t, settings, "auth")
+			assert.Equal(t, map[string]any{"default": tt.wantProfile}, settings["profiles"])
+		})
+	}
+}
+
+func TestFileMigrator_LegacyLocation(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), ".hange")
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(dir, []byte(legacyConfig), 0600))
+
+	m := NewFileMigrator(path, dir)
+
+	res, err := m.Migrate(true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"move " + dir + " to " + path,
+		"move auth.openai.token to profiles.default.token",
+	}, res.Changes)
+	assert.FileExists(t, dir)
+
+	res, err = m.Migrate(false)
+	require.NoError(t, err)
+	assert.Equal(t, dir+".bak", res.Backup)
+	assert.DirExists(t, dir)
+	assert.FileExists(t, dir+".bak")
+
+	settings := readSettings(t, path)
+	assert.Equal(t, CurrentVersion(), settings["version"])
+	assert.NotContains(t, settings, "auth")
+}
+
+func TestFileMigrator_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{name: "newer version", content: "version: 100\n", wantErr: ErrNewerVersion},
+		{name: "invalid version", content: "version: latest\n", wantErr: ErrInvalidVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			_, err := NewFileMigrator(path, "").Migrate(false)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			// the file is not touched
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(data))
+		})
+	}
+}
+
+func TestFileMigrator_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config")
+
+	res, err := NewFileMigrator(path, "").Migrate(false)
+	require.NoError(t, err)
+	assert.Equal(t, res.ToVersion, res.FromVersion)
+	assert.NoFileExists(t, path)
+}
+
+func readSettings(t *testing.T, path string) map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	settings, err := parse(data)
+	require.NoError(t, err)
+
+	return settings
+}
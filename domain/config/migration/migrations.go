@@ -0,0 +1,179 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+var (
+	ErrNewerVersion   = errors.New("config is written by a newer version of hange")
+	ErrInvalidVersion = errors.New("invalid config version")
+)
+
+// Migration upgrades settings of the previous version to Version.
+type Migration struct {
+	Version     int
+	Description string
+	// Apply changes the settings in place and describes each change. Settings may have nothing to change.
+	Apply func(settings map[string]any) []string
+}
+
+// migrations are applied in order of versions. Breaking config changes are shipped as a new migration.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "move the token of versions before profiles to the default profile",
+		Apply:       moveLegacyToken,
+	},
+	{
+		Version:     2,
+		Description: "move the token command of versions before profiles to the default profile",
+		Apply:       moveLegacyTokenCommand,
+	},
+}
+
+// CurrentVersion is a config version written by this build.
+func CurrentVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// apply runs migrations newer than the version of the settings and sets the current version.
+func apply(settings map[string]any) (int, []string, error) {
+	from, err := readVersion(settings)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if from > CurrentVersion() {
+		return from, nil, fmt.Errorf("%w: version %d, supported %d", ErrNewerVersion, from, CurrentVersion())
+	}
+
+	var changes []string
+
+	for _, m := range migrations {
+		if m.Version <= from {
+			continue
+		}
+
+		changes = append(changes, m.Apply(settings)...)
+	}
+
+	settings[consts.VersionPath] = CurrentVersion()
+
+	return from, changes, nil
+}
+
+// readVersion returns 0 for configs written before versioning.
+func readVersion(settings map[string]any) (int, error) {
+	switch v := settings[consts.VersionPath].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		if v < 0 {
+			return 0, fmt.Errorf("%w: %d", ErrInvalidVersion, v)
+		}
+
+		return v, nil
+	default:
+		return 0, fmt.Errorf("%w: %v is not a number", ErrInvalidVersion, v)
+	}
+}
+
+func moveLegacyToken(settings map[string]any) []string {
+	return moveToDefaultProfile(settings, consts.AuthTokenPath, consts.ProfileTokenField)
+}
+
+// moveLegacyTokenCommand is a separate migration, since configs of version 1 kept the token command where it was
+func moveLegacyTokenCommand(settings map[string]any) []string {
+	changes := moveToDefaultProfile(settings, consts.AuthTokenCommandPath, consts.ProfileTokenCommandField)
+
+	return append(changes,
+		moveToDefaultProfile(settings, consts.AuthTokenCommandTimeoutPath, consts.ProfileTokenCommandTimeoutField)...)
+}
+
+// moveToDefaultProfile moves a field of versions before profiles to the field of the default profile,
+// unless the profile sets it already.
+func moveToDefaultProfile(settings map[string]any, from, field string) []string {
+	value, ok := getNested(settings, from)
+	if !ok {
+		return nil
+	}
+
+	target := consts.ProfilePath(profile.DefaultName, field)
+
+	if _, ok = getNested(settings, target); ok {
+		return []string{fmt.Sprintf("keep %s, %s is already set", from, target)}
+	}
+
+	if !setNested(settings, target, value) {
+		return []string{fmt.Sprintf("keep %s, %s is not a map", from, consts.ProfilesPath)}
+	}
+
+	deleteNested(settings, from)
+
+	return []string{fmt.Sprintf("move %s to %s", from, target)}
+}
+
+func getNested(m map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := m[p].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		m = child
+	}
+
+	v, ok := m[parts[len(parts)-1]]
+
+	return v, ok
+}
+
+// setNested creates missing parents. It returns false if a parent is not a map.
+func setNested(m map[string]any, path string, value any) bool {
+	parts := strings.Split(path, ".")
+
+	for _, p := range parts[:len(parts)-1] {
+		if _, ok := m[p]; !ok {
+			m[p] = map[string]any{}
+		}
+
+		child, ok := m[p].(map[string]any)
+		if !ok {
+			return false
+		}
+
+		m = child
+	}
+
+	m[parts[len(parts)-1]] = value
+
+	return true
+}
+
+// deleteNested removes parents left empty too.
+func deleteNested(m map[string]any, path string) {
+	parts := strings.SplitN(path, ".", 2)
+
+	if len(parts) == 1 {
+		delete(m, path)
+		return
+	}
+
+	child, ok := m[parts[0]].(map[string]any)
+	if !ok {
+		return
+	}
+
+	deleteNested(child, parts[1])
+
+	if len(child) == 0 {
+		delete(m, parts[0])
+	}
+}
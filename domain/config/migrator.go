@@ -0,0 +1,17 @@
+package config
+
+// Migrator upgrades a config file written by an older version, both its location and layout.
+type Migrator interface {
+	// Migrate applies pending migrations. The file is backed up before it is changed. dryRun only reports the changes.
+	Migrate(dryRun bool) (MigrationResult, error)
+}
+
+// MigrationResult describes applied changes, or pending ones on a dry run.
+type MigrationResult struct {
+	Path        string
+	FromVersion int
+	ToVersion   int
+	Changes     []string
+	// Backup is a copy of the file before the migration. It is empty if nothing is backed up.
+	Backup string
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+)
+
+// Entry is a config value with its source. Value is not masked.
+type Entry struct {
+	Path   string
+	Value  any
+	Source config.Source
+	// Key is empty if the path is not known by the schema.
+	Key Key
+}
+
+func (e Entry) Known() bool {
+	return e.Key.Pattern != ""
+}
+
+// Entries lists values set in the file or env, and known keys with defaults. Fields of profiles from the file are
+// listed for each profile. Unknown fields of the file are listed too, so they can be found and removed.
+func Entries(c config.Configurator) []Entry {
+	profiles := profileNames(c)
+	entries := map[string]Entry{}
+
+	for _, k := range keys {
+		paths := []string{k.Pattern}
+		if k.IsProfileKey() {
+			paths = make([]string, 0, len(profiles))
+			for _, name := range profiles {
+				paths = append(paths, k.Expand(name))
+			}
+		}
+
+		for _, path := range paths {
+			source := c.FieldSource(path)
+			if source == config.SourceDefault && k.Default == "" {
+				continue
+			}
+
+			var value any = k.Default
+			if source != config.SourceDefault {
+				value = c.ReadField(path)
+			}
+
+			entries[path] = Entry{Path: path, Value: value, Source: source, Key: k}
+		}
+	}
+
+	for _, path := range c.FileFields() {
+		if _, ok := entries[path]; ok {
+			continue
+		}
+
+		k, _ := Lookup(path)
+		entries[path] = Entry{Path: path, Value: c.ReadField(path), Source: c.FieldSource(path), Key: k}
+	}
+
+	result := make([]Entry, 0, len(entries))
+	for _, path := range slices.Sorted(maps.Keys(entries)) {
+		result = append(result, entries[path])
+	}
+
+	return result
+}
+
+// Validate checks values set in the file or env. It returns an error per unknown key or invalid value.
+func Validate(c config.Configurator) []error {
+	var errs []error
+
+	if v := c.ReadField(consts.ProfilesPath); v != nil {
+		if _, ok := v.(map[string]any); !ok {
+			errs = append(errs, fmt.Errorf("%w: %s must be a map of profiles", ErrInvalidValue, consts.ProfilesPath))
+		}
+	}
+
+	for _, e := range Entries(c) {
+		if e.Source == config.SourceDefault {
+			continue
+		}
+
+		if !e.Known() {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownKey, e.Path))
+			continue
+		}
+
+		if err := e.Key.Validate(e.Value); err != nil {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", e.Path, e.Source, err))
+		}
+	}
+
+	return errs
+}
+
+func profileNames(c config.Configurator) []string {
+	profiles, _ := c.ReadField(consts.ProfilesPath).(map[string]any)
+
+	return slices.Sorted(maps.Keys(profiles))
+}
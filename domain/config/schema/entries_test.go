@@ -0,0 +1,93 @@
+package schema_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/configcli"
+	"github.com/yaroslav-koval/hange/domain/config/schema"
+)
+
+const testConfig = `profiles:
+  work:
+    base_url: https://llm.example.com/v1
+    token: hange:v1:abc
+    token_command_timeout: soon
+unknown: 1
+`
+
+func TestEntries(t *testing.T) {
+	t.Setenv("HANGE_PROFILES_WORK_MODELS_COMMIT", "gpt-5-mini")
+
+	c := newConfig(t, testConfig)
+
+	got := map[string]schema.Entry{}
+	for _, e := range schema.Entries(c) {
+		got[e.Path] = e
+	}
+
+	assert.Equal(t, []string{
+		"active_profile",
+		"commit.conventional",
+		"explain.persistent",
+		"profiles.work.base_url",
+		"profiles.work.models.commit",
+		"profiles.work.provider",
+		"profiles.work.token",
+		"profiles.work.token_command_timeout",
+		"routing.cooldown",
+		"unknown",
+		"version",
+	}, sortedPaths(schema.Entries(c)))
+
+	assert.Equal(t, config.SourceDefault, got["active_profile"].Source)
+	assert.Equal(t, "default", got["active_profile"].Value)
+	assert.Equal(t, config.SourceEnv, got["profiles.work.models.commit"].Source)
+	assert.Equal(t, "gpt-5-mini", got["profiles.work.models.commit"].Value)
+	assert.Equal(t, config.SourceFile, got["profiles.work.token"].Source)
+	assert.True(t, got["profiles.work.token"].Key.Secret)
+	assert.False(t, got["unknown"].Known())
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv("HANGE_PROFILES_WORK_PROVIDER", "ollama")
+
+	errs := schema.Validate(newConfig(t, testConfig))
+	require.Len(t, errs, 3)
+
+	assert.ErrorContains(t, errs[0], "profiles.work.provider (env)")
+	assert.ErrorIs(t, errs[0], schema.ErrInvalidValue)
+	assert.ErrorContains(t, errs[1], "profiles.work.token_command_timeout (file)")
+	assert.ErrorIs(t, errs[2], schema.ErrUnknownKey)
+}
+
+func TestValidateValidConfig(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, schema.Validate(newConfig(t, "active_profile: work\nprofiles:\n  work:\n    token: hange:v1:abc\n")))
+}
+
+func sortedPaths(entries []schema.Entry) []string {
+	paths := make([]string, 0, len(entries))
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+
+	return paths
+}
+
+func newConfig(t *testing.T, content string) config.Configurator {
+	t.Helper()
+
+	cfgPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0600))
+
+	c, err := configcli.NewCLIConfig(cfgPath, "")
+	require.NoError(t, err)
+
+	return c
+}
@@ -0,0 +1,355 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+// Kind is a type of a config value. All kinds are stored as strings.
+type Kind string
+
+const (
+	KindString   Kind = "string"
+	KindDuration Kind = "duration"
+	KindURL      Kind = "url"
+	KindEnum     Kind = "enum"
+	KindName     Kind = "name"
+	KindBool     Kind = "bool"
+	KindInt      Kind = "int"
+	// KindList is a list of strings. A raw value is comma-separated.
+	KindList Kind = "list"
+)
+
+// wildcard matches any profile name in a key pattern
+const wildcard = "*"
+
+const maskedValue = "********"
+
+var (
+	ErrUnknownKey   = errors.New("unknown config key")
+	ErrInvalidValue = errors.New("invalid config value")
+	ErrManagedKey   = errors.New("config key is managed by another command")
+)
+
+// Key describes a known config key.
+type Key struct {
+	// Pattern is a key path, "*" matches a profile name, e.g. "profiles.*.token".
+	Pattern     string
+	Kind        Kind
+	Description string
+	Default     string
+	// Values lists allowed values of KindEnum.
+	Values []string
+	// Secret values are masked on output.
+	Secret bool
+	// ManagedBy is a command writing the key, e.g. an encrypted token can't be set as plain text.
+	ManagedBy string
+	// Repo keys can be set by a repository config. Other keys of it are ignored, so a repository can't
+	// read secrets, run commands or send the token to another URL.
+	Repo bool
+}
+
+var keys = []Key{
+	{
+		Pattern:     consts.VersionPath,
+		Kind:        KindInt,
+		Description: "version of the config layout",
+		ManagedBy:   "hange config migrate",
+	},
+	{
+		Pattern:     consts.ActiveProfilePath,
+		Kind:        KindName,
+		Description: "profile used if --profile and HANGE_PROFILE are not set",
+		Default:     profile.DefaultName,
+	},
+	{
+		Pattern:     consts.AuthTokenPath,
+		Kind:        KindString,
+		Description: "encrypted token of versions before profiles, read by the default profile",
+		Secret:      true,
+		ManagedBy:   "hange auth",
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileProviderField),
+		Kind:        KindEnum,
+		Description: "LLM provider",
+		Default:     profile.ProviderOpenAI,
+		Values:      profile.Providers,
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileBaseURLField),
+		Kind:        KindURL,
+		Description: "base URL of an OpenAI compatible API",
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileTokenField),
+		Kind:        KindString,
+		Description: "encrypted token",
+		Secret:      true,
+		ManagedBy:   "hange auth",
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileTokenCommandField),
+		Kind:        KindString,
+		Description: "command printing the token",
+		// arguments of the command may contain secrets
+		Secret: true,
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileTokenCommandTimeoutField),
+		Kind:        KindDuration,
+		Description: "timeout of the token command",
+		Default:     "10s",
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileCommitModelField),
+		Kind:        KindString,
+		Description: "model of the commit-msg command",
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileExplainModelField),
+		Kind:        KindString,
+		Description: "model of the explain command",
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileCommitPromptField),
+		Kind:        KindString,
+		Description: "system prompt of the commit-msg command",
+	},
+	{
+		Pattern:     consts.ProfilePath(wildcard, consts.ProfileExplainPromptField),
+		Kind:        KindString,
+		Description: "system prompt of the explain command",
+	},
+	{
+		Pattern:     consts.IgnorePath,
+		Kind:        KindList,
+		Description: "gitignore-style patterns skipped by explain",
+		Repo:        true,
+	},
+	{
+		Pattern:     consts.CommitConventionalPath,
+		Kind:        KindBool,
+		Description: "write commit messages in the Conventional Commits format",
+		Default:     "false",
+		Repo:        true,
+	},
+	{
+		Pattern:     consts.ExplainPersistentPath,
+		Kind:        KindBool,
+		Description: "keep a vector store of the repository between explain runs and upload only changed files",
+		Default:     "false",
+		Repo:        true,
+	},
+	{
+		Pattern:     consts.CommitModelPath,
+		Kind:        KindString,
+		Description: "model of the commit-msg command, overrides the profile's one",
+		Repo:        true,
+	},
+	{
+		Pattern:     consts.ExplainModelPath,
+		Kind:        KindString,
+		Description: "model of the explain command, overrides the profile's one",
+		Repo:        true,
+	},
+	{
+		Pattern:     consts.CommitPromptPath,
+		Kind:        KindString,
+		Description: "system prompt of the commit-msg command, overrides the profile's one",
+		Repo:        true,
+	},
+	{
+		Pattern:     consts.ExplainPromptPath,
+		Kind:        KindString,
+		Description: "system prompt of the explain command, overrides the profile's one",
+		Repo:        true,
+	},
+	{
+		Pattern:     consts.RoutingCommitPath,
+		Kind:        KindList,
+		Description: "profiles tried in order by commit-msg if a provider fails, the active profile if empty",
+	},
+	{
+		Pattern:     consts.RoutingExplainPath,
+		Kind:        KindList,
+		Description: "profiles tried in order by explain if a provider fails, the active profile if empty",
+	},
+	{
+		Pattern:     consts.RoutingCooldownPath,
+		Kind:        KindDuration,
+		Description: "time a failed profile is tried last for",
+		Default:     "5m",
+	},
+	{
+		Pattern:     consts.PluginsTokenPath,
+		Kind:        KindList,
+		Description: "plugins given the token of the profile, others run without it",
+	},
+}
+
+// Keys returns all known keys.
+func Keys() []Key {
+	return slices.Clone(keys)
+}
+
+// Lookup finds a known key by its path, e.g. "profiles.work.token". Paths are case-insensitive.
+func Lookup(path string) (Key, error) {
+	path = strings.ToLower(path)
+
+	for _, k := range keys {
+		if matches(k.Pattern, path) {
+			return k, nil
+		}
+	}
+
+	return Key{}, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+}
+
+// Expand returns a path of the key for the profile, e.g. "profiles.work.token". Keys without "*" are returned as is.
+func (k Key) Expand(profileName string) string {
+	return strings.Replace(k.Pattern, wildcard, profileName, 1)
+}
+
+// IsProfileKey reports whether the key is a field of a profile.
+func (k Key) IsProfileKey() bool {
+	return strings.Contains(k.Pattern, wildcard)
+}
+
+// Coerce validates a raw value and converts it to the stored form.
+func (k Key) Coerce(raw string) (any, error) {
+	if k.ManagedBy != "" {
+		return nil, fmt.Errorf("%w, use %s", ErrManagedKey, k.ManagedBy)
+	}
+
+	v := strings.TrimSpace(raw)
+
+	switch k.Kind {
+	case KindDuration:
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: must be a positive duration, e.g. 30s", ErrInvalidValue)
+		}
+
+		return d.String(), nil
+	case KindURL:
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: must be an http(s) URL", ErrInvalidValue)
+		}
+
+		return strings.TrimSuffix(u.String(), "/"), nil
+	case KindEnum:
+		if !slices.Contains(k.Values, v) {
+			return nil, fmt.Errorf("%w: must be one of %v", ErrInvalidValue, k.Values)
+		}
+	case KindName:
+		if err := profile.ValidateName(v); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+		}
+	case KindInt:
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: must be an integer", ErrInvalidValue)
+		}
+
+		return i, nil
+	case KindBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: must be true or false", ErrInvalidValue)
+		}
+
+		return b, nil
+	case KindList:
+		var items []string
+
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		return items, nil
+	case KindString:
+	}
+
+	return v, nil
+}
+
+// Validate checks a value read from the config. Values of managed keys are not coerced, only their type is checked.
+// Env values are strings, so they are coerced.
+func (k Key) Validate(value any) error {
+	switch v := value.(type) {
+	case bool:
+		if k.Kind == KindBool {
+			return nil
+		}
+	case int:
+		if k.Kind == KindInt {
+			return nil
+		}
+	case []any:
+		if k.Kind == KindList && isStringList(v) {
+			return nil
+		}
+	case string:
+		if k.ManagedBy != "" {
+			return nil
+		}
+
+		_, err := k.Coerce(v)
+
+		return err
+	}
+
+	return fmt.Errorf("%w: must be a %s", ErrInvalidValue, k.Kind)
+}
+
+// Format returns a value for output. Secrets are masked.
+func (k Key) Format(value any) string {
+	if value == nil {
+		return ""
+	}
+
+	if k.Secret {
+		return maskedValue
+	}
+
+	return fmt.Sprint(value)
+}
+
+func isStringList(items []any) bool {
+	for _, item := range items {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matches(pattern, path string) bool {
+	patternParts := strings.Split(pattern, ".")
+	pathParts := strings.Split(path, ".")
+
+	if len(patternParts) != len(pathParts) {
+		return false
+	}
+
+	for i, p := range patternParts {
+		if p != wildcard && p != pathParts[i] {
+			return false
+		}
+	}
+
+	return true
+}
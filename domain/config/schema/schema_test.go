@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	k, err := Lookup("Profiles.Work.Token_Command_Timeout")
+	require.NoError(t, err)
+	assert.Equal(t, "profiles.*.token_command_timeout", k.Pattern)
+	assert.Equal(t, "profiles.work.token_command_timeout", k.Expand("work"))
+
+	_, err = Lookup("profiles.work.models")
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = Lookup("auth.openai")
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyCoerce(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		raw     string
+		want    string
+		wantErr error
+	}{
+		{path: "profiles.work.token_command_timeout", raw: " 90s ", want: "1m30s"},
+		{path: "profiles.work.token_command_timeout", raw: "0s", wantErr: ErrInvalidValue},
+		{path: "profiles.work.base_url", raw: "http://localhost:11434/v1/", want: "http://localhost:11434/v1"},
+		{path: "profiles.work.base_url", raw: "localhost:11434", wantErr: ErrInvalidValue},
+		{path: "profiles.work.provider", raw: "openai", want: "openai"},
+		{path: "profiles.work.provider", raw: "ollama", wantErr: ErrInvalidValue},
+		{path: "active_profile", raw: "work", want: "work"},
+		{path: "active_profile", raw: "my.work", wantErr: ErrInvalidValue},
+		{path: "profiles.work.models.commit", raw: "gpt-5-mini", want: "gpt-5-mini"},
+		{path: "profiles.work.token", raw: "sk-plain", wantErr: ErrManagedKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+"="+tt.raw, func(t *testing.T) {
+			t.Parallel()
+
+			k, err := Lookup(tt.path)
+			require.NoError(t, err)
+
+			got, err := k.Coerce(tt.raw)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKeyFormatMasksSecrets(t *testing.T) {
+	t.Parallel()
+
+	token, err := Lookup("profiles.work.token")
+	require.NoError(t, err)
+	assert.Equal(t, maskedValue, token.Format("hange:v1:abc"))
+	assert.Empty(t, token.Format(nil))
+
+	model, err := Lookup("profiles.work.models.commit")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-5-mini", model.Format("gpt-5-mini"))
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReadBool reads a bool field. Env variables are strings, so "true" and "false" are accepted too.
+func ReadBool(c Configurator, field string) (bool, error) {
+	switch v := c.ReadField(field).(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("%s must be true or false", field)
+		}
+
+		return b, nil
+	default:
+		return false, fmt.Errorf("%s must be true or false", field)
+	}
+}
+
+// ReadStringSlice reads a list of strings. Env variables are strings, so a comma-separated list is accepted too.
+func ReadStringSlice(c Configurator, field string) ([]string, error) {
+	switch v := c.ReadField(field).(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return splitList(v), nil
+	case []string:
+		return v, nil
+	case []any:
+		res := make([]string, 0, len(v))
+
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of strings", field)
+			}
+
+			res = append(res, s)
+		}
+
+		return res, nil
+	default:
+		return nil, fmt.Errorf("%s must be a list of strings", field)
+	}
+}
+
+func splitList(s string) []string {
+	var res []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
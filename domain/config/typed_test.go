@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/config"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+)
+
+func TestReadBool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   any
+		want    bool
+		wantErr bool
+	}{
+		{name: "not set", value: nil, want: false},
+		{name: "bool", value: true, want: true},
+		{name: "env string", value: " true ", want: true},
+		{name: "invalid string", value: "yes please", wantErr: true},
+		{name: "invalid type", value: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := configurator_mock.NewMockConfigurator(t)
+			c.EXPECT().ReadField("commit.conventional").Return(tt.value)
+
+			got, err := config.ReadBool(c, "commit.conventional")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadStringSlice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   any
+		want    []string
+		wantErr bool
+	}{
+		{name: "not set", value: nil, want: nil},
+		{name: "yaml list", value: []any{"dist/", "*.pb.go"}, want: []string{"dist/", "*.pb.go"}},
+		{name: "env string", value: "dist/, *.pb.go,", want: []string{"dist/", "*.pb.go"}},
+		{name: "not strings", value: []any{"dist/", 1}, wantErr: true},
+		{name: "invalid type", value: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := configurator_mock.NewMockConfigurator(t)
+			c.EXPECT().ReadField("ignore").Return(tt.value)
+
+			got, err := config.ReadStringSlice(c, "ignore")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
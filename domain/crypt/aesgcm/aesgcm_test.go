@@ -0,0 +1,111 @@
+package aesgcm_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/crypt/aesgcm"
+	base64crypt "github.com/yaroslav-koval/hange/domain/crypt/base64"
+	passphraseprovider_mock "github.com/yaroslav-koval/hange/mocks/passphraseprovider"
+)
+
+func TestAESGCMRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	passphrase := newPassphrase(t, "correct horse")
+
+	encryptor := aesgcm.NewAESGCMEncryptor(passphrase)
+	decryptor := aesgcm.NewAESGCMDecryptor(passphrase, base64crypt.NewBase64Decryptor())
+
+	first, err := encryptor.Encrypt([]byte("sk-secret"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(first), "hange:v1:"))
+	require.NotContains(t, string(first), "sk-secret")
+
+	second, err := encryptor.Encrypt([]byte("sk-secret"))
+	require.NoError(t, err)
+	require.NotEqual(t, first, second, "random salt and nonce must produce different envelopes")
+
+	for _, v := range [][]byte{first, second} {
+		decrypted, err := decryptor.Decrypt(v)
+		require.NoError(t, err)
+		require.Equal(t, "sk-secret", string(decrypted))
+	}
+}
+
+func TestAESGCMDecryptorFailures(t *testing.T) {
+	t.Parallel()
+
+	encrypted, err := aesgcm.NewAESGCMEncryptor(newPassphrase(t, "right")).Encrypt([]byte("sk-secret"))
+	require.NoError(t, err)
+
+	tampered := []byte(string(encrypted[:len(encrypted)-4]) + "AAA=")
+
+	tests := []struct {
+		name       string
+		passphrase string
+		value      []byte
+		wantErr    error
+	}{
+		{name: "wrong passphrase", passphrase: "wrong", value: encrypted, wantErr: aesgcm.ErrDecryptionFailed},
+		{name: "tampered ciphertext", passphrase: "right", value: tampered, wantErr: aesgcm.ErrDecryptionFailed},
+		{name: "unsupported version", value: []byte("hange:v9:AAAA"), wantErr: aesgcm.ErrUnsupportedVersion},
+		{name: "missing version separator", value: []byte("hange:v1"), wantErr: aesgcm.ErrInvalidEnvelope},
+		{name: "invalid base64", value: []byte("hange:v1:!!!"), wantErr: aesgcm.ErrInvalidEnvelope},
+		{
+			name:    "too short payload",
+			value:   []byte("hange:v1:" + base64.StdEncoding.EncodeToString([]byte("short"))),
+			wantErr: aesgcm.ErrInvalidEnvelope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			decryptor := aesgcm.NewAESGCMDecryptor(newPassphrase(t, tt.passphrase), base64crypt.NewBase64Decryptor())
+
+			decrypted, err := decryptor.Decrypt(tt.value)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Nil(t, decrypted)
+		})
+	}
+}
+
+func TestAESGCMDecryptorFallsBackToLegacy(t *testing.T) {
+	t.Parallel()
+
+	decryptor := aesgcm.NewAESGCMDecryptor(
+		passphraseprovider_mock.NewMockPassphraseProvider(t), base64crypt.NewBase64Decryptor())
+
+	decrypted, err := decryptor.Decrypt([]byte(base64.StdEncoding.EncodeToString([]byte("sk-legacy"))))
+	require.NoError(t, err)
+	require.Equal(t, "sk-legacy", string(decrypted))
+}
+
+func TestAESGCMEncryptorPropagatesPassphraseError(t *testing.T) {
+	t.Parallel()
+
+	passphraseErr := errors.New("no passphrase")
+
+	passphrase := passphraseprovider_mock.NewMockPassphraseProvider(t)
+	passphrase.EXPECT().ConfirmedPassphrase().Return(nil, passphraseErr)
+
+	encrypted, err := aesgcm.NewAESGCMEncryptor(passphrase).Encrypt([]byte("sk-secret"))
+	require.ErrorIs(t, err, passphraseErr)
+	require.Nil(t, encrypted)
+}
+
+// newPassphrase returns a provider of a constant passphrase. Not every call path requests it.
+func newPassphrase(t *testing.T, value string) *passphraseprovider_mock.MockPassphraseProvider {
+	t.Helper()
+
+	m := passphraseprovider_mock.NewMockPassphraseProvider(t)
+	m.EXPECT().Passphrase().Return([]byte(value), nil).Maybe()
+	m.EXPECT().ConfirmedPassphrase().Return([]byte(value), nil).Maybe()
+
+	return m
+}
@@ -0,0 +1,78 @@
+package aesgcm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+
+	"github.com/yaroslav-koval/hange/domain/crypt"
+)
+
+// NewAESGCMDecryptor creates a decryptor of envelopes produced by the AES-GCM encryptor.
+// Values without the envelope prefix were stored before encryption was introduced, they are passed to legacy.
+func NewAESGCMDecryptor(passphrase crypt.PassphraseProvider, legacy crypt.Decryptor) crypt.Decryptor {
+	return &aesGCMDecryptor{
+		passphrase: passphrase,
+		legacy:     legacy,
+	}
+}
+
+type aesGCMDecryptor struct {
+	passphrase crypt.PassphraseProvider
+	legacy     crypt.Decryptor
+}
+
+func (a *aesGCMDecryptor) Decrypt(value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, []byte(envelopePrefix)) {
+		slog.Debug("Value is not encrypted with AES-GCM, using legacy decryption. It is re-encrypted on the next save")
+
+		return a.legacy.Decrypt(value)
+	}
+
+	version, encoded, ok := bytes.Cut(value[len(envelopePrefix):], []byte(":"))
+	if !ok {
+		return nil, ErrInvalidEnvelope
+	}
+
+	if string(version) != versionV1 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, version)
+	}
+
+	payload := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+
+	n, err := base64.StdEncoding.Decode(payload, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEnvelope, err)
+	}
+
+	payload = payload[:n]
+
+	if len(payload) < saltLen {
+		return nil, ErrInvalidEnvelope
+	}
+
+	passphrase, err := a.passphrase.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, payload[:saltLen])
+	if err != nil {
+		return nil, err
+	}
+
+	payload = payload[saltLen:]
+	if len(payload) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrInvalidEnvelope
+	}
+
+	nonce, ciphertext := payload[:gcm.NonceSize()], payload[gcm.NonceSize():]
+
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plain, nil
+}
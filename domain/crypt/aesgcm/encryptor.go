@@ -0,0 +1,54 @@
+package aesgcm
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/yaroslav-koval/hange/domain/crypt"
+)
+
+func NewAESGCMEncryptor(passphrase crypt.PassphraseProvider) crypt.Encryptor {
+	return &aesGCMEncryptor{
+		passphrase: passphrase,
+	}
+}
+
+type aesGCMEncryptor struct {
+	passphrase crypt.PassphraseProvider
+}
+
+// Encrypt derives a key from the passphrase with a random salt, so equal values never produce equal envelopes.
+func (a *aesGCMEncryptor) Encrypt(value []byte) ([]byte, error) {
+	passphrase, err := a.passphrase.ConfirmedPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, 0, saltLen+len(nonce)+len(value)+gcm.Overhead())
+	payload = append(payload, salt...)
+	payload = append(payload, nonce...)
+	payload = gcm.Seal(payload, nonce, value, nil)
+
+	header := envelopePrefix + versionV1 + ":"
+
+	dst := make([]byte, len(header)+base64.StdEncoding.EncodedLen(len(payload)))
+	copy(dst, header)
+	base64.StdEncoding.Encode(dst[len(header):], payload)
+
+	return dst, nil
+}
@@ -0,0 +1,46 @@
+package aesgcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Envelope layout: "hange:v1:" + base64(salt | nonce | ciphertext with GCM tag).
+// The prefix keeps the value readable in a config file and lets to change the scheme later.
+const (
+	envelopePrefix = "hange:"
+	versionV1      = "v1"
+)
+
+const (
+	saltLen = 16
+	keyLen  = 32 // AES-256
+
+	// scrypt parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	ErrInvalidEnvelope    = errors.New("invalid encrypted value")
+	ErrUnsupportedVersion = errors.New("unsupported version of encrypted value")
+	ErrDecryptionFailed   = errors.New("failed to decrypt value, the passphrase may be wrong")
+)
+
+func newGCM(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -7,3 +7,11 @@ type Encryptor interface {
 type Decryptor interface {
 	Decrypt(value []byte) ([]byte, error)
 }
+
+// PassphraseProvider supplies a secret that encryption keys are derived from.
+type PassphraseProvider interface {
+	Passphrase() ([]byte, error)
+	// ConfirmedPassphrase is a passphrase a value is encrypted with. A typed passphrase is asked twice,
+	// so a typo doesn't make the value undecryptable.
+	ConfirmedPassphrase() ([]byte, error)
+}
@@ -0,0 +1,17 @@
+package noop
+
+import (
+	"github.com/yaroslav-koval/hange/domain/crypt"
+)
+
+// NewNoopDecryptor returns values as they are. It is used for secrets that are never stored by the app,
+// e.g. tokens printed by a credential helper.
+func NewNoopDecryptor() crypt.Decryptor {
+	return &noopDecryptor{}
+}
+
+type noopDecryptor struct{}
+
+func (n noopDecryptor) Decrypt(value []byte) ([]byte, error) {
+	return value, nil
+}
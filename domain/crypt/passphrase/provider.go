@@ -0,0 +1,163 @@
+package passphrase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/yaroslav-koval/hange/domain/crypt"
+	"golang.org/x/term"
+)
+
+var (
+	ErrNoPassphrase         = errors.New("passphrase is not available")
+	ErrEmptyPassphrase      = errors.New("empty passphrase")
+	ErrPassphrasesDontMatch = errors.New("passphrases don't match")
+)
+
+const (
+	prompt        = "Passphrase for the auth token: "
+	confirmPrompt = "Repeat the passphrase: "
+)
+
+// NewEnvTTYPassphraseProvider reads a passphrase from the env variable or prompts it on the terminal.
+// The terminal is opened directly, so a prompt works even if stdin is a pipe. The passphrase is asked once per process.
+func NewEnvTTYPassphraseProvider(envName string, promptOut io.Writer) crypt.PassphraseProvider {
+	return &envTTYPassphraseProvider{
+		envName:   envName,
+		promptOut: promptOut,
+		openTTY:   openTTY,
+		readPassword: func(tty *os.File) ([]byte, error) {
+			return term.ReadPassword(int(tty.Fd()))
+		},
+		mutex: &sync.Mutex{},
+	}
+}
+
+type envTTYPassphraseProvider struct {
+	envName      string
+	promptOut    io.Writer
+	openTTY      func() (*os.File, error)
+	readPassword func(tty *os.File) ([]byte, error)
+
+	mutex      *sync.Mutex
+	passphrase []byte
+	// confirmed is set if the passphrase is read from the env or typed twice
+	confirmed bool
+}
+
+func (p *envTTYPassphraseProvider) Passphrase() ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.passphrase != nil {
+		return p.passphrase, nil
+	}
+
+	if p.fromEnv() {
+		return p.passphrase, nil
+	}
+
+	tty, err := p.openPrompt()
+	if err != nil {
+		return nil, err
+	}
+	defer tty.Close()
+
+	v, err := p.read(tty, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	p.passphrase = v
+
+	return p.passphrase, nil
+}
+
+// ConfirmedPassphrase asks to repeat a typed passphrase. A passphrase typed once by Passphrase is only repeated.
+func (p *envTTYPassphraseProvider) ConfirmedPassphrase() ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.confirmed || (p.passphrase == nil && p.fromEnv()) {
+		return p.passphrase, nil
+	}
+
+	tty, err := p.openPrompt()
+	if err != nil {
+		return nil, err
+	}
+	defer tty.Close()
+
+	v := p.passphrase
+	if v == nil {
+		if v, err = p.read(tty, prompt); err != nil {
+			return nil, err
+		}
+	}
+
+	repeated, err := p.read(tty, confirmPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(v, repeated) {
+		return nil, ErrPassphrasesDontMatch
+	}
+
+	p.passphrase, p.confirmed = v, true
+
+	return p.passphrase, nil
+}
+
+func (p *envTTYPassphraseProvider) fromEnv() bool {
+	v := os.Getenv(p.envName)
+	if v == "" {
+		return false
+	}
+
+	p.passphrase, p.confirmed = []byte(v), true
+
+	return true
+}
+
+func (p *envTTYPassphraseProvider) openPrompt() (*os.File, error) {
+	tty, err := p.openTTY()
+	if err != nil {
+		return nil, fmt.Errorf("%w: set %s or run in a terminal", ErrNoPassphrase, p.envName)
+	}
+
+	return tty, nil
+}
+
+func (p *envTTYPassphraseProvider) read(tty *os.File, text string) ([]byte, error) {
+	if _, err := fmt.Fprint(p.promptOut, text); err != nil {
+		return nil, err
+	}
+
+	v, err := p.readPassword(tty)
+	_, _ = fmt.Fprintln(p.promptOut)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoPassphrase, err)
+	}
+
+	if len(v) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	return v, nil
+}
+
+func openTTY() (*os.File, error) {
+	name := "/dev/tty"
+	if runtime.GOOS == "windows" {
+		name = "CONIN$"
+	}
+
+	return os.OpenFile(name, os.O_RDWR, 0)
+}
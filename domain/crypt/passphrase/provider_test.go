@@ -0,0 +1,125 @@
+package passphrase
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEnvName = "HANGE_TEST_PASSPHRASE"
+
+func TestEnvTTYPassphraseProvider_FromEnv(t *testing.T) {
+	t.Setenv(testEnvName, "secret")
+
+	p := newTestProvider(t)
+
+	v, err := p.Passphrase()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), v)
+
+	// cached value is returned even if env is changed
+	t.Setenv(testEnvName, "other")
+
+	v, err = p.Passphrase()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), v)
+}
+
+func TestEnvTTYPassphraseProvider_NoTTY(t *testing.T) {
+	t.Setenv(testEnvName, "")
+
+	p := newTestProvider(t)
+
+	v, err := p.Passphrase()
+	require.ErrorIs(t, err, ErrNoPassphrase)
+	assert.ErrorContains(t, err, testEnvName)
+	assert.Nil(t, v)
+}
+
+func TestEnvTTYPassphraseProvider_ConfirmedPassphrase(t *testing.T) {
+	t.Run("env is used as is", func(t *testing.T) {
+		t.Setenv(testEnvName, "secret")
+
+		v, err := newTestProvider(t).ConfirmedPassphrase()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), v)
+	})
+
+	t.Run("typed twice", func(t *testing.T) {
+		t.Setenv(testEnvName, "")
+
+		p, out := newTestTTYProvider(t, "secret", "secret")
+
+		v, err := p.ConfirmedPassphrase()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), v)
+		assert.Equal(t, prompt+"\n"+confirmPrompt+"\n", out.String())
+
+		// the confirmed passphrase is reused without prompts
+		v, err = p.ConfirmedPassphrase()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), v)
+	})
+
+	t.Run("typed once is only repeated", func(t *testing.T) {
+		t.Setenv(testEnvName, "")
+
+		p, out := newTestTTYProvider(t, "secret", "secret")
+
+		_, err := p.Passphrase()
+		require.NoError(t, err)
+
+		v, err := p.ConfirmedPassphrase()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), v)
+		assert.Equal(t, prompt+"\n"+confirmPrompt+"\n", out.String())
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		t.Setenv(testEnvName, "")
+
+		p, _ := newTestTTYProvider(t, "secret", "secert")
+
+		v, err := p.ConfirmedPassphrase()
+		require.ErrorIs(t, err, ErrPassphrasesDontMatch)
+		assert.Nil(t, v)
+		assert.Nil(t, p.passphrase, "a mismatched passphrase is not kept")
+	})
+}
+
+func newTestProvider(t *testing.T) *envTTYPassphraseProvider {
+	t.Helper()
+
+	p := NewEnvTTYPassphraseProvider(testEnvName, &bytes.Buffer{}).(*envTTYPassphraseProvider)
+	p.openTTY = func() (*os.File, error) {
+		return nil, errors.New("no tty")
+	}
+
+	return p
+}
+
+// newTestTTYProvider returns a provider reading the answers from a terminal in order
+func newTestTTYProvider(t *testing.T, answers ...string) (*envTTYPassphraseProvider, *bytes.Buffer) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+
+	p := NewEnvTTYPassphraseProvider(testEnvName, out).(*envTTYPassphraseProvider)
+	p.openTTY = func() (*os.File, error) {
+		return os.Open(os.DevNull)
+	}
+	p.readPassword = func(*os.File) ([]byte, error) {
+		require.NotEmpty(t, answers, "unexpected prompt")
+
+		v := answers[0]
+		answers = answers[1:]
+
+		return []byte(v), nil
+	}
+
+	return p, out
+}
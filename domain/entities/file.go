@@ -3,4 +3,22 @@ package entities
 type File struct {
 	Path string
 	Data []byte
+	// ContentType is a media type detected from the content, e.g. "text/plain". Empty if unknown.
+	ContentType string
+}
+
+type SkipReason string
+
+const (
+	SkipReasonBinary    SkipReason = "binary"
+	SkipReasonTooLarge  SkipReason = "too large"
+	SkipReasonGenerated SkipReason = "generated"
+)
+
+// SkippedFile is a file that was found, but not processed by policy.
+type SkippedFile struct {
+	Path   string     `json:"path"`
+	Reason SkipReason `json:"reason"`
+	// Details explains the reason in a human-readable way, e.g. a detected content type or a size.
+	Details string `json:"details,omitempty"`
 }
@@ -0,0 +1,58 @@
+package entities
+
+import "time"
+
+// ResultVersion is a version of the Result schema. It changes only on backward incompatible changes.
+const ResultVersion = 1
+
+// Usage is an amount of tokens spent by LLM requests.
+type Usage struct {
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+	TotalTokens  int64 `json:"total_tokens"`
+}
+
+// Completion is a text generated by LLM along with the request metadata.
+type Completion struct {
+	Text  string
+	Model string
+	// Backend is a name of the profile which answered, if requests are routed between profiles.
+	Backend string
+	Usage   Usage
+	// Warnings are non-fatal issues that may reduce quality of the text, e.g. an incomplete response.
+	Warnings []string
+}
+
+// Result is a stable envelope of a command result shared by all the commands with machine-readable output.
+type Result struct {
+	Version        int           `json:"version"`
+	Command        string        `json:"command"`
+	Result         string        `json:"result"`
+	Model          string        `json:"model,omitempty"`
+	Backend        string        `json:"backend,omitempty"`
+	Usage          Usage         `json:"usage"`
+	FilesProcessed int           `json:"files_processed"`
+	Skipped        []SkippedFile `json:"skipped"`
+	DurationMs     int64         `json:"duration_ms"`
+	Warnings       []string      `json:"warnings"`
+}
+
+// NewResult creates a Result of the command from a completion. Slices are never nil, so JSON has arrays, not nulls.
+func NewResult(command string, c Completion, duration time.Duration) Result {
+	warnings := c.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return Result{
+		Version:    ResultVersion,
+		Command:    command,
+		Result:     c.Text,
+		Model:      c.Model,
+		Backend:    c.Backend,
+		Usage:      c.Usage,
+		Skipped:    []SkippedFile{},
+		DurationMs: duration.Milliseconds(),
+		Warnings:   warnings,
+	}
+}
@@ -0,0 +1,90 @@
+package agentfactory
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/commit"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/agent/explain"
+	"github.com/yaroslav-koval/hange/domain/agent/routing"
+	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+// NewDryRunFactory creates processors printing their requests to w instead of sending them. They don't read
+// the token. Other dependencies are created by next.
+func NewDryRunFactory(w io.Writer, next factory.AgentFactory) factory.AgentFactory {
+	return &dryRunFactory{
+		AgentFactory: next,
+		w:            w,
+	}
+}
+
+type dryRunFactory struct {
+	factory.AgentFactory
+	w io.Writer
+}
+
+func (d *dryRunFactory) CreateCommitProcessor(_ auth.Auth, p profile.Profile) (agent.CommitProcessor, error) {
+	if err := profile.ValidateProvider(p.Provider); err != nil {
+		return nil, err
+	}
+
+	return commit.NewRecorderCommitProcessor(d.w, p.Models.Commit, p.Prompts.Commit, p.ConventionalCommits), nil
+}
+
+func (d *dryRunFactory) CreateExplainProcessor(
+	_ auth.Auth, p profile.Profile, _ agent.ResourceJournal, _ agent.SyncStateStore,
+) (agent.ExplainProcessor, error) {
+	if err := profile.ValidateProvider(p.Provider); err != nil {
+		return nil, err
+	}
+
+	return explain.NewRecorderExplainProcessor(d.w, p.Models.Explain, p.Prompts.Explain), nil
+}
+
+// CreateCommitRouter prints the request of the first backend only
+func (d *dryRunFactory) CreateCommitRouter(
+	_ profile.Profile, backends []agent.CommitBackend, _ agent.BackendHealth,
+) (agent.CommitProcessor, error) {
+	if len(backends) == 0 {
+		return nil, routing.ErrNoBackends
+	}
+
+	return backends[0].Processor, nil
+}
+
+// CreateExplainRouter prints the request of the first backend only
+func (d *dryRunFactory) CreateExplainRouter(
+	backends []agent.ExplainBackend, _ agent.BackendHealth,
+) (agent.ExplainProcessor, error) {
+	if len(backends) == 0 {
+		return nil, routing.ErrNoBackends
+	}
+
+	return backends[0].Processor, nil
+}
+
+// CreateTokenVerifier creates a verifier which doesn't call the provider, so a dry run of auth status stays offline
+func (d *dryRunFactory) CreateTokenVerifier(p profile.Profile) (auth.TokenVerifier, error) {
+	if err := profile.ValidateProvider(p.Provider); err != nil {
+		return nil, err
+	}
+
+	return dryRunTokenVerifier{}, nil
+}
+
+type dryRunTokenVerifier struct{}
+
+func (dryRunTokenVerifier) Verify(context.Context, string) error {
+	return fmt.Errorf("token is not verified: %w", dryrun.ErrDryRun)
+}
+
+// CreateJanitor fails, so leftovers of previous runs are not swept by a dry run
+func (d *dryRunFactory) CreateJanitor(auth.Auth, profile.Profile) (agent.Janitor, error) {
+	return nil, fmt.Errorf("janitor is disabled: %w", dryrun.ErrDryRun)
+}
@@ -0,0 +1,52 @@
+package agentfactory
+
+import (
+	"errors"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/commit"
+	"github.com/yaroslav-koval/hange/domain/agent/explain"
+	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+var ErrOffline = errors.New("the provider is not used in offline mode")
+
+// NewOfflineFactory creates processors which don't use the provider. Commit messages are generated
+// by heuristics, explaining files fails. Other dependencies are created by next.
+func NewOfflineFactory(next factory.AgentFactory) factory.AgentFactory {
+	return &offlineFactory{AgentFactory: next}
+}
+
+type offlineFactory struct {
+	factory.AgentFactory
+}
+
+func (o *offlineFactory) CreateCommitProcessor(_ auth.Auth, p profile.Profile) (agent.CommitProcessor, error) {
+	return commit.NewHeuristicCommitProcessor(p.ConventionalCommits), nil
+}
+
+func (o *offlineFactory) CreateExplainProcessor(
+	auth.Auth, profile.Profile, agent.ResourceJournal, agent.SyncStateStore,
+) (agent.ExplainProcessor, error) {
+	return explain.NewUnavailableExplainProcessor(ErrOffline), nil
+}
+
+// CreateCommitRouter doesn't route, since the heuristics don't fail over
+func (o *offlineFactory) CreateCommitRouter(
+	p profile.Profile, _ []agent.CommitBackend, _ agent.BackendHealth,
+) (agent.CommitProcessor, error) {
+	return commit.NewHeuristicCommitProcessor(p.ConventionalCommits), nil
+}
+
+func (o *offlineFactory) CreateExplainRouter(
+	[]agent.ExplainBackend, agent.BackendHealth,
+) (agent.ExplainProcessor, error) {
+	return explain.NewUnavailableExplainProcessor(ErrOffline), nil
+}
+
+// CreateJanitor fails, so leftovers of previous runs are not swept in offline mode
+func (o *offlineFactory) CreateJanitor(auth.Auth, profile.Profile) (agent.Janitor, error) {
+	return nil, ErrOffline
+}
@@ -1,49 +1,197 @@
 package agentfactory
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
 	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/cleanup"
 	"github.com/yaroslav-koval/hange/domain/agent/commit"
 	"github.com/yaroslav-koval/hange/domain/agent/explain"
+	"github.com/yaroslav-koval/hange/domain/agent/routing"
 	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/auth/tokenverify"
 	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	"github.com/yaroslav-koval/hange/pkg/cassette"
+	"github.com/yaroslav-koval/hange/pkg/envs"
+	"github.com/yaroslav-koval/hange/pkg/logging"
 )
 
+// NewOpenAIFactory creates a factory of OpenAI clients. HANGE_CASSETTE makes them record or replay requests.
 func NewOpenAIFactory() factory.AgentFactory {
-	return &openAIFactory{}
+	return &openAIFactory{
+		cassette: os.Getenv(envs.EnvHangeCassette),
+		mutex:    &sync.Mutex{},
+	}
 }
 
 type openAIFactory struct {
+	cassette string
+	// transport is shared by clients, so they record to one cassette
+	transport http.RoundTripper
+	mutex     *sync.Mutex
+}
+
+// CreateCommitProcessor defers a failure to read the token, so the router can fall back.
+func (o *openAIFactory) CreateCommitProcessor(auth auth.Auth, p profile.Profile) (agent.CommitProcessor, error) {
+	c, err := o.createOpenAIClient(auth, p)
+
+	var tokenErr *tokenError
+	if errors.As(err, &tokenErr) {
+		return commit.NewUnavailableCommitProcessor(err), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return commit.NewOpenAICommitProcessor(c, p.Models.Commit, p.Prompts.Commit, p.ConventionalCommits), nil
 }
 
-func (o *openAIFactory) CreateCommitProcessor(auth auth.Auth) (agent.CommitProcessor, error) {
-	c, err := o.createOpenAIClient(auth)
+// CreateCommitRouter falls back to the heuristic processor if no backend answers, e.g. the token can't be read.
+func (o *openAIFactory) CreateCommitRouter(
+	p profile.Profile, backends []agent.CommitBackend, health agent.BackendHealth,
+) (agent.CommitProcessor, error) {
+	router, err := routing.NewCommitRouter(backends, health)
 	if err != nil {
 		return nil, err
 	}
 
-	return commit.NewOpenAICommitProcessor(c), nil
+	return commit.NewFallbackCommitProcessor(router, commit.NewHeuristicCommitProcessor(p.ConventionalCommits)), nil
+}
+
+func (o *openAIFactory) CreateExplainRouter(
+	backends []agent.ExplainBackend, health agent.BackendHealth,
+) (agent.ExplainProcessor, error) {
+	return routing.NewExplainRouter(backends, health)
+}
+
+func (o *openAIFactory) CreateExplainProcessor(
+	auth auth.Auth, p profile.Profile, journal agent.ResourceJournal, state agent.SyncStateStore,
+) (agent.ExplainProcessor, error) {
+	c, err := o.createOpenAIClient(auth, p)
+
+	var tokenErr *tokenError
+	if errors.As(err, &tokenErr) {
+		return explain.NewUnavailableExplainProcessor(err), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return explain.NewOpenAIExplainProcessor(c, p.Models.Explain, p.Prompts.Explain, journal, state), nil
 }
 
-func (o *openAIFactory) CreateExplainProcessor(auth auth.Auth) (agent.ExplainProcessor, error) {
-	c, err := o.createOpenAIClient(auth)
+func (o *openAIFactory) CreateJanitor(auth auth.Auth, p profile.Profile) (agent.Janitor, error) {
+	c, err := o.createOpenAIClient(auth, p)
 	if err != nil {
 		return nil, err
 	}
 
-	return explain.NewOpenAIExplainProcessor(c), nil
+	return cleanup.NewOpenAIJanitor(c), nil
+}
+
+func (o *openAIFactory) CreateTokenVerifier(p profile.Profile) (auth.TokenVerifier, error) {
+	if err := profile.ValidateProvider(p.Provider); err != nil {
+		return nil, err
+	}
+
+	return tokenverify.NewOpenAITokenVerifier(p.BaseURL), nil
 }
 
-func (o *openAIFactory) createOpenAIClient(auth auth.Auth) (*openai.Client, error) {
-	token, err := auth.GetToken()
+// createOpenAIClient creates a client of the profile. BaseURL points to an OpenAI compatible API, e.g. a local model.
+func (o *openAIFactory) createOpenAIClient(auth auth.Auth, p profile.Profile) (*openai.Client, error) {
+	if err := profile.ValidateProvider(p.Provider); err != nil {
+		return nil, err
+	}
+
+	mode, path, err := o.cassetteSpec()
 	if err != nil {
 		return nil, err
 	}
 
-	c := openai.NewClient(
+	// a replay doesn't send requests, so it doesn't need a token
+	token := "replay"
+
+	if mode != cassette.ModeReplay {
+		if token, err = auth.GetToken(); err != nil {
+			return nil, &tokenError{err: err}
+		}
+	}
+
+	opts := []option.RequestOption{
 		option.WithAPIKey(token),
-	)
+	}
+
+	if mode != "" {
+		transport, err := o.cassetteTransport(mode, path, token)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, option.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+
+	if p.BaseURL != "" {
+		opts = append(opts, option.WithBaseURL(p.BaseURL))
+	}
+
+	c := openai.NewClient(opts...)
 
 	return &c, nil
 }
+
+// tokenError lets processors defer the failure, so a command which doesn't need them runs without a token
+type tokenError struct {
+	err error
+}
+
+func (e *tokenError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tokenError) Unwrap() error {
+	return e.err
+}
+
+// Is lets the router try the next backend instead of the one without a token
+func (e *tokenError) Is(target error) bool {
+	return target == routing.ErrUnavailable
+}
+
+func (o *openAIFactory) cassetteSpec() (cassette.Mode, string, error) {
+	if o.cassette == "" {
+		return "", "", nil
+	}
+
+	mode, path, err := cassette.ParseSpec(o.cassette)
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", envs.EnvHangeCassette, err)
+	}
+
+	return mode, path, nil
+}
+
+func (o *openAIFactory) cassetteTransport(mode cassette.Mode, path, token string) (http.RoundTripper, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.transport != nil {
+		return o.transport, nil
+	}
+
+	transport, err := cassette.New(mode, path, http.DefaultTransport, token)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Using cassette", slog.String(logging.KeyFile, path), slog.String("mode", string(mode)))
+
+	o.transport = transport
+
+	return transport, nil
+}
@@ -0,0 +1,149 @@
+package agentfactory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/commit"
+	"github.com/yaroslav-koval/hange/domain/agent/dryrun"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/agent/routing"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	auth_mock "github.com/yaroslav-koval/hange/mocks/auth"
+	backendhealth_mock "github.com/yaroslav-koval/hange/mocks/backendhealth"
+	resourcejournal_mock "github.com/yaroslav-koval/hange/mocks/resourcejournal"
+)
+
+// The tests replay cassettes, so the auth mock fails them if the token is read
+
+func TestOpenAIFactory_ReplayExplain(t *testing.T) {
+	t.Parallel()
+
+	f := newTestFactory("replay:testdata/explain.cassette.json")
+	au := auth_mock.NewMockAuth(t)
+
+	journal := resourcejournal_mock.NewMockResourceJournal(t)
+	journal.EXPECT().Created(mock.Anything).Return(nil).Times(2)
+	journal.EXPECT().Deleted(mock.Anything).Return(nil).Times(2)
+
+	ep, err := f.CreateExplainProcessor(au, profile.Profile{}, journal, nil)
+	require.NoError(t, err)
+
+	cp, err := f.CreateCommitProcessor(au, profile.Profile{})
+	require.NoError(t, err)
+
+	ag, err := agent.NewAgent(cp, ep)
+	require.NoError(t, err)
+
+	files := make(chan entities.File, 1)
+	files <- entities.File{Path: "main.go", Data: []byte("package main")}
+	close(files)
+
+	res, err := ag.ExplainFiles(context.Background(), files, entity.ExplainOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "main.go prints a greeting", res.Text)
+}
+
+func TestOpenAIFactory_ReplayCommit(t *testing.T) {
+	t.Parallel()
+
+	f := newTestFactory("replay:testdata/commit.cassette.json")
+
+	cp, err := f.CreateCommitProcessor(auth_mock.NewMockAuth(t), profile.Profile{})
+	require.NoError(t, err)
+
+	res, err := cp.GenCommitMessage(context.Background(), entity.CommitData{Status: "M a.go", Diff: "+x"})
+	require.NoError(t, err)
+	require.Equal(t, "Add cassette replay", res.Text)
+}
+
+func TestOpenAIFactory_InvalidCassette(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTestFactory("rewind:file.json").CreateCommitProcessor(auth_mock.NewMockAuth(t), profile.Profile{})
+	require.ErrorContains(t, err, "HANGE_CASSETTE")
+}
+
+func TestOpenAIFactory_MissingToken(t *testing.T) {
+	t.Parallel()
+
+	tokenErr := errors.New("token is not found")
+
+	f := newTestFactory("")
+	au := auth_mock.NewMockAuth(t)
+	au.EXPECT().GetToken().Return("", tokenErr)
+
+	cp, err := f.CreateCommitProcessor(au, profile.Profile{})
+	require.NoError(t, err)
+
+	health := backendhealth_mock.NewMockBackendHealth(t)
+	health.EXPECT().Healthy("default").Return(true, nil)
+
+	cp, err = f.CreateCommitRouter(profile.Profile{}, []agent.CommitBackend{{Name: "default", Processor: cp}}, health)
+	require.NoError(t, err)
+
+	res, err := cp.GenCommitMessage(context.Background(), entity.CommitData{
+		Diff: "diff --git a/a.go b/a.go\nnew file mode 100644\n+func Run() {}\n",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "a.go: add Run", res.Text)
+	require.Equal(t, commit.HeuristicModel, res.Model)
+	require.Len(t, res.Warnings, 1)
+
+	ep, err := f.CreateExplainProcessor(au, profile.Profile{}, nil, nil)
+	require.NoError(t, err)
+	err = ep.ProcessFiles(context.Background(), nil, entity.ExplainOptions{})
+	require.ErrorIs(t, err, tokenErr)
+	require.ErrorIs(t, err, routing.ErrUnavailable, "the router tries the next backend")
+}
+
+func TestOfflineFactory(t *testing.T) {
+	t.Parallel()
+
+	f := NewOfflineFactory(newTestFactory(""))
+	au := auth_mock.NewMockAuth(t)
+
+	cp, err := f.CreateCommitRouter(profile.Profile{ConventionalCommits: true}, nil, nil)
+	require.NoError(t, err)
+
+	res, err := cp.GenCommitMessage(context.Background(), entity.CommitData{
+		Diff: "diff --git a/README.md b/README.md\n+docs\n",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "docs: update README.md", res.Text)
+
+	ep, err := f.CreateExplainRouter(nil, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, ep.ProcessFiles(context.Background(), nil, entity.ExplainOptions{}), ErrOffline)
+
+	_, err = f.CreateJanitor(au, profile.Profile{})
+	require.ErrorIs(t, err, ErrOffline)
+}
+
+func TestDryRunFactory_TokenVerifier(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("a dry run called the provider")
+	}))
+	t.Cleanup(srv.Close)
+
+	v, err := NewDryRunFactory(io.Discard, newTestFactory("")).CreateTokenVerifier(profile.Profile{BaseURL: srv.URL})
+	require.NoError(t, err)
+	require.ErrorIs(t, v.Verify(context.Background(), "sk-proj-abcdefghx9Qz"), dryrun.ErrDryRun)
+}
+
+func newTestFactory(cassette string) *openAIFactory {
+	f := NewOpenAIFactory().(*openAIFactory)
+	f.cassette = cassette
+
+	return f
+}
@@ -1,35 +1,66 @@
 package factory
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
 	"github.com/yaroslav-koval/hange/domain/agent"
 	"github.com/yaroslav-koval/hange/domain/auth"
 	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
 	"github.com/yaroslav-koval/hange/domain/crypt"
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
 	"github.com/yaroslav-koval/hange/domain/git"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	"github.com/yaroslav-koval/hange/pkg/logging"
 )
 
+// sweepTimeout limits the cleanup of leftovers, so a slow provider doesn't delay the run much
+const sweepTimeout = 10 * time.Second
+
 type AppBuilder interface {
 	GetAuth() (auth.Auth, error)
 	GetAIAgent() (agent.AIAgent, error)
+	GetJanitor() (agent.Janitor, error)
+	GetResourceJournal() (agent.ResourceJournal, error)
 	GetConfigurator() (config.Configurator, error)
+	// GetConfigMigrator doesn't create a configurator, so the config can be checked before it is migrated.
+	GetConfigMigrator() (config.Migrator, error)
 	GetFileProvider() (fileprovider.FileProvider, error)
 	GetGitChangesProvider() (git.ChangesProvider, error)
+	GetProfileManager() (profile.ProfileManager, error)
+	// GetProfile returns the active profile.
+	GetProfile() (profile.Profile, error)
 }
 
 type AppFactory interface {
 	CreateConfigurator() (config.Configurator, error)
-	CreateTokenFetcher(config.Configurator) (auth.TokenFetcher, error)
-	CreateTokenStorer(config.Configurator) (auth.TokenStorer, error)
-	CreateBase64Encryptor() (crypt.Encryptor, error)
-	CreateBase64Decryptor() (crypt.Decryptor, error)
+	CreateConfigMigrator() (config.Migrator, error)
+	CreateProfileManager(config.Configurator) (profile.ProfileManager, error)
+	CreateResourceJournal(config.Configurator, profile.Profile) (agent.ResourceJournal, error)
+	CreateSyncStateStore(config.Configurator, profile.Profile) (agent.SyncStateStore, error)
+	CreateBackendHealth(config.Configurator) (agent.BackendHealth, error)
+	CreateTokenFetcher(config.Configurator, profile.Profile) (auth.TokenFetcher, error)
+	CreateTokenStorer(config.Configurator, profile.Profile) (auth.TokenStorer, error)
+	CreateEncryptor() (crypt.Encryptor, error)
+	CreateDecryptor(profile.Profile) (crypt.Decryptor, error)
 	CreateFileProvider() (fileprovider.FileProvider, error)
 	CreateGitChangesProvider() (git.ChangesProvider, error)
 }
 
 type AgentFactory interface {
-	CreateCommitProcessor(auth.Auth) (agent.CommitProcessor, error)
-	CreateExplainProcessor(auth.Auth) (agent.ExplainProcessor, error)
+	CreateCommitProcessor(auth.Auth, profile.Profile) (agent.CommitProcessor, error)
+	CreateExplainProcessor(
+		auth.Auth, profile.Profile, agent.ResourceJournal, agent.SyncStateStore,
+	) (agent.ExplainProcessor, error)
+	// CreateCommitRouter combines processors of the backends. The profile is the active one.
+	CreateCommitRouter(profile.Profile, []agent.CommitBackend, agent.BackendHealth) (agent.CommitProcessor, error)
+	CreateExplainRouter([]agent.ExplainBackend, agent.BackendHealth) (agent.ExplainProcessor, error)
+	CreateTokenVerifier(profile.Profile) (auth.TokenVerifier, error)
+	CreateJanitor(auth.Auth, profile.Profile) (agent.Janitor, error)
 }
 
 func NewAppBuilder(appFactory AppFactory, agentFactory AgentFactory) AppBuilder {
@@ -38,9 +69,14 @@ func NewAppBuilder(appFactory AppFactory, agentFactory AgentFactory) AppBuilder
 		agentFactory: agentFactory,
 		au:           newLazyInitializer[auth.Auth](),
 		ag:           newLazyInitializer[agent.AIAgent](),
+		ja:           newLazyInitializer[agent.Janitor](),
+		jo:           newLazyInitializer[agent.ResourceJournal](),
 		cfg:          newLazyInitializer[config.Configurator](),
+		mig:          newLazyInitializer[config.Migrator](),
 		fp:           newLazyInitializer[fileprovider.FileProvider](),
 		gi:           newLazyInitializer[git.ChangesProvider](),
+		pm:           newLazyInitializer[profile.ProfileManager](),
+		pr:           newLazyInitializer[profile.Profile](),
 	}
 }
 
@@ -50,9 +86,14 @@ type lazyAppBuilder struct {
 
 	au  *lazyInitializer[auth.Auth]
 	ag  *lazyInitializer[agent.AIAgent]
+	ja  *lazyInitializer[agent.Janitor]
+	jo  *lazyInitializer[agent.ResourceJournal]
 	cfg *lazyInitializer[config.Configurator]
+	mig *lazyInitializer[config.Migrator]
 	fp  *lazyInitializer[fileprovider.FileProvider]
 	gi  *lazyInitializer[git.ChangesProvider]
+	pm  *lazyInitializer[profile.ProfileManager]
+	pr  *lazyInitializer[profile.Profile]
 }
 
 func (ab *lazyAppBuilder) GetAuth() (auth.Auth, error) {
@@ -62,53 +103,296 @@ func (ab *lazyAppBuilder) GetAuth() (auth.Auth, error) {
 			return nil, err
 		}
 
-		tokenStorer, err := ab.appFactory.CreateTokenStorer(configurator)
+		p, err := ab.GetProfile()
+		if err != nil {
+			return nil, err
+		}
+
+		return ab.createAuth(configurator, p)
+	})
+}
+
+func (ab *lazyAppBuilder) createAuth(configurator config.Configurator, p profile.Profile) (auth.Auth, error) {
+	tokenStorer, err := ab.appFactory.CreateTokenStorer(configurator, p)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenFetcher, err := ab.appFactory.CreateTokenFetcher(configurator, p)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenVerifier, err := ab.agentFactory.CreateTokenVerifier(p)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptor, err := ab.appFactory.CreateEncryptor()
+	if err != nil {
+		return nil, err
+	}
+
+	decryptor, err := ab.appFactory.CreateDecryptor(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewAuth(
+		tokenStorer,
+		tokenFetcher,
+		tokenVerifier,
+		encryptor,
+		decryptor,
+	), nil
+}
+
+func (ab *lazyAppBuilder) GetAIAgent() (agent.AIAgent, error) {
+	return ab.ag.Get(func() (agent.AIAgent, error) {
+		configurator, err := ab.GetConfigurator()
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := ab.GetProfile()
 		if err != nil {
 			return nil, err
 		}
 
-		tokenFetcher, err := ab.appFactory.CreateTokenFetcher(configurator)
+		health, err := ab.appFactory.CreateBackendHealth(configurator)
 		if err != nil {
 			return nil, err
 		}
 
-		encryptor, err := ab.appFactory.CreateBase64Encryptor()
+		cp, err := ab.createCommitRouter(configurator, p, health)
 		if err != nil {
 			return nil, err
 		}
 
-		decryptor, err := ab.appFactory.CreateBase64Decryptor()
+		ep, journals, err := ab.createExplainRouter(configurator, health)
 		if err != nil {
 			return nil, err
 		}
 
-		return auth.NewAuth(
-			tokenStorer,
-			tokenFetcher,
-			encryptor,
-			decryptor,
-		), nil
+		ab.sweepLeftovers(p, journals)
+
+		return agent.NewAgent(cp, ep)
 	})
 }
 
-func (ab *lazyAppBuilder) GetAIAgent() (agent.AIAgent, error) {
-	return ab.ag.Get(func() (agent.AIAgent, error) {
+func (ab *lazyAppBuilder) createCommitRouter(
+	configurator config.Configurator, p profile.Profile, health agent.BackendHealth,
+) (agent.CommitProcessor, error) {
+	profiles, err := ab.backendProfiles(configurator, consts.RoutingCommitPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]agent.CommitBackend, 0, len(profiles))
+
+	for _, bp := range profiles {
+		au, err := ab.backendAuth(configurator, bp)
+		if err != nil {
+			return nil, err
+		}
+
+		cp, err := ab.agentFactory.CreateCommitProcessor(au, bp)
+		if err != nil {
+			return nil, err
+		}
+
+		backends = append(backends, agent.CommitBackend{Name: bp.Name, Processor: cp})
+	}
+
+	return ab.agentFactory.CreateCommitRouter(p, backends, health)
+}
+
+// backendJournal is a journal of a backend with the auth its leftovers are deleted with
+type backendJournal struct {
+	profile profile.Profile
+	auth    auth.Auth
+	journal agent.ResourceJournal
+}
+
+// createExplainRouter also returns the journals of the backends, so their leftovers are swept
+func (ab *lazyAppBuilder) createExplainRouter(
+	configurator config.Configurator, health agent.BackendHealth,
+) (agent.ExplainProcessor, []backendJournal, error) {
+	profiles, err := ab.backendProfiles(configurator, consts.RoutingExplainPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backends := make([]agent.ExplainBackend, 0, len(profiles))
+	journals := make([]backendJournal, 0, len(profiles))
+
+	for _, bp := range profiles {
+		au, err := ab.backendAuth(configurator, bp)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// remote resources belong to the profile, so each backend has its own journal records and sync state
+		journal, err := ab.appFactory.CreateResourceJournal(configurator, bp)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		state, err := ab.appFactory.CreateSyncStateStore(configurator, bp)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ep, err := ab.agentFactory.CreateExplainProcessor(au, bp, journal, state)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		backends = append(backends, agent.ExplainBackend{Name: bp.Name, Processor: ep})
+		journals = append(journals, backendJournal{profile: bp, auth: au, journal: journal})
+	}
+
+	router, err := ab.agentFactory.CreateExplainRouter(backends, health)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return router, journals, nil
+}
+
+// backendProfiles returns the profiles listed by the routing key in order, the active profile if the list is empty
+func (ab *lazyAppBuilder) backendProfiles(configurator config.Configurator, path string) ([]profile.Profile, error) {
+	active, err := ab.GetProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := config.ReadStringSlice(configurator, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		return []profile.Profile{active}, nil
+	}
+
+	pm, err := ab.GetProfileManager()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]profile.Profile, 0, len(names))
+
+	for _, name := range names {
+		if name == active.Name {
+			profiles = append(profiles, active)
+			continue
+		}
+
+		p, err := pm.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	return profiles, nil
+}
+
+// backendAuth reuses the auth of the active profile, so its passphrase or token command runs once
+func (ab *lazyAppBuilder) backendAuth(configurator config.Configurator, p profile.Profile) (auth.Auth, error) {
+	active, err := ab.GetProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Name == active.Name {
+		return ab.GetAuth()
+	}
+
+	return ab.createAuth(configurator, p)
+}
+
+// sweepLeftovers deletes resources of crashed runs before the agent creates new ones. Journals of all the backends
+// and of the active profile are swept, each with a janitor of its profile, within one timeout. It is best-effort,
+// so a failure doesn't stop the run.
+func (ab *lazyAppBuilder) sweepLeftovers(active profile.Profile, journals []backendJournal) {
+	if !slices.ContainsFunc(journals, func(j backendJournal) bool { return j.profile.Name == active.Name }) {
 		au, err := ab.GetAuth()
+		if err != nil {
+			slog.Debug("Skipping leftovers cleanup", slog.String("profile", active.Name), logging.Err(err))
+		} else if journal, err := ab.GetResourceJournal(); err != nil {
+			slog.Debug("Skipping leftovers cleanup", slog.String("profile", active.Name), logging.Err(err))
+		} else {
+			journals = append(journals, backendJournal{profile: active, auth: au, journal: journal})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sweepTimeout)
+	defer cancel()
+
+	deleted := 0
+
+	for _, j := range journals {
+		janitor, err := ab.backendJanitor(active, j)
+		if err != nil {
+			slog.Debug("Skipping leftovers cleanup", slog.String("profile", j.profile.Name), logging.Err(err))
+			continue
+		}
+
+		n, err := agent.SweepLeftovers(ctx, j.journal, janitor)
+		if err != nil {
+			slog.Warn("Failed to clean up leftovers of previous runs", slog.String("profile", j.profile.Name),
+				logging.Err(err))
+		}
+
+		deleted += n
+	}
+
+	if deleted > 0 {
+		slog.Info("Deleted remote resources left by interrupted runs", slog.Int("deleted", deleted))
+	}
+}
+
+// backendJanitor reuses the janitor of the active profile
+func (ab *lazyAppBuilder) backendJanitor(active profile.Profile, j backendJournal) (agent.Janitor, error) {
+	if j.profile.Name == active.Name {
+		return ab.GetJanitor()
+	}
+
+	return ab.agentFactory.CreateJanitor(j.auth, j.profile)
+}
+
+func (ab *lazyAppBuilder) GetResourceJournal() (agent.ResourceJournal, error) {
+	return ab.jo.Get(func() (agent.ResourceJournal, error) {
+		configurator, err := ab.GetConfigurator()
 		if err != nil {
 			return nil, err
 		}
 
-		cp, err := ab.agentFactory.CreateCommitProcessor(au)
+		p, err := ab.GetProfile()
 		if err != nil {
 			return nil, err
 		}
 
-		ep, err := ab.agentFactory.CreateExplainProcessor(au)
+		return ab.appFactory.CreateResourceJournal(configurator, p)
+	})
+}
+
+func (ab *lazyAppBuilder) GetJanitor() (agent.Janitor, error) {
+	return ab.ja.Get(func() (agent.Janitor, error) {
+		au, err := ab.GetAuth()
 		if err != nil {
 			return nil, err
 		}
 
-		return agent.NewAgent(cp, ep)
+		p, err := ab.GetProfile()
+		if err != nil {
+			return nil, err
+		}
+
+		return ab.agentFactory.CreateJanitor(au, p)
 	})
 }
 
@@ -118,6 +402,12 @@ func (ab *lazyAppBuilder) GetConfigurator() (config.Configurator, error) {
 	})
 }
 
+func (ab *lazyAppBuilder) GetConfigMigrator() (config.Migrator, error) {
+	return ab.mig.Get(func() (config.Migrator, error) {
+		return ab.appFactory.CreateConfigMigrator()
+	})
+}
+
 func (ab *lazyAppBuilder) GetFileProvider() (fileprovider.FileProvider, error) {
 	return ab.fp.Get(func() (fileprovider.FileProvider, error) {
 		return ab.appFactory.CreateFileProvider()
@@ -129,3 +419,25 @@ func (ab *lazyAppBuilder) GetGitChangesProvider() (git.ChangesProvider, error) {
 		return ab.appFactory.CreateGitChangesProvider()
 	})
 }
+
+func (ab *lazyAppBuilder) GetProfileManager() (profile.ProfileManager, error) {
+	return ab.pm.Get(func() (profile.ProfileManager, error) {
+		configurator, err := ab.GetConfigurator()
+		if err != nil {
+			return nil, err
+		}
+
+		return ab.appFactory.CreateProfileManager(configurator)
+	})
+}
+
+func (ab *lazyAppBuilder) GetProfile() (profile.Profile, error) {
+	return ab.pr.Get(func() (profile.Profile, error) {
+		pm, err := ab.GetProfileManager()
+		if err != nil {
+			return profile.Profile{}, err
+		}
+
+		return pm.Get(pm.Active())
+	})
+}
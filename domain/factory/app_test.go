@@ -0,0 +1,69 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	agentfactory_mock "github.com/yaroslav-koval/hange/mocks/agentfactory"
+	appfactory_mock "github.com/yaroslav-koval/hange/mocks/appfactory"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+	janitor_mock "github.com/yaroslav-koval/hange/mocks/janitor"
+	profilemanager_mock "github.com/yaroslav-koval/hange/mocks/profilemanager"
+	resourcejournal_mock "github.com/yaroslav-koval/hange/mocks/resourcejournal"
+)
+
+func TestLazyAppBuilder_GetAIAgentSweepsEveryBackend(t *testing.T) {
+	active := profile.Profile{Name: "default"}
+	work := profile.Profile{Name: "work"}
+	personal := profile.Profile{Name: "personal"}
+
+	cfg := configurator_mock.NewMockConfigurator(t)
+	cfg.EXPECT().ReadField(consts.RoutingCommitPath).Return(nil)
+	cfg.EXPECT().ReadField(consts.RoutingExplainPath).Return([]string{work.Name, personal.Name})
+
+	pm := profilemanager_mock.NewMockProfileManager(t)
+	pm.EXPECT().Active().Return(active.Name)
+	pm.EXPECT().Get(active.Name).Return(active, nil)
+	pm.EXPECT().Get(work.Name).Return(work, nil)
+	pm.EXPECT().Get(personal.Name).Return(personal, nil)
+
+	appFactory := appfactory_mock.NewMockAppFactory(t)
+	appFactory.EXPECT().CreateConfigurator().Return(cfg, nil)
+	appFactory.EXPECT().CreateProfileManager(cfg).Return(pm, nil)
+	appFactory.EXPECT().CreateBackendHealth(cfg).Return(nil, nil)
+	appFactory.EXPECT().CreateTokenStorer(cfg, mock.Anything).Return(nil, nil)
+	appFactory.EXPECT().CreateTokenFetcher(cfg, mock.Anything).Return(nil, nil)
+	appFactory.EXPECT().CreateEncryptor().Return(nil, nil)
+	appFactory.EXPECT().CreateDecryptor(mock.Anything).Return(nil, nil)
+	appFactory.EXPECT().CreateSyncStateStore(cfg, mock.Anything).Return(nil, nil)
+
+	agentFactory := agentfactory_mock.NewMockAgentFactory(t)
+	agentFactory.EXPECT().CreateTokenVerifier(mock.Anything).Return(nil, nil)
+	agentFactory.EXPECT().CreateCommitProcessor(mock.Anything, active).Return(nil, nil)
+	agentFactory.EXPECT().CreateCommitRouter(active, mock.Anything, nil).Return(nil, nil)
+	agentFactory.EXPECT().CreateExplainProcessor(mock.Anything, mock.Anything, mock.Anything, nil).Return(nil, nil)
+	agentFactory.EXPECT().CreateExplainRouter(mock.Anything, nil).Return(nil, nil)
+
+	// the active profile isn't a backend, but its journal may have leftovers of runs before the routing
+	for _, p := range []profile.Profile{active, work, personal} {
+		leftover := entity.RemoteResource{Kind: entity.RemoteFile, ID: "file_" + p.Name}
+
+		journal := resourcejournal_mock.NewMockResourceJournal(t)
+		journal.EXPECT().Leftovers().Return([]entity.RemoteResource{leftover}, nil)
+		journal.EXPECT().Deleted(leftover).Return(nil)
+		appFactory.EXPECT().CreateResourceJournal(cfg, p).Return(journal, nil).Once()
+
+		janitor := janitor_mock.NewMockJanitor(t)
+		janitor.EXPECT().Delete(mock.Anything, []entity.RemoteResource{leftover}).
+			Return([]entity.CleanupResult{{Resource: leftover}})
+		agentFactory.EXPECT().CreateJanitor(mock.Anything, p).Return(janitor, nil).Once()
+	}
+
+	ag, err := NewAppBuilder(appFactory, agentFactory).GetAIAgent()
+	require.NoError(t, err)
+	require.NotNil(t, ag)
+}
@@ -1,50 +1,174 @@
 package appfactory
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yaroslav-koval/hange/domain/agent"
+	"github.com/yaroslav-koval/hange/domain/agent/health"
+	"github.com/yaroslav-koval/hange/domain/agent/journal"
+	"github.com/yaroslav-koval/hange/domain/agent/syncstate"
 	"github.com/yaroslav-koval/hange/domain/auth"
 	"github.com/yaroslav-koval/hange/domain/auth/tokenfetch"
 	"github.com/yaroslav-koval/hange/domain/auth/tokenstore"
 	"github.com/yaroslav-koval/hange/domain/config"
 	"github.com/yaroslav-koval/hange/domain/config/configcli"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
 	"github.com/yaroslav-koval/hange/domain/crypt"
+	"github.com/yaroslav-koval/hange/domain/crypt/aesgcm"
 	"github.com/yaroslav-koval/hange/domain/crypt/base64"
+	"github.com/yaroslav-koval/hange/domain/crypt/noop"
+	"github.com/yaroslav-koval/hange/domain/crypt/passphrase"
 	"github.com/yaroslav-koval/hange/domain/factory"
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/archiveprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/contentclassifier"
 	"github.com/yaroslav-koval/hange/domain/fileprovider/errmapper"
 	"github.com/yaroslav-koval/hange/domain/fileprovider/filecontentprovider"
 	"github.com/yaroslav-koval/hange/domain/fileprovider/filenamesprovider"
 	"github.com/yaroslav-koval/hange/domain/git"
 	"github.com/yaroslav-koval/hange/domain/git/gitadapter"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	"github.com/yaroslav-koval/hange/domain/profile/configprofile"
+	"github.com/yaroslav-koval/hange/pkg/envs"
+)
+
+// archive limits are generous for source tarballs and still stop decompression bombs
+const (
+	maxArchiveEntries   = 10_000
+	maxArchiveTotalSize = 256 << 20 // 256 MiB
 )
 
-func NewCLIFactory(configPath string) factory.AppFactory {
+// syncStateDir is a directory of states of persistent vector stores in the config directory
+const syncStateDir = "stores"
+
+// NewCLIFactory creates a factory of CLI dependencies. profileName overrides the active profile if it is not empty.
+// workDir is the directory of the repository config and of git commands, the working directory if it is empty.
+func NewCLIFactory(configPath, profileName, workDir string) factory.AppFactory {
 	return &cliFactory{
-		configPath: configPath,
+		configPath:  configPath,
+		profileName: profileName,
+		workDir:     workDir,
+		passphrase:  passphrase.NewEnvTTYPassphraseProvider(envs.EnvHangePassphrase, os.Stderr),
 	}
 }
 
 type cliFactory struct {
-	configPath string
+	configPath  string
+	profileName string
+	workDir     string
+	// passphrase is shared by encryptor and decryptor, so it is asked once
+	passphrase crypt.PassphraseProvider
 }
 
+// CreateConfigurator merges the repository config of the working directory over the user config.
 func (c *cliFactory) CreateConfigurator() (config.Configurator, error) {
-	return configcli.NewCLIConfig(c.configPath)
+	workDir, err := c.getWorkDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return configcli.NewCLIConfig(c.configPath, workDir)
+}
+
+func (c *cliFactory) getWorkDir() (string, error) {
+	if c.workDir != "" {
+		return c.workDir, nil
+	}
+
+	return os.Getwd()
+}
+
+func (c *cliFactory) CreateConfigMigrator() (config.Migrator, error) {
+	return configcli.NewMigrator(c.configPath)
+}
+
+// CreateResourceJournal keeps the journal next to the config file.
+func (c *cliFactory) CreateResourceJournal(
+	configurator config.Configurator, p profile.Profile) (agent.ResourceJournal, error) {
+	return journal.NewFileJournal(filepath.Join(filepath.Dir(configurator.Path()), journal.FileName), p.Name), nil
+}
+
+// CreateSyncStateStore keeps states of persistent vector stores next to the config file. A state belongs to
+// the repository of the working directory and to the profile, since another token can't access the vector store.
+// The processor rejects files outside the repository.
+func (c *cliFactory) CreateSyncStateStore(
+	configurator config.Configurator, p profile.Profile) (agent.SyncStateStore, error) {
+	workDir, err := c.getWorkDir()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.FindRoot(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if repo == "" {
+		repo = workDir
+	}
+
+	key := sha256.Sum256([]byte(p.Name + "\x00" + repo))
+	path := filepath.Join(filepath.Dir(configurator.Path()), syncStateDir, hex.EncodeToString(key[:8])+".json")
+
+	return syncstate.NewFileSyncStateStore(path, repo), nil
 }
 
-func (c *cliFactory) CreateTokenFetcher(configurator config.Configurator) (auth.TokenFetcher, error) {
-	return tokenfetch.NewConfigTokenFetcher(configurator), nil
+// CreateBackendHealth keeps cooldowns of failed profiles next to the config file, so they outlive a run.
+func (c *cliFactory) CreateBackendHealth(configurator config.Configurator) (agent.BackendHealth, error) {
+	cooldown := health.DefaultCooldown
+
+	if v, ok := configurator.ReadField(consts.RoutingCooldownPath).(string); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s must be a positive duration, e.g. \"5m\"", consts.RoutingCooldownPath)
+		}
+
+		cooldown = d
+	}
+
+	return health.NewFileBackendHealth(filepath.Join(filepath.Dir(configurator.Path()), health.FileName), cooldown), nil
 }
 
-func (c *cliFactory) CreateTokenStorer(configurator config.Configurator) (auth.TokenStorer, error) {
-	return tokenstore.NewConfigTokenStorer(configurator), nil
+func (c *cliFactory) CreateProfileManager(configurator config.Configurator) (profile.ProfileManager, error) {
+	return configprofile.NewConfigProfileManager(configurator, c.profileName), nil
 }
 
-func (c *cliFactory) CreateBase64Encryptor() (crypt.Encryptor, error) {
-	return base64.NewBase64Encryptor(), nil
+// CreateTokenFetcher prefers a credential helper command over the stored token if the command is configured.
+func (c *cliFactory) CreateTokenFetcher(
+	configurator config.Configurator, p profile.Profile) (auth.TokenFetcher, error) {
+	cmdCfg, err := tokenfetch.ReadCommandConfig(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmdCfg.Command != "" {
+		return tokenfetch.NewCommandTokenFetcher(cmdCfg), nil
+	}
+
+	return tokenfetch.NewConfigTokenFetcher(configurator, p.Name), nil
 }
 
-func (c *cliFactory) CreateBase64Decryptor() (crypt.Decryptor, error) {
-	return base64.NewBase64Decryptor(), nil
+func (c *cliFactory) CreateTokenStorer(configurator config.Configurator, p profile.Profile) (auth.TokenStorer, error) {
+	return tokenstore.NewConfigTokenStorer(configurator, p.Name), nil
+}
+
+func (c *cliFactory) CreateEncryptor() (crypt.Encryptor, error) {
+	return aesgcm.NewAESGCMEncryptor(c.passphrase), nil
+}
+
+// CreateDecryptor reads tokens stored by older versions as base64 too. They are re-encrypted on the next save.
+// A token printed by a credential helper is plain text.
+func (c *cliFactory) CreateDecryptor(p profile.Profile) (crypt.Decryptor, error) {
+	if p.TokenCommand != "" {
+		return noop.NewNoopDecryptor(), nil
+	}
+
+	return aesgcm.NewAESGCMDecryptor(c.passphrase, base64.NewBase64Decryptor()), nil
 }
 
 func (c *cliFactory) CreateFileProvider() (fileprovider.FileProvider, error) {
@@ -52,10 +176,16 @@ func (c *cliFactory) CreateFileProvider() (fileprovider.FileProvider, error) {
 
 	fnp := filenamesprovider.NewOSFileNamesProvider(errMapper)
 	fcp := filecontentprovider.NewOSFileContentProvider(errMapper)
+	classifier := contentclassifier.NewSniffContentClassifier()
+
+	fp := fileprovider.NewFileProvider(fnp, fcp, classifier)
 
-	return fileprovider.NewFileProvider(fnp, fcp), nil
+	return archiveprovider.NewArchiveFileProvider(fp, classifier, os.Stdin, archiveprovider.Limits{
+		MaxEntries:   maxArchiveEntries,
+		MaxTotalSize: maxArchiveTotalSize,
+	}), nil
 }
 
 func (c *cliFactory) CreateGitChangesProvider() (git.ChangesProvider, error) {
-	return gitadapter.NewGitChangesProvider(), nil
+	return gitadapter.NewGitChangesProvider(c.workDir), nil
 }
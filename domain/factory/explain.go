@@ -0,0 +1,105 @@
+package factory
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+	"golang.org/x/sync/errgroup"
+)
+
+// ExplainRun is an explanation of files and directories, shared by the command line, the servers and the client.
+type ExplainRun struct {
+	// Paths are files, directories, archives or stdin.
+	Paths  []string
+	Names  fileprovider.NamesConfig
+	Policy fileprovider.SkipPolicy
+	// Skipped receives files skipped by Policy. Can be nil.
+	Skipped fileprovider.SkipReporter
+	// StdinName is a path displayed for content read from stdin.
+	StdinName string
+	Options   entity.ExplainOptions
+}
+
+// ExplainPaths reads the files of the paths concurrently and explains them with the agent of the app.
+// It returns the number of files sent to the agent, skipped files are not counted.
+func ExplainPaths(ctx context.Context, app AppBuilder, run ExplainRun) (entities.Completion, int, error) {
+	fp, err := app.GetFileProvider()
+	if err != nil {
+		return entities.Completion{}, 0, err
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	fileNames, err := fp.GetAllFileNames(ctx, run.Names, run.Paths)
+	if err != nil {
+		return entities.Completion{}, 0, err
+	}
+
+	agent, err := app.GetAIAgent()
+	if err != nil {
+		return entities.Completion{}, 0, err
+	}
+
+	workers := runtime.GOMAXPROCS(0) - 1 // keep 1 free thread for files consumer
+	workers = max(workers, 1)            // in case GOMAXPROCS=1
+
+	opts := run.Options
+	// a persistent sync removes files missing from the input only under the paths
+	opts.Roots = run.Paths
+
+	filesCh, doneCh := fp.ReadFiles(ctx, fileprovider.Config{
+		Workers:    workers,
+		BufferSize: workers * 2,
+		Policy:     run.Policy,
+		Skipped:    run.Skipped,
+		StdinName:  run.StdinName,
+		Progress:   run.Options.Progress,
+	}, fileNames)
+
+	eg.Go(func() error {
+		if err := <-doneCh; err != nil {
+			return err
+		}
+
+		progress.OrDiscard(run.Options.Progress).Finish(progress.StageRead)
+
+		return nil
+	})
+
+	countedCh := make(chan entities.File)
+	processed := 0
+
+	eg.Go(func() error {
+		defer close(countedCh)
+
+		for f := range filesCh {
+			select {
+			case <-ctx.Done():
+				return context.Canceled
+			case countedCh <- f:
+				processed++
+			}
+		}
+
+		return nil
+	})
+
+	var output entities.Completion
+
+	eg.Go(func() error {
+		var err error
+		output, err = agent.ExplainFiles(ctx, countedCh, opts)
+
+		return err
+	})
+
+	if err = eg.Wait(); err != nil {
+		return entities.Completion{}, processed, err
+	}
+
+	return output, processed, nil
+}
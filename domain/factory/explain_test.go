@@ -0,0 +1,67 @@
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	aiagent_mock "github.com/yaroslav-koval/hange/mocks/aiagent"
+	appbuilder_mock "github.com/yaroslav-koval/hange/mocks/appbuilder"
+	fileprovider_mock "github.com/yaroslav-koval/hange/mocks/fileprovider"
+	reporter_mock "github.com/yaroslav-koval/hange/mocks/reporter"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+func TestExplainPaths(t *testing.T) {
+	reporter := reporter_mock.NewMockReporter(t)
+	reporter.EXPECT().Finish(progress.StageRead).Once()
+
+	run := ExplainRun{
+		Paths:     []string{"cmd", "-"},
+		Names:     fileprovider.NamesConfig{Include: []string{"*.go"}},
+		Policy:    fileprovider.SkipPolicy{MaxFileSize: fileprovider.DefaultMaxFileSize},
+		StdinName: "snippet.go",
+		Options:   entity.ExplainOptions{Persistent: true, Progress: reporter},
+	}
+	names := []string{"cmd/main.go", "-"}
+
+	files := make(chan entities.File, 2)
+	files <- entities.File{Path: "cmd/main.go"}
+	files <- entities.File{Path: "snippet.go"}
+	close(files)
+
+	done := make(chan error, 1)
+	done <- nil
+
+	fp := fileprovider_mock.NewMockFileProvider(t)
+	fp.EXPECT().GetAllFileNames(mock.Anything, run.Names, run.Paths).Return(names, nil)
+	fp.EXPECT().ReadFiles(mock.Anything, mock.MatchedBy(func(cfg fileprovider.Config) bool {
+		return cfg.Workers > 0 && cfg.Policy == run.Policy && cfg.StdinName == run.StdinName && cfg.Progress == reporter
+	}), names).Return(files, done)
+
+	ag := aiagent_mock.NewMockAIAgent(t)
+	opts := run.Options
+	opts.Roots = run.Paths
+
+	ag.EXPECT().ExplainFiles(mock.Anything, mock.Anything, opts).RunAndReturn(
+		func(_ context.Context, files <-chan entities.File, _ entity.ExplainOptions) (entities.Completion, error) {
+			for range files {
+			}
+
+			return entities.Completion{Text: "explanation"}, nil
+		})
+
+	app := appbuilder_mock.NewMockAppBuilder(t)
+	app.EXPECT().GetFileProvider().Return(fp, nil)
+	app.EXPECT().GetAIAgent().Return(ag, nil)
+
+	c, processed, err := ExplainPaths(context.Background(), app, run)
+	require.NoError(t, err)
+	assert.Equal(t, "explanation", c.Text)
+	assert.Equal(t, 2, processed)
+}
@@ -0,0 +1,252 @@
+package archiveprovider
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+)
+
+type emitFunc func(name string, data []byte) error
+
+// skipFunc reports an entry skipped before it is read.
+type skipFunc func(entities.SkippedFile)
+
+// expander reads entries of a single archive and keeps track of bomb limits.
+type expander struct {
+	archivePath string
+	limits      Limits
+	policy      fileprovider.SkipPolicy
+	skip        skipFunc
+	// inflatesSkipped is set for a compressed stream, it is decompressed to skip the content of an entry
+	inflatesSkipped bool
+	entries         int
+	totalSize       int64
+}
+
+func newExpander(archivePath string, limits Limits, policy fileprovider.SkipPolicy, skip skipFunc) *expander {
+	return &expander{
+		archivePath: archivePath,
+		limits:      limits,
+		policy:      policy,
+		skip:        skip,
+	}
+}
+
+func (e *expander) expandTar(ctx context.Context, emit emitFunc, gzipped bool) error {
+	f, err := os.Open(e.archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+
+	e.inflatesSkipped = gzipped
+
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = e.count(); err != nil {
+			return err
+		}
+
+		if h.Typeflag != tar.TypeReg {
+			// directories, links and devices have no content to explain, but the reader skips what they declare
+			if err = e.skipContent(h.Size); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		ok, err := e.admit(h.Name, h.Size)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			// the reader skips the unread content of the entry on Next
+			continue
+		}
+
+		if err = e.expandEntry(h.Name, tr, emit); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *expander) expandZip(ctx context.Context, emit emitFunc) error {
+	zr, err := zip.OpenReader(e.archivePath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	// the central directory lists every entry, so a bomb fails before anything is decompressed
+	if e.limits.MaxEntries > 0 && len(zr.File) > e.limits.MaxEntries {
+		return fmt.Errorf("%w: limit is %d", ErrTooManyEntries, e.limits.MaxEntries)
+	}
+
+	for _, zf := range zr.File {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err = e.count(); err != nil {
+			return err
+		}
+
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+
+		if err = e.expandZipEntry(zf, emit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *expander) expandZipEntry(zf *zip.File, emit emitFunc) error {
+	// declared size can't be trusted, but it lets to fail before decompression
+	ok, err := e.admit(zf.Name, int64(min(zf.UncompressedSize64, 1<<62)))
+	if err != nil || !ok {
+		return err
+	}
+
+	rc, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return e.expandEntry(zf.Name, rc, emit)
+}
+
+// count counts an entry of any type towards the entries limit.
+func (e *expander) count() error {
+	e.entries++
+	if e.limits.MaxEntries > 0 && e.entries > e.limits.MaxEntries {
+		return fmt.Errorf("%w: limit is %d", ErrTooManyEntries, e.limits.MaxEntries)
+	}
+
+	return nil
+}
+
+// admit checks the declared size of an entry before it is read. An entry larger than the file size limit is skipped
+// without reading it, so it doesn't count in the total size unless a compressed stream inflates it to skip.
+// Returns false if the entry is skipped.
+func (e *expander) admit(name string, declaredSize int64) (bool, error) {
+	if skipped := e.policy.TooLarge(entryPath(e.archivePath, name), declaredSize); skipped != nil {
+		if err := e.skipContent(declaredSize); err != nil {
+			return false, err
+		}
+
+		e.skip(*skipped)
+
+		return false, nil
+	}
+
+	if e.limits.MaxTotalSize > 0 && declaredSize > e.limits.MaxTotalSize-e.totalSize {
+		return false, fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, e.limits.MaxTotalSize)
+	}
+
+	return true, nil
+}
+
+// skipContent counts the declared size of unread content in the total size if a compressed stream inflates it.
+func (e *expander) skipContent(declaredSize int64) error {
+	if !e.inflatesSkipped {
+		return nil
+	}
+
+	if e.limits.MaxTotalSize > 0 && declaredSize > e.limits.MaxTotalSize-e.totalSize {
+		return fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, e.limits.MaxTotalSize)
+	}
+
+	e.totalSize += declaredSize
+
+	return nil
+}
+
+// expandEntry reads a single admitted archive file. The actual size is checked during the read.
+func (e *expander) expandEntry(name string, r io.Reader, emit emitFunc) error {
+	remaining := int64(-1)
+	if e.limits.MaxTotalSize > 0 {
+		remaining = e.limits.MaxTotalSize - e.totalSize
+	}
+
+	data, err := readLimited(r, remaining)
+	if err != nil {
+		if errors.Is(err, ErrTooLarge) {
+			return fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, e.limits.MaxTotalSize)
+		}
+
+		return err
+	}
+
+	e.totalSize += int64(len(data))
+
+	return emit(entryPath(e.archivePath, name), data)
+}
+
+// readLimited reads everything from r. Negative limit means no limit.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit < 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, ErrTooLarge
+	}
+
+	return data, nil
+}
+
+// entryPath builds a displayed path of an archive entry, e.g. "release.tar.gz/src/main.go".
+// Entry names are cleaned, so names like "../../etc/passwd" stay inside the archive path.
+func entryPath(archivePath, name string) string {
+	cleaned := strings.TrimPrefix(path.Clean("/"+name), "/")
+
+	return filepath.Join(archivePath, filepath.FromSlash(cleaned))
+}
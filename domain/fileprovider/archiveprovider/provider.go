@@ -0,0 +1,274 @@
+package archiveprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+	"golang.org/x/sync/errgroup"
+)
+
+// StdinPath is an input path that stands for a single pseudo-file read from stdin.
+const StdinPath = "-"
+
+// DefaultStdinName is displayed for stdin content if Config.StdinName is empty.
+const DefaultStdinName = "stdin"
+
+var (
+	ErrTooManyEntries = errors.New("archive has too many entries")
+	ErrTooLarge       = errors.New("archive content is too large")
+)
+
+// Limits protect from archive bombs. Every limit applies to a single archive or to stdin.
+type Limits struct {
+	// MaxEntries is the maximum number of entries in an archive, directories and links included.
+	MaxEntries int
+	// MaxTotalSize is the maximum sum of uncompressed file sizes in bytes. Skipped entries of a compressed tar count
+	// too, because they are decompressed to be skipped.
+	MaxTotalSize int64
+}
+
+// NewArchiveFileProvider creates a FileProvider that reads stdin and expands archives in memory.
+// All the other paths are handled by next.
+func NewArchiveFileProvider(
+	next fileprovider.FileProvider,
+	classifier fileprovider.ContentClassifier,
+	stdin io.Reader,
+	limits Limits,
+) fileprovider.FileProvider {
+	return &archiveFileProvider{
+		next:       next,
+		classifier: classifier,
+		stdin:      stdin,
+		limits:     limits,
+		mutex:      &sync.Mutex{},
+		archives:   make(map[string]struct{}),
+	}
+}
+
+type archiveFileProvider struct {
+	next       fileprovider.FileProvider
+	classifier fileprovider.ContentClassifier
+	stdin      io.Reader
+	limits     Limits
+
+	mutex *sync.Mutex
+	// archives keeps paths of archives listed explicitly. Archives found inside directories are regular files.
+	archives map[string]struct{}
+}
+
+// GetAllFileNames keeps stdin and archive paths as they are, they are expanded by ReadFiles.
+func (a *archiveFileProvider) GetAllFileNames(
+	ctx context.Context, cfg fileprovider.NamesConfig, paths []string) ([]string, error) {
+	var fileNames []string
+
+	for _, p := range paths {
+		if p == StdinPath {
+			fileNames = append(fileNames, p)
+			continue
+		}
+
+		if archiveKind(p) != kindNone {
+			a.mutex.Lock()
+			a.archives[p] = struct{}{}
+			a.mutex.Unlock()
+
+			fileNames = append(fileNames, p)
+
+			continue
+		}
+
+		names, err := a.next.GetAllFileNames(ctx, cfg, []string{p})
+		if err != nil {
+			return nil, err
+		}
+
+		fileNames = append(fileNames, names...)
+	}
+
+	return fileNames, nil
+}
+
+func (a *archiveFileProvider) ReadFiles(
+	ctx context.Context, cfg fileprovider.Config, fileNames []string) (<-chan entities.File, <-chan error) {
+	var osNames, memNames []string
+
+	for _, n := range fileNames {
+		if a.isInMemory(n) {
+			memNames = append(memNames, n)
+		} else {
+			osNames = append(osNames, n)
+		}
+	}
+
+	filesCh := make(chan entities.File, cfg.BufferSize)
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	if len(osNames) > 0 {
+		nextFilesCh, nextDoneCh := a.next.ReadFiles(ctx, cfg, osNames)
+
+		eg.Go(func() error {
+			for f := range nextFilesCh {
+				if err := send(ctx, filesCh, f); err != nil {
+					return err
+				}
+			}
+
+			return <-nextDoneCh
+		})
+	}
+
+	eg.Go(func() error {
+		for _, n := range memNames {
+			if err := a.readInMemory(ctx, cfg, n, filesCh); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	doneCh := make(chan error, 1)
+
+	go func() {
+		doneCh <- eg.Wait()
+		close(doneCh)
+		close(filesCh)
+	}()
+
+	return filesCh, doneCh
+}
+
+func (a *archiveFileProvider) readInMemory(
+	ctx context.Context, cfg fileprovider.Config, path string, filesCh chan<- entities.File) error {
+	emit := func(name string, data []byte) error {
+		return a.emit(ctx, cfg, name, data, filesCh)
+	}
+
+	if path == StdinPath {
+		limit := a.limits.MaxTotalSize
+		if limit <= 0 {
+			limit = -1
+		}
+
+		data, err := readLimited(a.stdin, limit)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+
+		name := cfg.StdinName
+		if name == "" {
+			name = DefaultStdinName
+		}
+
+		return emit(name, data)
+	}
+
+	e := newExpander(path, a.limits, cfg.Policy, func(skipped entities.SkippedFile) {
+		a.reportSkipped(cfg, skipped)
+	})
+
+	var err error
+
+	switch archiveKind(path) {
+	case kindZip:
+		err = e.expandZip(ctx, emit)
+	case kindTarGz:
+		err = e.expandTar(ctx, emit, true)
+	case kindTar:
+		err = e.expandTar(ctx, emit, false)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to expand archive %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (a *archiveFileProvider) emit(
+	ctx context.Context, cfg fileprovider.Config, name string, data []byte, filesCh chan<- entities.File) error {
+	// the number of entries is unknown until an archive is expanded
+	reporter := progress.OrDiscard(cfg.Progress)
+	reporter.Expect(progress.StageRead, 1)
+	reporter.Done(progress.StageRead, 1, int64(len(data)))
+
+	contentType, skipped := a.classifier.Classify(name, data, cfg.Policy)
+	if skipped != nil {
+		if cfg.Skipped != nil {
+			cfg.Skipped.ReportSkipped(*skipped)
+		}
+
+		return nil
+	}
+
+	return send(ctx, filesCh, entities.File{
+		Path:        name,
+		Data:        data,
+		ContentType: contentType,
+	})
+}
+
+// reportSkipped reports an entry skipped before it is read, it is read with no bytes in progress
+func (a *archiveFileProvider) reportSkipped(cfg fileprovider.Config, skipped entities.SkippedFile) {
+	reporter := progress.OrDiscard(cfg.Progress)
+	reporter.Expect(progress.StageRead, 1)
+	reporter.Done(progress.StageRead, 1, 0)
+
+	if cfg.Skipped != nil {
+		cfg.Skipped.ReportSkipped(skipped)
+	}
+}
+
+func send(ctx context.Context, filesCh chan<- entities.File, f entities.File) error {
+	select {
+	case <-ctx.Done():
+		return context.Canceled
+	case filesCh <- f:
+		return nil
+	}
+}
+
+func (a *archiveFileProvider) isInMemory(path string) bool {
+	if path == StdinPath {
+		return true
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	_, ok := a.archives[path]
+
+	return ok
+}
+
+type kind int
+
+const (
+	kindNone kind = iota
+	kindTar
+	kindTarGz
+	kindZip
+)
+
+func archiveKind(path string) kind {
+	lower := strings.ToLower(path)
+
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return kindZip
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return kindTarGz
+	case strings.HasSuffix(lower, ".tar"):
+		return kindTar
+	default:
+		return kindNone
+	}
+}
@@ -0,0 +1,392 @@
+package archiveprovider
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	contentclassifier_mock "github.com/yaroslav-koval/hange/mocks/contentclassifier"
+	fileprovider_mock "github.com/yaroslav-koval/hange/mocks/fileprovider"
+)
+
+var testLimits = Limits{MaxEntries: 10, MaxTotalSize: 1024}
+
+func TestArchiveFileProvider_GetAllFileNames(t *testing.T) {
+	t.Parallel()
+
+	next := fileprovider_mock.NewMockFileProvider(t)
+	next.EXPECT().GetAllFileNames(mock.Anything, mock.Anything, []string{"dir"}).
+		Return([]string{"dir/a.go", "dir/nested.zip"}, nil)
+
+	p := NewArchiveFileProvider(next, textClassifier(t), strings.NewReader(""), testLimits).(*archiveFileProvider)
+
+	got, err := p.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{"-", "dir", "release.tar.gz"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-", "dir/a.go", "dir/nested.zip", "release.tar.gz"}, got)
+
+	assert.True(t, p.isInMemory("-"))
+	assert.True(t, p.isInMemory("release.tar.gz"))
+	assert.False(t, p.isInMemory("dir/nested.zip"), "archives found inside directories must not be expanded")
+}
+
+func TestArchiveFileProvider_ReadFilesStdin(t *testing.T) {
+	t.Parallel()
+
+	p := NewArchiveFileProvider(
+		fileprovider_mock.NewMockFileProvider(t), textClassifier(t), strings.NewReader("snippet"), testLimits)
+
+	cfg := fileprovider.Config{BufferSize: 1, StdinName: "clipboard.go"}
+
+	got, err := readAll(t, p, cfg, []string{StdinPath})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"clipboard.go": "snippet"}, got)
+}
+
+func TestArchiveFileProvider_ReadFilesArchives(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"src/main.go":      "package main",
+		"../../escape.txt": "outside",
+	}
+
+	tests := []struct {
+		name  string
+		file  string
+		write func(t *testing.T, path string, files map[string]string)
+	}{
+		{name: "tar", file: "release.tar", write: writeTar(false)},
+		{name: "tar.gz", file: "release.tar.gz", write: writeTar(true)},
+		{name: "tgz", file: "release.tgz", write: writeTar(true)},
+		{name: "zip", file: "release.zip", write: writeZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			archivePath := filepath.Join(t.TempDir(), tt.file)
+			tt.write(t, archivePath, files)
+
+			p := NewArchiveFileProvider(
+				fileprovider_mock.NewMockFileProvider(t), textClassifier(t), strings.NewReader(""), testLimits)
+
+			_, err := p.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{archivePath})
+			require.NoError(t, err)
+
+			got, err := readAll(t, p, fileprovider.Config{BufferSize: 1}, []string{archivePath})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{
+				filepath.Join(archivePath, "src", "main.go"): "package main",
+				filepath.Join(archivePath, "escape.txt"):     "outside",
+			}, got)
+		})
+	}
+}
+
+func TestArchiveFileProvider_ReadFilesLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		limits  Limits
+		files   map[string]string
+		wantErr error
+	}{
+		{
+			name:    "too many entries",
+			limits:  Limits{MaxEntries: 2},
+			files:   map[string]string{"a": "a", "b": "b", "c": "c"},
+			wantErr: ErrTooManyEntries,
+		},
+		{
+			name:    "too large in total",
+			limits:  Limits{MaxTotalSize: 10},
+			files:   map[string]string{"a": strings.Repeat("a", 6), "b": strings.Repeat("b", 6)},
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "single entry too large",
+			limits:  Limits{MaxTotalSize: 10},
+			files:   map[string]string{"a": strings.Repeat("a", 11)},
+			wantErr: ErrTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			archives := []struct {
+				file  string
+				write func(t *testing.T, path string, files map[string]string)
+			}{
+				{file: "bomb.tar.gz", write: writeTar(true)},
+				{file: "bomb.zip", write: writeZip},
+			}
+
+			for _, a := range archives {
+				archivePath := filepath.Join(t.TempDir(), a.file)
+				a.write(t, archivePath, tt.files)
+
+				p := NewArchiveFileProvider(
+					fileprovider_mock.NewMockFileProvider(t), textClassifier(t), strings.NewReader(""), tt.limits)
+
+				_, err := p.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{archivePath})
+				require.NoError(t, err)
+
+				_, err = readAll(t, p, fileprovider.Config{BufferSize: 10}, []string{archivePath})
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, archivePath)
+			}
+		})
+	}
+}
+
+func TestArchiveFileProvider_ReadFilesSkipsLargeEntries(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{"dump.sql": strings.Repeat("x", 20), "a.go": "package a"}
+
+	for _, a := range []struct {
+		file  string
+		write func(t *testing.T, path string, files map[string]string)
+	}{
+		{file: "release.tar", write: writeTar(false)},
+		{file: "release.zip", write: writeZip},
+	} {
+		t.Run(a.file, func(t *testing.T) {
+			t.Parallel()
+
+			archivePath := filepath.Join(t.TempDir(), a.file)
+			a.write(t, archivePath, files)
+
+			// the large entry is skipped by its header, it is neither classified nor counted in the total size
+			classifier := contentclassifier_mock.NewMockContentClassifier(t)
+			classifier.EXPECT().Classify(filepath.Join(archivePath, "a.go"), []byte("package a"), mock.Anything).
+				Return("text/plain", nil)
+
+			p := NewArchiveFileProvider(fileprovider_mock.NewMockFileProvider(t), classifier, strings.NewReader(""),
+				Limits{MaxTotalSize: 25})
+
+			_, err := p.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{archivePath})
+			require.NoError(t, err)
+
+			collector := fileprovider.NewSkipCollector()
+
+			got, err := readAll(t, p, fileprovider.Config{
+				BufferSize: 2,
+				Policy:     fileprovider.SkipPolicy{MaxFileSize: 10},
+				Skipped:    collector,
+			}, []string{archivePath})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{filepath.Join(archivePath, "a.go"): "package a"}, got)
+			assert.Equal(t, []entities.SkippedFile{{
+				Path:    filepath.Join(archivePath, "dump.sql"),
+				Reason:  entities.SkipReasonTooLarge,
+				Details: "20 bytes, limit is 10 bytes",
+			}}, collector.Skipped())
+		})
+	}
+}
+
+func TestArchiveFileProvider_ReadFilesCountsSkippedCompressedEntries(t *testing.T) {
+	t.Parallel()
+
+	archivePath := filepath.Join(t.TempDir(), "release.tar.gz")
+	writeTar(true)(t, archivePath, map[string]string{"dump.sql": strings.Repeat("x", 20), "a.go": "package a"})
+
+	// the skipped entry is decompressed to reach the next one, so it counts in the total size
+	p := NewArchiveFileProvider(fileprovider_mock.NewMockFileProvider(t), textClassifier(t), strings.NewReader(""),
+		Limits{MaxTotalSize: 25})
+
+	_, err := p.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{archivePath})
+	require.NoError(t, err)
+
+	_, err = readAll(t, p, fileprovider.Config{
+		BufferSize: 2,
+		Policy:     fileprovider.SkipPolicy{MaxFileSize: 10},
+	}, []string{archivePath})
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestArchiveFileProvider_ReadFilesCountsEveryTarHeader(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	for _, name := range []string{"a/", "b/", "c/"} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeDir, Mode: 0o755}))
+	}
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "d", Typeflag: tar.TypeSymlink, Linkname: "a"}))
+	require.NoError(t, tw.Close())
+
+	archivePath := filepath.Join(t.TempDir(), "dirs.tar")
+	require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0o644))
+
+	p := NewArchiveFileProvider(fileprovider_mock.NewMockFileProvider(t),
+		contentclassifier_mock.NewMockContentClassifier(t), strings.NewReader(""), Limits{MaxEntries: 3})
+
+	_, err := p.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{archivePath})
+	require.NoError(t, err)
+
+	_, err = readAll(t, p, fileprovider.Config{}, []string{archivePath})
+	require.ErrorIs(t, err, ErrTooManyEntries)
+}
+
+func TestArchiveFileProvider_ReadFilesChecksZipEntriesFirst(t *testing.T) {
+	t.Parallel()
+
+	archivePath := filepath.Join(t.TempDir(), "bomb.zip")
+	writeZip(t, archivePath, map[string]string{"a": "a", "b": "b", "c": "c"})
+
+	// nothing is decompressed and classified, the entries are counted by the central directory
+	p := NewArchiveFileProvider(fileprovider_mock.NewMockFileProvider(t),
+		contentclassifier_mock.NewMockContentClassifier(t), strings.NewReader(""), Limits{MaxEntries: 3})
+
+	_, err := p.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{archivePath})
+	require.NoError(t, err)
+
+	got, err := readAll(t, p, fileprovider.Config{BufferSize: 10}, []string{archivePath})
+	require.ErrorIs(t, err, ErrTooManyEntries)
+	assert.Empty(t, got)
+}
+
+func TestArchiveFileProvider_ReadFilesStdinTooLarge(t *testing.T) {
+	t.Parallel()
+
+	p := NewArchiveFileProvider(fileprovider_mock.NewMockFileProvider(t),
+		contentclassifier_mock.NewMockContentClassifier(t), strings.NewReader("0123456789"), Limits{MaxTotalSize: 5})
+
+	_, err := readAll(t, p, fileprovider.Config{}, []string{StdinPath})
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestArchiveFileProvider_ReadFilesMergesNext(t *testing.T) {
+	t.Parallel()
+
+	nextFilesCh := make(chan entities.File, 1)
+	nextFilesCh <- entities.File{Path: "a.go", Data: []byte("a")}
+	close(nextFilesCh)
+
+	nextDoneCh := make(chan error, 1)
+	nextDoneCh <- nil
+	close(nextDoneCh)
+
+	cfg := fileprovider.Config{BufferSize: 1}
+
+	next := fileprovider_mock.NewMockFileProvider(t)
+	next.EXPECT().ReadFiles(mock.Anything, cfg, []string{"a.go"}).
+		Return((<-chan entities.File)(nextFilesCh), (<-chan error)(nextDoneCh))
+
+	p := NewArchiveFileProvider(next, textClassifier(t), strings.NewReader("b"), testLimits)
+
+	got, err := readAll(t, p, cfg, []string{"a.go", StdinPath})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.go": "a", DefaultStdinName: "b"}, got)
+}
+
+func TestArchiveFileProvider_ReadFilesReportsSkipped(t *testing.T) {
+	t.Parallel()
+
+	skipped := entities.SkippedFile{Path: DefaultStdinName, Reason: entities.SkipReasonBinary}
+
+	classifier := contentclassifier_mock.NewMockContentClassifier(t)
+	classifier.EXPECT().Classify(DefaultStdinName, []byte("\x00"), mock.Anything).Return("", &skipped)
+
+	collector := fileprovider.NewSkipCollector()
+
+	p := NewArchiveFileProvider(fileprovider_mock.NewMockFileProvider(t), classifier, strings.NewReader("\x00"), testLimits)
+
+	got, err := readAll(t, p, fileprovider.Config{Skipped: collector}, []string{StdinPath})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	assert.Equal(t, []entities.SkippedFile{skipped}, collector.Skipped())
+}
+
+func readAll(t *testing.T, p fileprovider.FileProvider, cfg fileprovider.Config, names []string) (map[string]string, error) {
+	t.Helper()
+
+	filesCh, doneCh := p.ReadFiles(context.Background(), cfg, names)
+
+	got := make(map[string]string)
+	for f := range filesCh {
+		got[f.Path] = string(f.Data)
+	}
+
+	return got, <-doneCh
+}
+
+func textClassifier(t *testing.T) *contentclassifier_mock.MockContentClassifier {
+	t.Helper()
+
+	classifier := contentclassifier_mock.NewMockContentClassifier(t)
+	classifier.EXPECT().Classify(mock.Anything, mock.Anything, mock.Anything).Return("text/plain", nil).Maybe()
+
+	return classifier
+}
+
+func writeTar(gzipped bool) func(t *testing.T, path string, files map[string]string) {
+	return func(t *testing.T, path string, files map[string]string) {
+		t.Helper()
+
+		var buf bytes.Buffer
+
+		tw := tar.NewWriter(&buf)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "src/", Typeflag: tar.TypeDir, Mode: 0o755}))
+
+		for name, content := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+
+		data := buf.Bytes()
+
+		if gzipped {
+			var gzBuf bytes.Buffer
+
+			gz := gzip.NewWriter(&gzBuf)
+			_, err := gz.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, gz.Close())
+
+			data = gzBuf.Bytes()
+		}
+
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+	}
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	_, err := zw.Create("src/")
+	require.NoError(t, err)
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+}
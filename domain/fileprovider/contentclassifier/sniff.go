@@ -0,0 +1,130 @@
+package contentclassifier
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+)
+
+func NewSniffContentClassifier() fileprovider.ContentClassifier {
+	return &sniffContentClassifier{}
+}
+
+type sniffContentClassifier struct{}
+
+// minifiedLineLen is the average line length that no human-written js/css file has.
+const minifiedLineLen = 300
+
+// generatedMarker matches the Go convention and "@generated"/"<auto-generated>" comments used by other generators.
+var generatedMarker = regexp.MustCompile(
+	`(?m)^// Code generated .* DO NOT EDIT\.$|^\s*(?://|#|/?\*+|--)\s*(?:@generated|<auto-generated)`)
+
+var lockFiles = map[string]struct{}{
+	"package-lock.json":   {},
+	"npm-shrinkwrap.json": {},
+	"yarn.lock":           {},
+	"pnpm-lock.yaml":      {},
+	"go.sum":              {},
+	"Cargo.lock":          {},
+	"poetry.lock":         {},
+	"Pipfile.lock":        {},
+	"Gemfile.lock":        {},
+	"composer.lock":       {},
+	"mix.lock":            {},
+	"flake.lock":          {},
+}
+
+var minifiableExts = map[string]struct{}{
+	".js":  {},
+	".mjs": {},
+	".cjs": {},
+	".css": {},
+}
+
+func (s *sniffContentClassifier) Sniff(path string, head []byte) *entities.SkippedFile {
+	_, skipped := sniff(path, head[:min(len(head), fileprovider.SniffLen)])
+
+	return skipped
+}
+
+func (s *sniffContentClassifier) Classify(
+	path string, data []byte, policy fileprovider.SkipPolicy) (string, *entities.SkippedFile) {
+	if skipped := policy.TooLarge(path, int64(len(data))); skipped != nil {
+		return "", skipped
+	}
+
+	sample := data[:min(len(data), fileprovider.SniffLen)]
+
+	contentType, skipped := sniff(path, sample)
+	if skipped != nil {
+		return "", skipped
+	}
+
+	if !policy.AllowGenerated {
+		if details, ok := generated(path, sample, data); ok {
+			return "", &entities.SkippedFile{
+				Path:    path,
+				Reason:  entities.SkipReasonGenerated,
+				Details: details,
+			}
+		}
+	}
+
+	return contentType, nil
+}
+
+// sniff detects a content type of the sample and skips binary content
+func sniff(path string, sample []byte) (string, *entities.SkippedFile) {
+	contentType := mediaType(http.DetectContentType(sample))
+
+	if bytes.IndexByte(sample, 0) != -1 || !strings.HasPrefix(contentType, "text/") {
+		return "", &entities.SkippedFile{
+			Path:    path,
+			Reason:  entities.SkipReasonBinary,
+			Details: contentType,
+		}
+	}
+
+	return contentType, nil
+}
+
+func generated(path string, sample, data []byte) (string, bool) {
+	name := filepath.Base(path)
+
+	if _, ok := lockFiles[name]; ok {
+		return "lockfile", true
+	}
+
+	if strings.Contains(name, ".min.") {
+		return "minified", true
+	}
+
+	if generatedMarker.Match(sample) {
+		return "generated code marker", true
+	}
+
+	if _, ok := minifiableExts[filepath.Ext(name)]; ok {
+		lines := bytes.Count(data, []byte{'\n'}) + 1
+		if len(data)/lines > minifiedLineLen {
+			return "minified", true
+		}
+	}
+
+	return "", false
+}
+
+// mediaType strips parameters, e.g. "text/plain; charset=utf-8" becomes "text/plain".
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+
+	return mt
+}
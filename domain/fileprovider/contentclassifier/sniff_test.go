@@ -0,0 +1,141 @@
+package contentclassifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+)
+
+func TestSniffContentClassifier_Classify(t *testing.T) {
+	t.Parallel()
+
+	minifiedJS := "var a=1;" + strings.Repeat("function f(){return 1};", 100)
+
+	tests := []struct {
+		name            string
+		path            string
+		data            []byte
+		policy          fileprovider.SkipPolicy
+		wantContentType string
+		wantReason      entities.SkipReason
+	}{
+		{
+			name:            "plain source file",
+			path:            "main.go",
+			data:            []byte("package main\n\nfunc main() {}\n"),
+			wantContentType: "text/plain",
+		},
+		{
+			name:            "empty file",
+			path:            "empty.txt",
+			data:            []byte{},
+			wantContentType: "text/plain",
+		},
+		{
+			name:       "nul byte",
+			path:       "data.bin",
+			data:       []byte("abc\x00def"),
+			wantReason: entities.SkipReasonBinary,
+		},
+		{
+			name:       "png image",
+			path:       "image.png",
+			data:       []byte("\x89PNG\r\n\x1a\n0000"),
+			wantReason: entities.SkipReasonBinary,
+		},
+		{
+			name:       "too large",
+			path:       "big.txt",
+			data:       []byte(strings.Repeat("a", 11)),
+			policy:     fileprovider.SkipPolicy{MaxFileSize: 10},
+			wantReason: entities.SkipReasonTooLarge,
+		},
+		{
+			name:            "size limit disabled",
+			path:            "big.txt",
+			data:            []byte(strings.Repeat("a", 11)),
+			wantContentType: "text/plain",
+		},
+		{
+			name:       "go generated code",
+			path:       "mock.go",
+			data:       []byte("// Code generated by mockery; DO NOT EDIT.\n\npackage mock\n"),
+			wantReason: entities.SkipReasonGenerated,
+		},
+		{
+			name:       "generated comment marker",
+			path:       "schema.py",
+			data:       []byte("# @generated by protoc\nimport x\n"),
+			wantReason: entities.SkipReasonGenerated,
+		},
+		{
+			name:            "marker mentioned in code is not generated",
+			path:            "sniff.go",
+			data:            []byte("package x\n\nvar marker = \"@generated\"\n"),
+			wantContentType: "text/plain",
+		},
+		{
+			name:       "lockfile",
+			path:       "web/package-lock.json",
+			data:       []byte("{}"),
+			wantReason: entities.SkipReasonGenerated,
+		},
+		{
+			name:       "minified by name",
+			path:       "app.min.js",
+			data:       []byte("var a=1;"),
+			wantReason: entities.SkipReasonGenerated,
+		},
+		{
+			name:       "minified by line length",
+			path:       "bundle.js",
+			data:       []byte(minifiedJS),
+			wantReason: entities.SkipReasonGenerated,
+		},
+		{
+			name:            "generated allowed by policy",
+			path:            "go.sum",
+			data:            []byte("github.com/a/b v1.0.0 h1:abc=\n"),
+			policy:          fileprovider.SkipPolicy{AllowGenerated: true},
+			wantContentType: "text/plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			contentType, skipped := NewSniffContentClassifier().Classify(tt.path, tt.data, tt.policy)
+
+			if tt.wantReason == "" {
+				require.Nil(t, skipped)
+				assert.Equal(t, tt.wantContentType, contentType)
+
+				return
+			}
+
+			require.NotNil(t, skipped)
+			assert.Equal(t, tt.path, skipped.Path)
+			assert.Equal(t, tt.wantReason, skipped.Reason)
+			assert.NotEmpty(t, skipped.Details)
+			assert.Empty(t, contentType)
+		})
+	}
+}
+
+func TestSniffContentClassifier_Sniff(t *testing.T) {
+	t.Parallel()
+
+	c := NewSniffContentClassifier()
+
+	skipped := c.Sniff("image.png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"))
+	require.NotNil(t, skipped)
+	assert.Equal(t, entities.SkipReasonBinary, skipped.Reason)
+
+	// a generated marker in the head doesn't skip a file, it is classified by the whole content
+	assert.Nil(t, c.Sniff("api.pb.go", []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n")))
+}
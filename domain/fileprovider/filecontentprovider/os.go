@@ -2,6 +2,7 @@ package filecontentprovider
 
 import (
 	"context"
+	"io"
 	"os"
 
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
@@ -26,3 +27,27 @@ func (o *osFileContentProvider) GetFileContent(ctx context.Context, filePath str
 
 	return res, nil
 }
+
+func (o *osFileContentProvider) GetFileSize(ctx context.Context, filePath string) (int64, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return 0, o.errMapper.Map(err)
+	}
+
+	return info.Size(), nil
+}
+
+func (o *osFileContentProvider) GetFileHead(ctx context.Context, filePath string, n int) ([]byte, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, o.errMapper.Map(err)
+	}
+	defer f.Close()
+
+	res, err := io.ReadAll(io.LimitReader(f, int64(n)))
+	if err != nil {
+		return nil, o.errMapper.Map(err)
+	}
+
+	return res, nil
+}
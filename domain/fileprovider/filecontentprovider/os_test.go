@@ -49,3 +49,24 @@ func TestOSFileContentProvider_ReadFile(t *testing.T) {
 		assert.ErrorIs(t, err, fileprovider.ErrNotExist)
 	})
 }
+
+func TestOSFileContentProvider_SizeAndHead(t *testing.T) {
+	t.Parallel()
+
+	p := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(p, []byte("hello world"), 0o600))
+
+	provider := NewOSFileContentProvider(fileerrormapper_mock.NewMockFileErrorMapper(t))
+
+	size, err := provider.GetFileSize(context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), size)
+
+	head, err := provider.GetFileHead(context.Background(), p, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), head)
+
+	head, err = provider.GetFileHead(context.Background(), p, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello world"), head)
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package filenamesprovider
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileKey identifies a directory by its resolved path where device and inode numbers are not available.
+type fileKey struct {
+	path string
+}
+
+func newFileKey(path string, _ os.FileInfo) fileKey {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		resolved = path
+	}
+
+	return fileKey{path: resolved}
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package filenamesprovider
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileKey identifies a directory regardless of the path it is reached by.
+type fileKey struct {
+	dev uint64
+	ino uint64
+}
+
+func newFileKey(_ string, info os.FileInfo) fileKey {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}
+	}
+
+	return fileKey{
+		dev: uint64(st.Dev), // Dev type differs between platforms
+		ino: st.Ino,
+	}
+}
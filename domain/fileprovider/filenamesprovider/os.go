@@ -3,11 +3,15 @@ package filenamesprovider
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
 	"github.com/yaroslav-koval/hange/domain/fileprovider/errmapper"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/ignore"
+	"github.com/yaroslav-koval/hange/pkg/logging"
 )
 
 func NewOSFileNamesProvider(errMapper errmapper.FileErrorMapper) fileprovider.FileNamesProvider {
@@ -20,7 +24,8 @@ type osFileNamesProvider struct {
 	errMapper errmapper.FileErrorMapper
 }
 
-func (o *osFileNamesProvider) GetAllFileNames(ctx context.Context, paths []string) ([]string, error) {
+func (o *osFileNamesProvider) GetAllFileNames(
+	ctx context.Context, cfg fileprovider.NamesConfig, paths []string) ([]string, error) {
 	var fileNames []string
 
 	for _, p := range paths {
@@ -33,13 +38,48 @@ func (o *osFileNamesProvider) GetAllFileNames(ctx context.Context, paths []strin
 			return nil, o.createErrFailedPath(err, p)
 		}
 
+		absPath, err := filepath.Abs(p)
+		if err != nil {
+			return nil, o.createErrFailedPath(err, p)
+		}
+
 		if !fileInfo.IsDir() {
-			fileNames = append(fileNames, p)
+			// explicitly listed files bypass ignore files, but still respect include and exclude patterns
+			f, err := newPathFilter(cfg, filepath.Dir(absPath))
+			if err != nil {
+				return nil, err
+			}
+
+			if !f.skipFile(absPath) {
+				fileNames = append(fileNames, p)
+			}
 
 			continue
 		}
 
-		files, err := o.readFilesInDir(ctx, p)
+		f, err := newPathFilter(cfg, absPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if !cfg.NoIgnore {
+			rules, err := ignore.ReadAncestors(absPath)
+			if err != nil {
+				return nil, o.createErrFailedPath(err, p)
+			}
+
+			// the config rules have their own base, so they match the same files whatever the input path is
+			f.ignore = f.ignore.With(cfg.Ignore...).With(rules...)
+		}
+
+		w, err := newWalker(cfg, absPath)
+		if err != nil {
+			return nil, o.createErrFailedPath(err, p)
+		}
+
+		w.visit(absPath, fileInfo)
+
+		files, err := o.readFilesInDir(ctx, w, f, p, absPath)
 		if err != nil {
 			return nil, err
 		}
@@ -50,13 +90,24 @@ func (o *osFileNamesProvider) GetAllFileNames(ctx context.Context, paths []strin
 	return fileNames, nil
 }
 
-// readFilesInDir reads all the files from the dir or recursively reads all children directories and their files
-func (o *osFileNamesProvider) readFilesInDir(ctx context.Context, dirPath string) ([]string, error) {
+// readFilesInDir reads all the files from the dir or recursively reads all children directories and their files.
+// Ignore files found in every directory extend the filter for its children.
+func (o *osFileNamesProvider) readFilesInDir(
+	ctx context.Context, w *walker, f *pathFilter, dirPath, absDirPath string) ([]string, error) {
 	dir, err := os.ReadDir(dirPath)
 	if err != nil {
 		return nil, o.createErrFailedPath(err, dirPath)
 	}
 
+	if !f.noIgnore {
+		rules, err := ignore.ReadDir(absDirPath)
+		if err != nil {
+			return nil, o.createErrFailedPath(err, dirPath)
+		}
+
+		f = f.withIgnoreRules(rules)
+	}
+
 	var fileNames []string
 
 	for _, entry := range dir {
@@ -65,13 +116,44 @@ func (o *osFileNamesProvider) readFilesInDir(ctx context.Context, dirPath string
 		}
 
 		currPath := filepath.Join(dirPath, entry.Name())
+		currAbsPath := filepath.Join(absDirPath, entry.Name())
 
-		if !entry.IsDir() {
-			fileNames = append(fileNames, currPath)
+		info, err := entry.Info()
+		if err != nil {
+			return nil, o.createErrFailedPath(err, currPath)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			var ok bool
+
+			info, ok, err = w.resolveSymlink(currPath)
+			if err != nil {
+				return nil, o.createErrFailedPath(err, currPath)
+			}
+
+			if !ok {
+				continue
+			}
+		}
+
+		if !info.IsDir() {
+			if !f.skipEntry(entry.Name(), currAbsPath, false) {
+				fileNames = append(fileNames, currPath)
+			}
+
+			continue
+		}
+
+		if f.skipEntry(entry.Name(), currAbsPath, true) {
+			continue
+		}
+
+		if !w.visit(currPath, info) {
+			slog.Debug("Skipping already visited directory", slog.String(logging.KeyFile, currPath))
 			continue
 		}
 
-		entries, err := o.readFilesInDir(ctx, currPath)
+		entries, err := o.readFilesInDir(ctx, w, f, currPath, currAbsPath)
 		if err != nil {
 			return nil, err
 		}
@@ -84,3 +166,144 @@ func (o *osFileNamesProvider) readFilesInDir(ctx context.Context, dirPath string
 func (o *osFileNamesProvider) createErrFailedPath(err error, path string) error {
 	return fmt.Errorf("failed to process path %s: %w", path, o.errMapper.Map(err))
 }
+
+// walker keeps the state of a single directory traversal. It applies the symlink policy and breaks cycles.
+type walker struct {
+	followSymlinks bool
+	allowEscape    bool
+	// root is the input directory with all the symlinks resolved
+	root    string
+	visited map[fileKey]struct{}
+}
+
+func newWalker(cfg fileprovider.NamesConfig, absRoot string) (*walker, error) {
+	root, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &walker{
+		followSymlinks: cfg.FollowSymlinks,
+		allowEscape:    cfg.AllowSymlinkEscape,
+		root:           root,
+		visited:        make(map[fileKey]struct{}),
+	}, nil
+}
+
+// visit marks a directory as visited. Returns false if the directory was already visited.
+func (w *walker) visit(path string, info os.FileInfo) bool {
+	key := newFileKey(path, info)
+
+	if _, ok := w.visited[key]; ok {
+		return false
+	}
+
+	w.visited[key] = struct{}{}
+
+	return true
+}
+
+// resolveSymlink applies the symlink policy. Returns info of the symlink target and false if the link must be skipped.
+func (w *walker) resolveSymlink(path string) (os.FileInfo, bool, error) {
+	if !w.followSymlinks {
+		slog.Debug("Skipping symlink, symlinks are not followed", slog.String(logging.KeyFile, path))
+		return nil, false, nil
+	}
+
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		// dangling symlinks and too long chains are not worth failing the whole read
+		slog.Debug("Skipping broken symlink", slog.String(logging.KeyFile, path), logging.Err(err))
+		return nil, false, nil
+	}
+
+	if !w.allowEscape && !isWithin(w.root, target) {
+		slog.Warn("Skipping symlink, it points outside of the root", slog.String(logging.KeyFile, path),
+			slog.String("root", w.root))
+		return nil, false, nil
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return info, true, nil
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// pathFilter decides which entries are skipped during a directory traversal.
+type pathFilter struct {
+	noIgnore bool
+	ignore   *ignore.Matcher
+	include  *ignore.Matcher
+	exclude  *ignore.Matcher
+	// hasInclude is set when at least one include pattern is provided, so files must match one of them
+	hasInclude bool
+}
+
+func newPathFilter(cfg fileprovider.NamesConfig, base string) (*pathFilter, error) {
+	include, err := ignore.ParsePatterns(base, cfg.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+
+	exclude, err := ignore.ParsePatterns(base, cfg.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+
+	return &pathFilter{
+		noIgnore:   cfg.NoIgnore,
+		ignore:     ignore.NewMatcher(),
+		include:    ignore.NewMatcher(include...),
+		exclude:    ignore.NewMatcher(exclude...),
+		hasInclude: len(include) > 0,
+	}, nil
+}
+
+func (f *pathFilter) withIgnoreRules(rules []ignore.Rule) *pathFilter {
+	if len(rules) == 0 {
+		return f
+	}
+
+	cp := *f
+	cp.ignore = f.ignore.With(rules...)
+
+	return &cp
+}
+
+func (f *pathFilter) skipEntry(name, absPath string, isDir bool) bool {
+	if !f.noIgnore {
+		if name == ignore.GitDirName || f.ignore.Ignored(absPath, isDir) {
+			return true
+		}
+	}
+
+	if isDir {
+		return f.exclude.Ignored(absPath, true)
+	}
+
+	return f.skipFile(absPath)
+}
+
+func (f *pathFilter) skipFile(absPath string) bool {
+	if f.exclude.Ignored(absPath, false) {
+		return true
+	}
+
+	if f.hasInclude {
+		// matcher reports "ignored" for a positive match, which means "included" here
+		return !f.include.Ignored(absPath, false)
+	}
+
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/ignore"
 	fileerrormapper_mock "github.com/yaroslav-koval/hange/mocks/fileerrormapper"
 )
 
@@ -87,7 +88,7 @@ func TestOSFileNamesProvider_GetAllFileNames(t *testing.T) {
 			input := tt.layout(root)
 
 			provider := NewOSFileNamesProvider(fileerrormapper_mock.NewMockFileErrorMapper(t))
-			got, err := provider.GetAllFileNames(t.Context(), input)
+			got, err := provider.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, input)
 			require.NoError(t, err)
 
 			for i := range got {
@@ -113,7 +114,7 @@ func TestOSFileNamesProvider_GetAllFileNamesFailures(t *testing.T) {
 		provider := NewOSFileNamesProvider(errMapper)
 		missing := filepath.Join(t.TempDir(), "missing.txt")
 
-		names, err := provider.GetAllFileNames(t.Context(), []string{missing})
+		names, err := provider.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{missing})
 		require.Error(t, err)
 		assert.Nil(t, names)
 		assert.ErrorIs(t, err, fileprovider.ErrNotExist)
@@ -135,7 +136,7 @@ func TestOSFileNamesProvider_GetAllFileNamesFailures(t *testing.T) {
 
 		provider := NewOSFileNamesProvider(errMapper)
 
-		names, err := provider.GetAllFileNames(t.Context(), []string{root})
+		names, err := provider.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{root})
 		require.Error(t, err)
 		assert.Nil(t, names)
 		assert.ErrorContains(t, err, nested)
@@ -151,7 +152,7 @@ func TestOSFileNamesProvider_GetAllFileNamesCancelledContext(t *testing.T) {
 
 	provider := NewOSFileNamesProvider(fileerrormapper_mock.NewMockFileErrorMapper(t))
 
-	names, err := provider.GetAllFileNames(ctx, []string{filepath.Join(t.TempDir(), "unused.txt")})
+	names, err := provider.GetAllFileNames(ctx, fileprovider.NamesConfig{}, []string{filepath.Join(t.TempDir(), "unused.txt")})
 	require.Error(t, err)
 	assert.Nil(t, names)
 	assert.ErrorIs(t, err, context.Canceled)
@@ -200,7 +201,7 @@ func TestOSFileNamesProvider_readFilesInDir(t *testing.T) {
 			dir := tt.layout(root)
 
 			provider := NewOSFileNamesProvider(fileerrormapper_mock.NewMockFileErrorMapper(t)).(*osFileNamesProvider)
-			got, err := provider.readFilesInDir(t.Context(), dir)
+			got, err := provider.readFilesInDir(t.Context(), newTestWalker(t, dir), newTestPathFilter(t), dir, dir)
 			require.NoError(t, err)
 
 			for i := range got {
@@ -229,7 +230,7 @@ func TestOSFileNamesProvider_readFilesInDirFailures(t *testing.T) {
 		errMapper.EXPECT().Map(mock.Anything).Return(fileprovider.ErrPermission)
 
 		provider := NewOSFileNamesProvider(errMapper).(*osFileNamesProvider)
-		_, err := provider.readFilesInDir(t.Context(), root)
+		_, err := provider.readFilesInDir(t.Context(), newTestWalker(t, root), newTestPathFilter(t), root, root)
 		require.Error(t, err)
 		assert.ErrorContains(t, err, grandchild)
 		assert.ErrorIs(t, err, fileprovider.ErrPermission)
@@ -246,12 +247,292 @@ func TestOSFileNamesProvider_readFilesInDirFailures(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
 
-		_, err := provider.readFilesInDir(ctx, dir)
+		_, err := provider.readFilesInDir(ctx, newTestWalker(t, dir), newTestPathFilter(t), dir, dir)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, context.Canceled)
 	})
 }
 
+func TestOSFileNamesProvider_GetAllFileNamesIgnore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     fileprovider.NamesConfig
+		layout  func(root string)
+		wantRel []string
+	}{
+		{
+			name: "skips .git and files from nested ignore files",
+			layout: func(root string) {
+				writeFiles(t, []string{
+					filepath.Join(root, ".git", "HEAD"),
+					filepath.Join(root, "main.go"),
+					filepath.Join(root, "app.log"),
+					filepath.Join(root, "node_modules", "dep", "index.js"),
+					filepath.Join(root, "pkg", "lib.go"),
+					filepath.Join(root, "pkg", "gen.pb.go"),
+					filepath.Join(root, "pkg", "keep.log"),
+				})
+				writeContent(t, filepath.Join(root, ".gitignore"), "*.log\nnode_modules/\n")
+				writeContent(t, filepath.Join(root, "pkg", ".gitignore"), "!keep.log\n")
+				writeContent(t, filepath.Join(root, "pkg", ".hangeignore"), "*.pb.go\n")
+			},
+			wantRel: []string{".gitignore", "main.go", "pkg/.gitignore", "pkg/.hangeignore", "pkg/lib.go", "pkg/keep.log"},
+		},
+		{
+			name: "anchored and double star patterns",
+			layout: func(root string) {
+				writeFiles(t, []string{
+					filepath.Join(root, "build", "out.txt"),
+					filepath.Join(root, "src", "build", "keep.txt"),
+					filepath.Join(root, "docs", "a", "b", "draft.md"),
+					filepath.Join(root, "docs", "readme.md"),
+				})
+				writeContent(t, filepath.Join(root, ".hangeignore"), "/build\ndocs/**/draft.md\n")
+			},
+			wantRel: []string{".hangeignore", "src/build/keep.txt", "docs/readme.md"},
+		},
+		{
+			name: "include and exclude patterns",
+			cfg: fileprovider.NamesConfig{
+				Include: []string{"*.go"},
+				Exclude: []string{"*_test.go", "vendor/"},
+			},
+			layout: func(root string) {
+				writeFiles(t, []string{
+					filepath.Join(root, "main.go"),
+					filepath.Join(root, "main_test.go"),
+					filepath.Join(root, "README.md"),
+					filepath.Join(root, "vendor", "dep", "dep.go"),
+				})
+			},
+			wantRel: []string{"main.go"},
+		},
+		{
+			name: "no ignore reads everything",
+			cfg:  fileprovider.NamesConfig{NoIgnore: true},
+			layout: func(root string) {
+				writeFiles(t, []string{
+					filepath.Join(root, ".git", "HEAD"),
+					filepath.Join(root, "app.log"),
+				})
+				writeContent(t, filepath.Join(root, ".gitignore"), "*.log\n")
+			},
+			wantRel: []string{".git/HEAD", ".gitignore", "app.log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			tt.layout(root)
+
+			provider := NewOSFileNamesProvider(fileerrormapper_mock.NewMockFileErrorMapper(t))
+			got, err := provider.GetAllFileNames(t.Context(), tt.cfg, []string{root})
+			require.NoError(t, err)
+
+			for i := range got {
+				got[i], _ = filepath.Rel(root, got[i])
+			}
+			assert.ElementsMatch(t, tt.wantRel, got)
+		})
+	}
+}
+
+func TestOSFileNamesProvider_GetAllFileNamesParentIgnore(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, []string{
+		filepath.Join(root, ".git", "HEAD"),
+		filepath.Join(root, "sub", "a.txt"),
+		filepath.Join(root, "sub", "b.tmp"),
+	})
+	writeContent(t, filepath.Join(root, ".gitignore"), "*.tmp\n")
+
+	provider := NewOSFileNamesProvider(fileerrormapper_mock.NewMockFileErrorMapper(t))
+	got, err := provider.GetAllFileNames(t.Context(), fileprovider.NamesConfig{}, []string{filepath.Join(root, "sub")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "sub", "a.txt")}, got)
+}
+
+func TestOSFileNamesProvider_GetAllFileNamesConfigIgnore(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, []string{
+		filepath.Join(root, ".git", "HEAD"),
+		filepath.Join(root, "gen", "a.go"),
+		filepath.Join(root, "sub", "gen", "b.go"),
+		filepath.Join(root, "sub", "c.log"),
+	})
+
+	// the rules of a repository config are relative to its directory, whatever is explained
+	rules, err := ignore.ParsePatterns(root, []string{"/gen", "*.log"})
+	require.NoError(t, err)
+
+	cfg := fileprovider.NamesConfig{Ignore: rules}
+	provider := NewOSFileNamesProvider(fileerrormapper_mock.NewMockFileErrorMapper(t))
+
+	got, err := provider.GetAllFileNames(t.Context(), cfg, []string{filepath.Join(root, "sub")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "sub", "gen", "b.go")}, got)
+
+	got, err = provider.GetAllFileNames(t.Context(), cfg, []string{root})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "sub", "gen", "b.go")}, got)
+
+	cfg.NoIgnore = true
+
+	got, err = provider.GetAllFileNames(t.Context(), cfg, []string{filepath.Join(root, "sub")})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(root, "sub", "gen", "b.go"),
+		filepath.Join(root, "sub", "c.log"),
+	}, got)
+}
+
+func TestOSFileNamesProvider_GetAllFileNamesSymlinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     fileprovider.NamesConfig
+		layout  func(t *testing.T, root, outside string)
+		wantRel []string
+	}{
+		{
+			name: "symlinks are skipped by default",
+			layout: func(t *testing.T, root, _ string) {
+				writeFiles(t, []string{filepath.Join(root, "a.txt"), filepath.Join(root, "sub", "b.txt")})
+				symlink(t, "a.txt", filepath.Join(root, "link.txt"))
+				symlink(t, "sub", filepath.Join(root, "linkdir"))
+			},
+			wantRel: []string{"a.txt", "sub/b.txt"},
+		},
+		{
+			name: "symlinked files and directories are followed",
+			cfg:  fileprovider.NamesConfig{FollowSymlinks: true},
+			layout: func(t *testing.T, root, _ string) {
+				writeFiles(t, []string{filepath.Join(root, "a.txt"), filepath.Join(root, "sub", "nested", "b.txt")})
+				symlink(t, "a.txt", filepath.Join(root, "link.txt"))
+				symlink(t, filepath.Join("sub", "nested"), filepath.Join(root, "z-link"))
+			},
+			// sub/nested is reached first, so the link to it is a duplicate
+			wantRel: []string{"a.txt", "link.txt", "sub/nested/b.txt"},
+		},
+		{
+			name: "symlink loop to itself",
+			cfg:  fileprovider.NamesConfig{FollowSymlinks: true},
+			layout: func(t *testing.T, root, _ string) {
+				writeFiles(t, []string{filepath.Join(root, "dir", "a.txt")})
+				symlink(t, ".", filepath.Join(root, "dir", "loop"))
+			},
+			wantRel: []string{"dir/a.txt"},
+		},
+		{
+			name: "symlink to a parent directory",
+			cfg:  fileprovider.NamesConfig{FollowSymlinks: true},
+			layout: func(t *testing.T, root, _ string) {
+				writeFiles(t, []string{filepath.Join(root, "a.txt"), filepath.Join(root, "x", "y", "b.txt")})
+				symlink(t, filepath.Join("..", ".."), filepath.Join(root, "x", "y", "up"))
+			},
+			wantRel: []string{"a.txt", "x/y/b.txt"},
+		},
+		{
+			name: "mutual symlink loop",
+			cfg:  fileprovider.NamesConfig{FollowSymlinks: true},
+			layout: func(t *testing.T, root, _ string) {
+				writeFiles(t, []string{filepath.Join(root, "a", "a.txt"), filepath.Join(root, "b", "b.txt")})
+				symlink(t, filepath.Join("..", "b"), filepath.Join(root, "a", "to-b"))
+				symlink(t, filepath.Join("..", "a"), filepath.Join(root, "b", "to-a"))
+			},
+			// every directory is read once, b is reached through the link first
+			wantRel: []string{"a/a.txt", "a/to-b/b.txt"},
+		},
+		{
+			name: "broken symlink is skipped",
+			cfg:  fileprovider.NamesConfig{FollowSymlinks: true},
+			layout: func(t *testing.T, root, _ string) {
+				writeFiles(t, []string{filepath.Join(root, "a.txt")})
+				symlink(t, "missing.txt", filepath.Join(root, "broken.txt"))
+			},
+			wantRel: []string{"a.txt"},
+		},
+		{
+			name: "escape outside of the root is refused",
+			cfg:  fileprovider.NamesConfig{FollowSymlinks: true},
+			layout: func(t *testing.T, root, outside string) {
+				writeFiles(t, []string{filepath.Join(root, "a.txt"), filepath.Join(outside, "secret.txt")})
+				symlink(t, outside, filepath.Join(root, "out"))
+				symlink(t, filepath.Join(outside, "secret.txt"), filepath.Join(root, "secret.txt"))
+			},
+			wantRel: []string{"a.txt"},
+		},
+		{
+			name: "escape outside of the root is allowed",
+			cfg:  fileprovider.NamesConfig{FollowSymlinks: true, AllowSymlinkEscape: true},
+			layout: func(t *testing.T, root, outside string) {
+				writeFiles(t, []string{filepath.Join(root, "a.txt"), filepath.Join(outside, "secret.txt")})
+				symlink(t, outside, filepath.Join(root, "out"))
+			},
+			wantRel: []string{"a.txt", "out/secret.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			tt.layout(t, root, t.TempDir())
+
+			provider := NewOSFileNamesProvider(fileerrormapper_mock.NewMockFileErrorMapper(t))
+			got, err := provider.GetAllFileNames(t.Context(), tt.cfg, []string{root})
+			require.NoError(t, err)
+
+			for i := range got {
+				got[i], _ = filepath.Rel(root, got[i])
+				got[i] = filepath.ToSlash(got[i])
+			}
+			assert.ElementsMatch(t, tt.wantRel, got)
+		})
+	}
+}
+
+func newTestWalker(t *testing.T, root string) *walker {
+	t.Helper()
+
+	w, err := newWalker(fileprovider.NamesConfig{}, root)
+	require.NoError(t, err)
+
+	return w
+}
+
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(link), 0o755))
+	require.NoError(t, os.Symlink(target, link))
+}
+
+func newTestPathFilter(t *testing.T) *pathFilter {
+	t.Helper()
+
+	f, err := newPathFilter(fileprovider.NamesConfig{}, t.TempDir())
+	require.NoError(t, err)
+
+	return f
+}
+
+func writeContent(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
 func writeFiles(t *testing.T, paths []string) {
 	t.Helper()
 	for _, p := range paths {
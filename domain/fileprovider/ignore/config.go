@@ -0,0 +1,48 @@
+package ignore
+
+import (
+	"path/filepath"
+
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/git"
+)
+
+// ReadConfig compiles the "ignore" patterns of the config. Patterns of a repository config are relative to
+// its directory, as patterns of a .hangeignore file there. Patterns of the user config and env are relative
+// to the root of the repository of workDir, or to workDir outside of a repository.
+func ReadConfig(cfg config.Configurator, workDir string) ([]Rule, error) {
+	patterns, err := config.ReadStringSlice(cfg, consts.IgnorePath)
+	if err != nil || len(patterns) == 0 {
+		return nil, err
+	}
+
+	base, err := configBase(cfg, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePatterns(base, patterns)
+}
+
+func configBase(cfg config.Configurator, workDir string) (string, error) {
+	if cfg.FieldSource(consts.IgnorePath) == config.SourceRepo && cfg.RepoPath() != "" {
+		return filepath.Dir(cfg.RepoPath()), nil
+	}
+
+	workDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := git.FindRoot(workDir)
+	if err != nil {
+		return "", err
+	}
+
+	if root == "" {
+		return workDir, nil
+	}
+
+	return root, nil
+}
@@ -0,0 +1,64 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/config"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+)
+
+func TestReadConfig(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o755))
+
+	sub := filepath.Join(repo, "sub")
+	require.NoError(t, os.Mkdir(sub, 0o755))
+
+	t.Run("repository config", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().ReadField("ignore").Return([]any{"/gen"})
+		cfg.EXPECT().FieldSource("ignore").Return(config.SourceRepo)
+		cfg.EXPECT().RepoPath().Return(filepath.Join(sub, ".hange.yaml"))
+
+		rules, err := ReadConfig(cfg, repo)
+		require.NoError(t, err)
+
+		m := NewMatcher(rules...)
+		assert.True(t, m.Ignored(filepath.Join(sub, "gen"), true))
+		assert.False(t, m.Ignored(filepath.Join(repo, "gen"), true))
+	})
+
+	t.Run("user config is relative to the repository root", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().ReadField("ignore").Return([]any{"/gen"})
+		cfg.EXPECT().FieldSource("ignore").Return(config.SourceFile)
+
+		rules, err := ReadConfig(cfg, sub)
+		require.NoError(t, err)
+
+		m := NewMatcher(rules...)
+		assert.True(t, m.Ignored(filepath.Join(repo, "gen"), true))
+		assert.False(t, m.Ignored(filepath.Join(sub, "gen"), true))
+	})
+
+	t.Run("no patterns", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := configurator_mock.NewMockConfigurator(t)
+		cfg.EXPECT().ReadField("ignore").Return(nil)
+
+		rules, err := ReadConfig(cfg, repo)
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+}
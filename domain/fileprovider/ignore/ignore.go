@@ -0,0 +1,384 @@
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+const (
+	GitIgnoreFileName   = ".gitignore"
+	HangeIgnoreFileName = ".hangeignore"
+	GitDirName          = ".git"
+)
+
+// FileNames lists ignore files read from every directory, in the order of increasing precedence.
+var FileNames = []string{GitIgnoreFileName, HangeIgnoreFileName}
+
+// Rule is a single compiled gitignore pattern. Rules are relative to the directory of the file they come from.
+type Rule struct {
+	base    string
+	negate  bool
+	dirOnly bool
+	re      *regexp.Regexp
+}
+
+// Matcher holds ordered rules. The last matching rule decides whether a path is ignored, exactly as git does.
+// Matcher is immutable: With returns a copy, so children directories can extend rules without affecting siblings.
+type Matcher struct {
+	rules []Rule
+}
+
+func NewMatcher(rules ...Rule) *Matcher {
+	return &Matcher{rules: rules}
+}
+
+// With returns a new matcher with extra rules appended after the existing ones.
+func (m *Matcher) With(rules ...Rule) *Matcher {
+	if len(rules) == 0 {
+		return m
+	}
+
+	merged := make([]Rule, 0, len(m.rules)+len(rules))
+	merged = append(merged, m.rules...)
+	merged = append(merged, rules...)
+
+	return &Matcher{rules: merged}
+}
+
+// Match reports whether any rule matches the path, and whether the decisive rule ignores it.
+// Path must be absolute.
+func (m *Matcher) Match(path string, isDir bool) (matched bool, ignored bool) {
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		r := m.rules[i]
+
+		if r.dirOnly && !isDir {
+			continue
+		}
+
+		rel, err := filepath.Rel(r.base, path)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			// rules never apply outside their base directory
+			continue
+		}
+
+		if r.re.MatchString(filepath.ToSlash(rel)) {
+			return true, !r.negate
+		}
+	}
+
+	return false, false
+}
+
+// Ignored reports whether the path is ignored by the rules.
+func (m *Matcher) Ignored(path string, isDir bool) bool {
+	_, ignored := m.Match(path, isDir)
+
+	return ignored
+}
+
+// ParsePatterns compiles gitignore patterns relative to the absolute base directory.
+// Blank lines and comments are skipped.
+func ParsePatterns(base string, patterns []string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, p := range patterns {
+		r, ok, err := parseRule(base, p)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			rules = append(rules, r)
+		}
+	}
+
+	return rules, nil
+}
+
+// ReadFile compiles rules of a gitignore-like file. A missing file produces no rules and no error.
+// Invalid patterns are skipped with a warning.
+func ReadFile(path string) ([]Rule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var rules []Rule
+
+	base := filepath.Dir(path)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for line := 1; scanner.Scan(); line++ {
+		r, ok, err := parseRule(base, scanner.Text())
+		if err != nil {
+			// git ignores a pattern it can't parse, one bad line doesn't fail the whole read
+			slog.Warn("Skipping invalid ignore pattern", slog.String(logging.KeyFile, path), slog.Int("line", line),
+				logging.Err(err))
+
+			continue
+		}
+
+		if ok {
+			rules = append(rules, r)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ReadDir compiles rules of all the ignore files located in the directory.
+func ReadDir(dir string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, name := range FileNames {
+		r, err := ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, r...)
+	}
+
+	return rules, nil
+}
+
+// FindRepoRoot walks up from the absolute dir and returns the closest directory containing ".git".
+func FindRepoRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, GitDirName)); err == nil {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+// ReadAncestors compiles rules of ignore files located in the parents of the absolute dir, up to the repository root.
+// Rules of the dir itself are not included. Nothing is read if dir is not inside a git repository.
+func ReadAncestors(dir string) ([]Rule, error) {
+	root, ok := FindRepoRoot(dir)
+	if !ok || root == dir {
+		return nil, nil
+	}
+
+	var parents []string
+	for p := filepath.Dir(dir); ; p = filepath.Dir(p) {
+		parents = append(parents, p)
+
+		if p == root || p == filepath.Dir(p) {
+			break
+		}
+	}
+
+	var rules []Rule
+
+	// from the root down to the closest parent, so deeper files take precedence
+	for i := len(parents) - 1; i >= 0; i-- {
+		r, err := ReadDir(parents[i])
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, r...)
+	}
+
+	return rules, nil
+}
+
+func parseRule(base, pattern string) (Rule, bool, error) {
+	pattern = strings.TrimSuffix(pattern, "\r")
+	pattern = trimTrailingSpaces(pattern)
+
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return Rule{}, false, nil
+	}
+
+	r := Rule{base: base}
+
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		r.negate = true
+		pattern = pattern[1:]
+	case strings.HasPrefix(pattern, `\!`), strings.HasPrefix(pattern, `\#`):
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	if pattern == "" {
+		return Rule{}, false, nil
+	}
+
+	// a slash at the beginning or in the middle anchors the pattern to the base directory
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	expr := translate(pattern)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(?:.*/)?" + expr + "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return Rule{}, false, err
+	}
+
+	r.re = re
+
+	return r, true, nil
+}
+
+// translate converts a gitignore glob into a regular expression body.
+func translate(pattern string) string {
+	var b strings.Builder
+
+	segments := strings.Split(pattern, "/")
+
+	for i, seg := range segments {
+		last := i == len(segments)-1
+
+		if seg == "**" {
+			switch {
+			case last && i == 0:
+				b.WriteString(".*")
+			case last:
+				// "abc/**" matches everything inside abc
+				b.WriteString(".+")
+			default:
+				// "**/" matches zero or more directories
+				b.WriteString("(?:.*/)?")
+			}
+
+			continue
+		}
+
+		b.WriteString(translateSegment(seg))
+
+		if !last {
+			b.WriteString("/")
+		}
+	}
+
+	return b.String()
+}
+
+func translateSegment(seg string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(seg); i++ {
+		c := seg[i]
+
+		switch c {
+		case '*':
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '\\':
+			if i+1 < len(seg) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(seg[i])))
+			}
+		case '[':
+			class, n := translateClass(seg[i:])
+			if n == 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			b.WriteString(class)
+			i += n - 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
+
+// translateClass converts a bracket expression at the beginning of s, e.g. "[!a-z]" or "[[:space:]]", and returns
+// its length in s. The length is 0 if the expression is not closed, so "[" is a literal.
+func translateClass(s string) (string, int) {
+	var b strings.Builder
+
+	b.WriteString("[")
+
+	i := 1
+	if i < len(s) && (s[i] == '!' || s[i] == '^') {
+		b.WriteString("^")
+		i++
+	}
+
+	for first := true; i < len(s); first = false {
+		c := s[i]
+
+		switch {
+		case c == ']' && !first:
+			// "]" right after the opening bracket is a member of the class
+			b.WriteString("]")
+			return b.String(), i + 1
+		case c == '[' && strings.HasPrefix(s[i:], "[:"):
+			end := strings.Index(s[i+2:], ":]")
+			if end < 0 {
+				b.WriteString(`\[`)
+				i++
+
+				continue
+			}
+
+			// POSIX classes have the same syntax in regexp, an unknown name fails the compilation
+			b.WriteString(s[i : i+2+end+2])
+			i += 2 + end + 2
+		case c == '\\' && i+1 < len(s):
+			b.WriteString(classLiteral(s[i+1]))
+			i += 2
+		default:
+			b.WriteString(classLiteral(c))
+			i++
+		}
+	}
+
+	return "", 0
+}
+
+func classLiteral(c byte) string {
+	switch c {
+	case '\\', ']', '[', '^':
+		return `\` + string(c)
+	default:
+		return string(c)
+	}
+}
+
+func trimTrailingSpaces(s string) string {
+	for strings.HasSuffix(s, " ") && !strings.HasSuffix(s, `\ `) {
+		s = s[:len(s)-1]
+	}
+
+	return strings.ReplaceAll(s, `\ `, " ")
+}
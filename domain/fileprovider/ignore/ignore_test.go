@@ -0,0 +1,133 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcherIgnored(t *testing.T) {
+	t.Parallel()
+
+	const base = "/repo"
+
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{name: "basename at any level", patterns: []string{"*.log"}, path: "a/b/c.log", want: true},
+		{name: "not matching", patterns: []string{"*.log"}, path: "a/b/c.go", want: false},
+		{name: "negation wins when last", patterns: []string{"*.log", "!keep.log"}, path: "keep.log", want: false},
+		{name: "later pattern wins over negation", patterns: []string{"!keep.log", "*.log"}, path: "keep.log", want: true},
+		{name: "leading slash anchors", patterns: []string{"/build"}, path: "src/build", isDir: true, want: false},
+		{name: "anchored matches at base", patterns: []string{"/build"}, path: "build", isDir: true, want: true},
+		{name: "middle slash anchors", patterns: []string{"doc/frotz"}, path: "a/doc/frotz", want: false},
+		{name: "dir only skips files", patterns: []string{"tmp/"}, path: "tmp", want: false},
+		{name: "dir only matches dirs", patterns: []string{"tmp/"}, path: "a/tmp", isDir: true, want: true},
+		{name: "leading double star", patterns: []string{"**/foo"}, path: "x/y/foo", want: true},
+		{name: "trailing double star", patterns: []string{"abc/**"}, path: "abc/x/y", want: true},
+		{name: "trailing double star skips the dir itself", patterns: []string{"abc/**"}, path: "abc", isDir: true, want: false},
+		{name: "middle double star zero dirs", patterns: []string{"a/**/b"}, path: "a/b", want: true},
+		{name: "middle double star many dirs", patterns: []string{"a/**/b"}, path: "a/x/y/b", want: true},
+		{name: "question mark", patterns: []string{"file?.txt"}, path: "file1.txt", want: true},
+		{name: "star does not cross slash", patterns: []string{"a/*.txt"}, path: "a/b/c.txt", want: false},
+		{name: "character class", patterns: []string{"[ab].txt"}, path: "b.txt", want: true},
+		{name: "negated character class", patterns: []string{"[!ab].txt"}, path: "b.txt", want: false},
+		{name: "posix class", patterns: []string{"foo[[:space:]]bar"}, path: "foo bar", want: true},
+		{name: "posix class misses", patterns: []string{"foo[[:space:]]bar"}, path: "foo]bar", want: false},
+		{name: "posix class with a range", patterns: []string{"v[[:digit:]x-z]"}, path: "vy", want: true},
+		{name: "negated class of one char", patterns: []string{"[!a]"}, path: "a", want: false},
+		{name: "negated class matches others", patterns: []string{"[!a]"}, path: "b", want: true},
+		{name: "bracket first in class", patterns: []string{"[]a]"}, path: "]", want: true},
+		{name: "bracket first in class and more", patterns: []string{"[]a]"}, path: "a", want: true},
+		{name: "unclosed bracket is literal", patterns: []string{"a[b"}, path: "a[b", want: true},
+		{name: "escaped hash", patterns: []string{`\#notes`}, path: "#notes", want: true},
+		{name: "comment is skipped", patterns: []string{"# comment"}, path: "# comment", want: false},
+		{name: "trailing spaces are trimmed", patterns: []string{"a.txt   "}, path: "a.txt", want: true},
+		{name: "outside base is never matched", patterns: []string{"*.txt"}, path: "../a.txt", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules, err := ParsePatterns(base, tt.patterns)
+			require.NoError(t, err)
+
+			m := NewMatcher(rules...)
+			assert.Equal(t, tt.want, m.Ignored(filepath.Join(base, tt.path), tt.isDir))
+		})
+	}
+}
+
+func TestMatcherWithDoesNotAffectParent(t *testing.T) {
+	t.Parallel()
+
+	parentRules, err := ParsePatterns("/repo", []string{"*.log"})
+	require.NoError(t, err)
+
+	childRules, err := ParsePatterns("/repo/sub", []string{"!*.log"})
+	require.NoError(t, err)
+
+	parent := NewMatcher(parentRules...)
+	child := parent.With(childRules...)
+
+	assert.False(t, child.Ignored("/repo/sub/a.log", false))
+	assert.True(t, parent.Ignored("/repo/sub/a.log", false))
+	assert.True(t, child.Ignored("/repo/a.log", false), "child rules must not apply outside their directory")
+}
+
+func TestReadAncestors(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, GitDirName), 0o755))
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, GitIgnoreFileName), []byte("*.tmp\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", HangeIgnoreFileName), []byte("*.bak\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(nested, GitIgnoreFileName), []byte("*.skip\n"), 0o644))
+
+	rules, err := ReadAncestors(nested)
+	require.NoError(t, err)
+
+	m := NewMatcher(rules...)
+	assert.True(t, m.Ignored(filepath.Join(nested, "x.tmp"), false))
+	assert.True(t, m.Ignored(filepath.Join(nested, "x.bak"), false))
+	assert.False(t, m.Ignored(filepath.Join(nested, "x.skip"), false), "rules of the dir itself are not read")
+}
+
+func TestReadAncestorsOutsideRepository(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "a")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(dir), GitIgnoreFileName), []byte("*\n"), 0o644))
+
+	rules, err := ReadAncestors(dir)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestReadFileSkipsInvalidPatterns(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), GitIgnoreFileName)
+	require.NoError(t, os.WriteFile(path, []byte("*.log\nfoo[[:nope:]]\nfoo[[:space:]]bar\n"), 0o644))
+
+	rules, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	m := NewMatcher(rules...)
+	dir := filepath.Dir(path)
+	assert.True(t, m.Ignored(filepath.Join(dir, "a.log"), false))
+	assert.True(t, m.Ignored(filepath.Join(dir, "foo bar"), false))
+}
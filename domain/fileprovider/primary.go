@@ -2,8 +2,11 @@ package fileprovider
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/ignore"
+	"github.com/yaroslav-koval/hange/pkg/progress"
 )
 
 type FileProvider interface {
@@ -14,4 +17,63 @@ type FileProvider interface {
 type Config struct {
 	Workers    int
 	BufferSize int
+	// Policy decides which files are skipped after their content is sniffed.
+	Policy SkipPolicy
+	// Skipped receives every file skipped by Policy. Can be nil.
+	Skipped SkipReporter
+	// StdinName is a path displayed for content read from stdin.
+	StdinName string
+	// Progress receives the read files and their size in progress.StageRead, skipped ones included. Can be nil.
+	// The caller finishes the stage.
+	Progress progress.Reporter
+}
+
+// DefaultMaxFileSize is a size limit of explained files unless another one is set.
+const DefaultMaxFileSize = 1 << 20 // 1 MiB
+
+// SniffLen is the amount of leading bytes inspected for binary content and generated markers.
+// The same amount is used by git to detect binary files.
+const SniffLen = 8000
+
+// SkipPolicy describes files that are not worth sending anywhere. Binary files are always skipped.
+type SkipPolicy struct {
+	// MaxFileSize is a size limit in bytes. 0 means no limit.
+	MaxFileSize int64
+	// AllowGenerated keeps generated code, lockfiles and minified assets.
+	AllowGenerated bool
+}
+
+// TooLarge returns a skipped file if the size exceeds MaxFileSize, so a file can be skipped before it is read.
+func (p SkipPolicy) TooLarge(path string, size int64) *entities.SkippedFile {
+	if p.MaxFileSize <= 0 || size <= p.MaxFileSize {
+		return nil
+	}
+
+	return &entities.SkippedFile{
+		Path:    path,
+		Reason:  entities.SkipReasonTooLarge,
+		Details: fmt.Sprintf("%d bytes, limit is %d bytes", size, p.MaxFileSize),
+	}
+}
+
+type SkipReporter interface {
+	ReportSkipped(entities.SkippedFile)
+}
+
+// NamesConfig controls which files are picked up during a recursive read of directories.
+type NamesConfig struct {
+	// Include keeps only files matching at least one of gitignore-style patterns. Empty means all files.
+	// Patterns are relative to each input path.
+	Include []string
+	// Exclude drops files and directories matching any of gitignore-style patterns.
+	// Patterns are relative to each input path.
+	Exclude []string
+	// Ignore are rules of the config. They apply inside directories as an ignore file read before all the others.
+	Ignore []ignore.Rule
+	// NoIgnore disables .gitignore and .hangeignore files and the config rules and stops skipping the .git directory.
+	NoIgnore bool
+	// FollowSymlinks reads symlinked files and directories found inside directories. Symlinks are skipped otherwise.
+	FollowSymlinks bool
+	// AllowSymlinkEscape lets followed symlinks point outside the input directory.
+	AllowSymlinkEscape bool
 }
@@ -5,42 +5,50 @@ import (
 	"sync/atomic"
 
 	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/pkg/progress"
 	"golang.org/x/sync/errgroup"
 )
 
-func NewFileProvider(fnProvider FileNamesProvider, fcProvider FileContentProvider) FileProvider {
+func NewFileProvider(
+	fnProvider FileNamesProvider, fcProvider FileContentProvider, classifier ContentClassifier) FileProvider {
 	return &fileProvider{
 		fileNamesProvider:   fnProvider,
 		fileContentProvider: fcProvider,
+		contentClassifier:   classifier,
 	}
 }
 
 type fileProvider struct {
 	fileNamesProvider   FileNamesProvider
 	fileContentProvider FileContentProvider
+	contentClassifier   ContentClassifier
 }
 
-func (d *fileProvider) GetAllFileNames(ctx context.Context, strings []string) ([]string, error) {
-	return d.fileNamesProvider.GetAllFileNames(ctx, strings)
+func (d *fileProvider) GetAllFileNames(ctx context.Context, cfg NamesConfig, strings []string) ([]string, error) {
+	return d.fileNamesProvider.GetAllFileNames(ctx, cfg, strings)
 }
 
 // ReadFiles reads files and directories recursively. Second argument accepts both file paths and directory paths.
+// Files rejected by the skip policy are not sent to the channel, they are reported to cfg.Skipped instead.
 func (d *fileProvider) ReadFiles(ctx context.Context, cfg Config, fileNames []string) (<-chan entities.File, <-chan error) {
 	fileCh := make(chan entities.File, cfg.BufferSize)
 
-	doneCh := d.produceFiles(ctx, fileNames, cfg.Workers, fileCh)
+	doneCh := d.produceFiles(ctx, cfg, fileNames, fileCh)
 
 	return fileCh, doneCh
 }
 
 func (d *fileProvider) produceFiles(
-	ctx context.Context, filePaths []string, workersCount int, filesCh chan<- entities.File) <-chan error {
+	ctx context.Context, cfg Config, filePaths []string, filesCh chan<- entities.File) <-chan error {
 	eg, ctx := errgroup.WithContext(ctx)
 
+	reporter := progress.OrDiscard(cfg.Progress)
+	reporter.Expect(progress.StageRead, len(filePaths))
+
 	fnIndex := atomic.Int32{}
 	fnIndex.Add(-1) // to start indexation from 0 after first 'fnIndex.Add(a)'
 
-	for range workersCount {
+	for range cfg.Workers {
 		eg.Go(func() error {
 			for {
 				// Select statement (at the end) doesn't guarantee order of cases execution.
@@ -55,14 +63,41 @@ func (d *fileProvider) produceFiles(
 					return nil
 				}
 
+				skipped, err := d.sniffFile(ctx, filePaths[i], cfg.Policy)
+				if err != nil {
+					return err
+				}
+
+				if skipped != nil {
+					reporter.Done(progress.StageRead, 1, 0)
+
+					if cfg.Skipped != nil {
+						cfg.Skipped.ReportSkipped(*skipped)
+					}
+
+					continue
+				}
+
 				fBytes, err := d.fileContentProvider.GetFileContent(ctx, filePaths[i])
 				if err != nil {
 					return err
 				}
 
+				reporter.Done(progress.StageRead, 1, int64(len(fBytes)))
+
+				contentType, skipped := d.contentClassifier.Classify(filePaths[i], fBytes, cfg.Policy)
+				if skipped != nil {
+					if cfg.Skipped != nil {
+						cfg.Skipped.ReportSkipped(*skipped)
+					}
+
+					continue
+				}
+
 				f := entities.File{
-					Path: filePaths[i],
-					Data: fBytes,
+					Path:        filePaths[i],
+					Data:        fBytes,
+					ContentType: contentType,
 				}
 
 				select {
@@ -84,3 +119,28 @@ func (d *fileProvider) produceFiles(
 
 	return doneCh
 }
+
+// sniffFile skips a file which is too large or binary before it is read, so a large dump or image in the tree
+// never gets into memory. Only a bounded head of a file larger than it is read.
+func (d *fileProvider) sniffFile(ctx context.Context, path string, policy SkipPolicy) (*entities.SkippedFile, error) {
+	size, err := d.fileContentProvider.GetFileSize(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if skipped := policy.TooLarge(path, size); skipped != nil {
+		return skipped, nil
+	}
+
+	if size <= SniffLen {
+		// the whole file is as cheap to read as its head
+		return nil, nil
+	}
+
+	head, err := d.fileContentProvider.GetFileHead(ctx, path, SniffLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.contentClassifier.Sniff(path, head), nil
+}
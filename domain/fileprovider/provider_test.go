@@ -1,4 +1,4 @@
-package fileprovider
+package fileprovider_test
 
 import (
 	"context"
@@ -9,8 +9,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	contentclassifier_mock "github.com/yaroslav-koval/hange/mocks/contentclassifier"
 	filecontentprovider_mock "github.com/yaroslav-koval/hange/mocks/filecontentprovider"
 	filenamesprovider_mock "github.com/yaroslav-koval/hange/mocks/filenamesprovider"
+	reporter_mock "github.com/yaroslav-koval/hange/mocks/reporter"
+	"github.com/yaroslav-koval/hange/pkg/progress"
 )
 
 func TestFileProvider_ReadFilesSuccess(t *testing.T) {
@@ -24,15 +28,20 @@ func TestFileProvider_ReadFilesSuccess(t *testing.T) {
 		"b.txt": []byte("b"),
 	}
 
+	fcProvider.EXPECT().GetFileSize(mock.Anything, mock.Anything).Return(1, nil).Times(2)
 	fcProvider.EXPECT().GetFileContent(mock.Anything, "a.txt").Return(content["a.txt"], nil).Once()
 	fcProvider.EXPECT().GetFileContent(mock.Anything, "b.txt").Return(content["b.txt"], nil).Once()
 
-	fp := NewFileProvider(
+	classifier := contentclassifier_mock.NewMockContentClassifier(t)
+	classifier.EXPECT().Classify(mock.Anything, mock.Anything, mock.Anything).Return("text/plain", nil).Times(2)
+
+	fp := fileprovider.NewFileProvider(
 		filenamesprovider_mock.NewMockFileNamesProvider(t),
 		fcProvider,
+		classifier,
 	)
 
-	filesCh, doneCh := fp.ReadFiles(context.Background(), Config{Workers: 2, BufferSize: 4}, names)
+	filesCh, doneCh := fp.ReadFiles(context.Background(), fileprovider.Config{Workers: 2, BufferSize: 4}, names)
 
 	got := make(map[string][]byte)
 	for f := range filesCh {
@@ -52,14 +61,16 @@ func TestFileProvider_ReadFiles_ContentError(t *testing.T) {
 	filePath := "file.txt"
 
 	fcProvider := filecontentprovider_mock.NewMockFileContentProvider(t)
+	fcProvider.EXPECT().GetFileSize(mock.Anything, filePath).Return(4, nil)
 	fcProvider.EXPECT().GetFileContent(mock.Anything, filePath).Return(nil, expectedErr)
 
-	fp := NewFileProvider(
+	fp := fileprovider.NewFileProvider(
 		filenamesprovider_mock.NewMockFileNamesProvider(t),
 		fcProvider,
+		contentclassifier_mock.NewMockContentClassifier(t),
 	)
 
-	filesCh, doneCh := fp.ReadFiles(context.Background(), Config{Workers: 1, BufferSize: 2}, []string{filePath})
+	filesCh, doneCh := fp.ReadFiles(context.Background(), fileprovider.Config{Workers: 1, BufferSize: 2}, []string{filePath})
 
 	var files []entities.File
 	for f := range filesCh {
@@ -76,6 +87,7 @@ func TestFileProvider_ReadFilesCancelledContext(t *testing.T) {
 
 	fcProvider := filecontentprovider_mock.NewMockFileContentProvider(t)
 	started := make(chan struct{}, 1)
+	fcProvider.EXPECT().GetFileSize(mock.Anything, "file.txt").Return(1, nil)
 	fcProvider.EXPECT().
 		GetFileContent(mock.Anything, "file.txt").
 		RunAndReturn(func(ctx context.Context, _ string) ([]byte, error) {
@@ -84,13 +96,14 @@ func TestFileProvider_ReadFilesCancelledContext(t *testing.T) {
 			return nil, ctx.Err()
 		})
 
-	fp := NewFileProvider(
+	fp := fileprovider.NewFileProvider(
 		filenamesprovider_mock.NewMockFileNamesProvider(t),
 		fcProvider,
+		contentclassifier_mock.NewMockContentClassifier(t),
 	)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	filesCh, doneCh := fp.ReadFiles(ctx, Config{Workers: 1, BufferSize: 1}, []string{"file.txt"})
+	filesCh, doneCh := fp.ReadFiles(ctx, fileprovider.Config{Workers: 1, BufferSize: 1}, []string{"file.txt"})
 
 	<-started
 	cancel()
@@ -111,12 +124,13 @@ func TestFileProvider_ReadFilesCancelledContext(t *testing.T) {
 func TestFileProvider_ReadFilesEmpty(t *testing.T) {
 	t.Parallel()
 
-	fp := NewFileProvider(
+	fp := fileprovider.NewFileProvider(
 		filenamesprovider_mock.NewMockFileNamesProvider(t),
 		filecontentprovider_mock.NewMockFileContentProvider(t),
+		contentclassifier_mock.NewMockContentClassifier(t),
 	)
 
-	filesCh, doneCh := fp.ReadFiles(context.Background(), Config{Workers: 1, BufferSize: 1}, []string{})
+	filesCh, doneCh := fp.ReadFiles(context.Background(), fileprovider.Config{Workers: 1, BufferSize: 1}, []string{})
 
 	_, ok := <-filesCh
 	assert.False(t, ok, "files channel should close immediately for no paths")
@@ -124,3 +138,124 @@ func TestFileProvider_ReadFilesEmpty(t *testing.T) {
 	doneErr := <-doneCh
 	assert.NoError(t, doneErr)
 }
+
+func TestFileProvider_ReadFilesReportsSkipped(t *testing.T) {
+	t.Parallel()
+
+	policy := fileprovider.SkipPolicy{MaxFileSize: 100}
+	skipped := entities.SkippedFile{Path: "image.png", Reason: entities.SkipReasonBinary, Details: "image/png"}
+
+	fcProvider := filecontentprovider_mock.NewMockFileContentProvider(t)
+	fcProvider.EXPECT().GetFileSize(mock.Anything, "main.go").Return(12, nil)
+	fcProvider.EXPECT().GetFileSize(mock.Anything, "image.png").Return(4, nil)
+	fcProvider.EXPECT().GetFileContent(mock.Anything, "main.go").Return([]byte("package main"), nil)
+	fcProvider.EXPECT().GetFileContent(mock.Anything, "image.png").Return([]byte("\x89PNG"), nil)
+
+	classifier := contentclassifier_mock.NewMockContentClassifier(t)
+	classifier.EXPECT().Classify("main.go", []byte("package main"), policy).Return("text/plain", nil)
+	classifier.EXPECT().Classify("image.png", []byte("\x89PNG"), policy).Return("", &skipped)
+
+	fp := fileprovider.NewFileProvider(
+		filenamesprovider_mock.NewMockFileNamesProvider(t),
+		fcProvider,
+		classifier,
+	)
+
+	collector := fileprovider.NewSkipCollector()
+
+	filesCh, doneCh := fp.ReadFiles(context.Background(), fileprovider.Config{
+		Workers:    1,
+		BufferSize: 2,
+		Policy:     policy,
+		Skipped:    collector,
+	}, []string{"main.go", "image.png"})
+
+	var files []entities.File
+	for f := range filesCh {
+		files = append(files, f)
+	}
+
+	assert.Equal(t, []entities.File{{Path: "main.go", Data: []byte("package main"), ContentType: "text/plain"}}, files)
+	assert.NoError(t, <-doneCh)
+	assert.Equal(t, []entities.SkippedFile{skipped}, collector.Skipped())
+}
+
+func TestFileProvider_ReadFilesReportsProgress(t *testing.T) {
+	t.Parallel()
+
+	fcProvider := filecontentprovider_mock.NewMockFileContentProvider(t)
+	fcProvider.EXPECT().GetFileSize(mock.Anything, "a.txt").Return(3, nil).Once()
+	fcProvider.EXPECT().GetFileSize(mock.Anything, "b.bin").Return(2, nil).Once()
+	fcProvider.EXPECT().GetFileContent(mock.Anything, "a.txt").Return([]byte("aaa"), nil).Once()
+	fcProvider.EXPECT().GetFileContent(mock.Anything, "b.bin").Return([]byte{0, 1}, nil).Once()
+
+	classifier := contentclassifier_mock.NewMockContentClassifier(t)
+	classifier.EXPECT().Classify("a.txt", mock.Anything, mock.Anything).Return("text/plain", nil).Once()
+	classifier.EXPECT().Classify("b.bin", mock.Anything, mock.Anything).
+		Return("", &entities.SkippedFile{Path: "b.bin", Reason: entities.SkipReasonBinary}).Once()
+
+	// skipped files are read too, so they count
+	reporter := reporter_mock.NewMockReporter(t)
+	reporter.EXPECT().Expect(progress.StageRead, 2).Once()
+	reporter.EXPECT().Done(progress.StageRead, 1, int64(3)).Once()
+	reporter.EXPECT().Done(progress.StageRead, 1, int64(2)).Once()
+
+	fp := fileprovider.NewFileProvider(filenamesprovider_mock.NewMockFileNamesProvider(t), fcProvider, classifier)
+
+	filesCh, doneCh := fp.ReadFiles(context.Background(), fileprovider.Config{
+		Workers:    2,
+		BufferSize: 2,
+		Progress:   reporter,
+	}, []string{"a.txt", "b.bin"})
+
+	var files []entities.File
+	for f := range filesCh {
+		files = append(files, f)
+	}
+
+	assert.Len(t, files, 1)
+	assert.NoError(t, <-doneCh)
+}
+
+func TestFileProvider_ReadFilesSkipsBeforeReading(t *testing.T) {
+	t.Parallel()
+
+	policy := fileprovider.SkipPolicy{MaxFileSize: 1 << 20}
+	binary := entities.SkippedFile{Path: "blob.bin", Reason: entities.SkipReasonBinary, Details: "image/png"}
+	head := make([]byte, fileprovider.SniffLen)
+
+	// GetFileContent is not expected, so the mock fails the test if a skipped file is read
+	fcProvider := filecontentprovider_mock.NewMockFileContentProvider(t)
+	fcProvider.EXPECT().GetFileSize(mock.Anything, "dump.sql").Return(5<<30, nil)
+	fcProvider.EXPECT().GetFileSize(mock.Anything, "blob.bin").Return(512<<10, nil)
+	fcProvider.EXPECT().GetFileHead(mock.Anything, "blob.bin", fileprovider.SniffLen).Return(head, nil)
+
+	classifier := contentclassifier_mock.NewMockContentClassifier(t)
+	classifier.EXPECT().Sniff("blob.bin", head).Return(&binary)
+
+	reporter := reporter_mock.NewMockReporter(t)
+	reporter.EXPECT().Expect(progress.StageRead, 2).Once()
+	reporter.EXPECT().Done(progress.StageRead, 1, int64(0)).Twice()
+
+	collector := fileprovider.NewSkipCollector()
+
+	fp := fileprovider.NewFileProvider(filenamesprovider_mock.NewMockFileNamesProvider(t), fcProvider, classifier)
+
+	filesCh, doneCh := fp.ReadFiles(context.Background(), fileprovider.Config{
+		Workers:    2,
+		BufferSize: 2,
+		Policy:     policy,
+		Skipped:    collector,
+		Progress:   reporter,
+	}, []string{"dump.sql", "blob.bin"})
+
+	for range filesCh {
+		t.Error("no file is expected")
+	}
+
+	assert.NoError(t, <-doneCh)
+	assert.Equal(t, []entities.SkippedFile{
+		binary,
+		{Path: "dump.sql", Reason: entities.SkipReasonTooLarge, Details: "5368709120 bytes, limit is 1048576 bytes"},
+	}, collector.Skipped())
+}
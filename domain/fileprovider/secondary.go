@@ -1,12 +1,30 @@
 package fileprovider
 
-import "context"
+import (
+	"context"
+
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
 
 type FileContentProvider interface {
 	GetFileContent(context.Context, string) ([]byte, error)
+	// GetFileSize returns a size of the file without opening it.
+	GetFileSize(context.Context, string) (int64, error)
+	// GetFileHead reads up to n leading bytes of the file.
+	GetFileHead(ctx context.Context, path string, n int) ([]byte, error)
 }
 
 type FileNamesProvider interface {
 	// GetAllFileNames accepts both file paths and directory paths. Returns only file paths after a recursive read.
-	GetAllFileNames(context.Context, []string) ([]string, error)
+	// Files inside directories are filtered by ignore files and patterns from NamesConfig.
+	GetAllFileNames(context.Context, NamesConfig, []string) ([]string, error)
+}
+
+type ContentClassifier interface {
+	// Sniff decides by up to SniffLen leading bytes of a file if it is binary, so a large binary file is skipped
+	// without reading it. Returns nil if the file must be classified by its whole content.
+	Sniff(path string, head []byte) *entities.SkippedFile
+	// Classify detects a content type of a file and decides if it must be skipped by the policy.
+	// Returns nil SkippedFile if the file must be processed.
+	Classify(path string, data []byte, policy SkipPolicy) (contentType string, skipped *entities.SkippedFile)
 }
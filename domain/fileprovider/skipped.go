@@ -0,0 +1,41 @@
+package fileprovider
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// SkipCollector is a concurrency-safe SkipReporter that keeps skipped files in memory.
+type SkipCollector struct {
+	mutex *sync.Mutex
+	files []entities.SkippedFile
+}
+
+func NewSkipCollector() *SkipCollector {
+	return &SkipCollector{
+		mutex: &sync.Mutex{},
+	}
+}
+
+func (c *SkipCollector) ReportSkipped(f entities.SkippedFile) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.files = append(c.files, f)
+}
+
+// Skipped returns skipped files sorted by path.
+func (c *SkipCollector) Skipped() []entities.SkippedFile {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	res := slices.Clone(c.files)
+	slices.SortFunc(res, func(a, b entities.SkippedFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return res
+}
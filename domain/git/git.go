@@ -2,6 +2,9 @@ package git
 
 import "context"
 
+// DiffContextLines is a number of unchanged lines around changes in the patch of a commit message.
+const DiffContextLines = 30
+
 type ChangesProvider interface {
 	// Status of git, including staged and unstaged changes
 	Status(context.Context) (string, error)
@@ -10,9 +10,10 @@ import (
 	"github.com/yaroslav-koval/hange/domain/git"
 )
 
-func NewGitChangesProvider() git.ChangesProvider {
+// NewGitChangesProvider runs git in the directory, the working directory of the process if it is empty.
+func NewGitChangesProvider(dir string) git.ChangesProvider {
 	return &gitChangesProvider{
-		commandExecutor: &osExecutor{},
+		commandExecutor: &osExecutor{dir: dir},
 	}
 }
 
@@ -58,12 +59,15 @@ func (g *gitChangesProvider) Commit(ctx context.Context, message string) error {
 	}...)
 }
 
-type osExecutor struct{}
+type osExecutor struct {
+	dir string
+}
 
 func (o *osExecutor) Output(ctx context.Context, command string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = o.dir
 
-	slog.Debug(fmt.Sprintf("Executing command: %s", cmd.String()))
+	slog.Debug("Executing command", slog.String("command_line", cmd.String()))
 
 	res, err := cmd.Output()
 	if err != nil {
@@ -75,8 +79,9 @@ func (o *osExecutor) Output(ctx context.Context, command string, args ...string)
 
 func (o *osExecutor) Run(ctx context.Context, command string, args ...string) error {
 	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = o.dir
 
-	slog.Debug(fmt.Sprintf("Executing command: %s", cmd.String()))
+	slog.Debug("Executing command", slog.String("command_line", cmd.String()))
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
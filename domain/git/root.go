@@ -0,0 +1,31 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// FindRoot returns a root of the repository containing dir. It is empty outside of a repository.
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		// .git is a file in worktrees and submodules
+		if _, err = os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
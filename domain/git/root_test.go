@@ -0,0 +1,33 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRoot(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0700))
+
+	nested := filepath.Join(repo, "cmd", "app")
+	require.NoError(t, os.MkdirAll(nested, 0700))
+
+	root, err := FindRoot(nested)
+	require.NoError(t, err)
+	assert.Equal(t, repo, root)
+
+	// a worktree has a .git file
+	worktree := filepath.Join(repo, "worktree")
+	require.NoError(t, os.Mkdir(worktree, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: ../.git"), 0600))
+
+	root, err = FindRoot(worktree)
+	require.NoError(t, err)
+	assert.Equal(t, worktree, root)
+}
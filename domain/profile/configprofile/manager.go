@@ -0,0 +1,277 @@
+package configprofile
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+// NewConfigProfileManager creates a manager of profiles stored in the config.
+// override is a profile selected by a flag or env, it is empty if nothing is selected.
+func NewConfigProfileManager(config config.Configurator, override string) profile.ProfileManager {
+	return &configProfileManager{
+		config:   config,
+		override: override,
+	}
+}
+
+type configProfileManager struct {
+	config   config.Configurator
+	override string
+}
+
+func (m *configProfileManager) Active() string {
+	if m.override != "" {
+		return m.override
+	}
+
+	if name, ok := m.config.ReadField(consts.ActiveProfilePath).(string); ok && name != "" {
+		return name
+	}
+
+	return profile.DefaultName
+}
+
+func (m *configProfileManager) List() ([]profile.Profile, error) {
+	sections, err := m.sections()
+	if err != nil {
+		return nil, err
+	}
+
+	names := slices.Sorted(maps.Keys(sections))
+	if _, ok := sections[profile.DefaultName]; !ok {
+		names = append([]string{profile.DefaultName}, names...)
+	}
+
+	profiles := make([]profile.Profile, 0, len(names))
+
+	for _, name := range names {
+		p, err := m.resolve(name)
+		if err != nil {
+			return nil, err
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	return profiles, nil
+}
+
+func (m *configProfileManager) Get(name string) (profile.Profile, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return profile.Profile{}, err
+	}
+
+	sections, err := m.sections()
+	if err != nil {
+		return profile.Profile{}, err
+	}
+
+	if _, ok := sections[name]; !ok && name != profile.DefaultName {
+		return profile.Profile{}, fmt.Errorf("%w: %s", profile.ErrProfileNotFound, name)
+	}
+
+	return m.resolve(name)
+}
+
+func (m *configProfileManager) Add(p profile.Profile) error {
+	if err := profile.ValidateName(p.Name); err != nil {
+		return err
+	}
+
+	if err := profile.ValidateProvider(p.Provider); err != nil {
+		return err
+	}
+
+	sections, err := m.sections()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := sections[p.Name]; ok {
+		return fmt.Errorf("%w: %s", profile.ErrProfileExists, p.Name)
+	}
+
+	return m.config.WriteField(consts.ProfilePath(p.Name), toSection(p))
+}
+
+func (m *configProfileManager) Use(name string) error {
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+
+	return m.config.WriteField(consts.ActiveProfilePath, name)
+}
+
+func (m *configProfileManager) Remove(name string) error {
+	if name == profile.DefaultName {
+		return profile.ErrDefaultProfile
+	}
+
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+
+	if err := m.config.DeleteField(consts.ProfilePath(name)); err != nil {
+		return err
+	}
+
+	if active, _ := m.config.ReadField(consts.ActiveProfilePath).(string); active == name {
+		return m.config.DeleteField(consts.ActiveProfilePath)
+	}
+
+	return nil
+}
+
+func (m *configProfileManager) sections() (map[string]map[string]any, error) {
+	v := m.config.ReadField(consts.ProfilesPath)
+	if v == nil {
+		return map[string]map[string]any{}, nil
+	}
+
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map of profiles", consts.ProfilesPath)
+	}
+
+	sections := make(map[string]map[string]any, len(raw))
+
+	for name, s := range raw {
+		if s == nil {
+			sections[name] = map[string]any{}
+			continue
+		}
+
+		section, ok := s.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a map", consts.ProfilePath(name))
+		}
+
+		sections[name] = section
+	}
+
+	return sections, nil
+}
+
+// resolve reads the profile and fills the active one with the top-level settings
+func (m *configProfileManager) resolve(name string) (profile.Profile, error) {
+	p, err := m.read(name)
+	if err != nil || name != m.Active() {
+		return p, err
+	}
+
+	return m.applyDefaults(p)
+}
+
+// read reads the fields one by one, so env variables override them.
+func (m *configProfileManager) read(name string) (profile.Profile, error) {
+	p := profile.Profile{Name: name}
+
+	fields := map[string]*string{
+		consts.ProfileProviderField:            &p.Provider,
+		consts.ProfileBaseURLField:             &p.BaseURL,
+		consts.ProfileTokenCommandField:        &p.TokenCommand,
+		consts.ProfileTokenCommandTimeoutField: &p.TokenCommandTimeout,
+		consts.ProfileCommitModelField:         &p.Models.Commit,
+		consts.ProfileExplainModelField:        &p.Models.Explain,
+		consts.ProfileCommitPromptField:        &p.Prompts.Commit,
+		consts.ProfileExplainPromptField:       &p.Prompts.Explain,
+	}
+
+	for field, dst := range fields {
+		v := m.config.ReadField(consts.ProfilePath(name, field))
+		if v == nil {
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return profile.Profile{}, fmt.Errorf("%s must be a string", consts.ProfilePath(name, field))
+		}
+
+		*dst = s
+	}
+
+	conventional, err := config.ReadBool(m.config, consts.CommitConventionalPath)
+	if err != nil {
+		return profile.Profile{}, err
+	}
+
+	p.ConventionalCommits = conventional
+
+	return p, nil
+}
+
+// applyDefaults fills models and prompts the profile doesn't set with the top-level ones, e.g. a model chosen
+// by a repository config. Only the active profile gets them, so a routed profile of another provider keeps
+// its own models.
+func (m *configProfileManager) applyDefaults(p profile.Profile) (profile.Profile, error) {
+	defaults := map[string]*string{
+		consts.CommitModelPath:   &p.Models.Commit,
+		consts.ExplainModelPath:  &p.Models.Explain,
+		consts.CommitPromptPath:  &p.Prompts.Commit,
+		consts.ExplainPromptPath: &p.Prompts.Explain,
+	}
+
+	for path, dst := range defaults {
+		if *dst != "" {
+			continue
+		}
+
+		v := m.config.ReadField(path)
+		if v == nil {
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return profile.Profile{}, fmt.Errorf("%s must be a string", path)
+		}
+
+		*dst = s
+	}
+
+	return p, nil
+}
+
+// toSection converts the profile to a config section. Empty fields are omitted.
+func toSection(p profile.Profile) map[string]any {
+	section := map[string]any{}
+
+	set := func(path string, value string) {
+		if value == "" {
+			return
+		}
+
+		keys := strings.Split(path, ".")
+		m := section
+
+		for _, k := range keys[:len(keys)-1] {
+			child, ok := m[k].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				m[k] = child
+			}
+
+			m = child
+		}
+
+		m[keys[len(keys)-1]] = value
+	}
+
+	set(consts.ProfileProviderField, p.Provider)
+	set(consts.ProfileBaseURLField, p.BaseURL)
+	set(consts.ProfileTokenCommandField, p.TokenCommand)
+	set(consts.ProfileTokenCommandTimeoutField, p.TokenCommandTimeout)
+	set(consts.ProfileCommitModelField, p.Models.Commit)
+	set(consts.ProfileExplainModelField, p.Models.Explain)
+	set(consts.ProfileCommitPromptField, p.Prompts.Commit)
+	set(consts.ProfileExplainPromptField, p.Prompts.Explain)
+
+	return section
+}
@@ -0,0 +1,166 @@
+package configprofile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/configcli"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	"github.com/yaroslav-koval/hange/domain/profile/configprofile"
+)
+
+func TestConfigProfileManager_AddUseRemove(t *testing.T) {
+	t.Parallel()
+
+	cfg, cfgPath := newConfig(t, "ignore:\n  - dist/\n")
+	pm := configprofile.NewConfigProfileManager(cfg, "")
+
+	require.Equal(t, profile.DefaultName, pm.Active())
+
+	work := profile.Profile{
+		Name:         "work",
+		Provider:     profile.ProviderOpenAI,
+		BaseURL:      "https://llm.example.com/v1",
+		TokenCommand: "pass show work",
+		Models:       profile.Models{Commit: "gpt-5-mini"},
+		Prompts:      profile.Prompts{Explain: "Be brief."},
+	}
+	require.NoError(t, pm.Add(work))
+	require.ErrorIs(t, pm.Add(work), profile.ErrProfileExists)
+
+	got, err := pm.Get("work")
+	require.NoError(t, err)
+	assert.Equal(t, work, got)
+
+	require.NoError(t, pm.Use("work"))
+	assert.Equal(t, "work", pm.Active())
+
+	profiles, err := pm.List()
+	require.NoError(t, err)
+	assert.Equal(t, []profile.Profile{{Name: profile.DefaultName}, work}, profiles)
+
+	// profiles are persisted, a new manager reads them from the file
+	reloaded, err := configcli.NewCLIConfig(cfgPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, "work", configprofile.NewConfigProfileManager(reloaded, "").Active())
+
+	require.NoError(t, pm.Remove("work"))
+	assert.Equal(t, profile.DefaultName, pm.Active())
+
+	_, err = pm.Get("work")
+	require.ErrorIs(t, err, profile.ErrProfileNotFound)
+
+	// unrelated settings survive the removal
+	assert.Equal(t, []any{"dist/"}, cfg.ReadField("ignore"))
+}
+
+func TestConfigProfileManager_Override(t *testing.T) {
+	t.Parallel()
+
+	cfg, _ := newConfig(t, "active_profile: work\nprofiles:\n  work: {}\n  home:\n    provider: openai\n")
+
+	assert.Equal(t, "work", configprofile.NewConfigProfileManager(cfg, "").Active())
+	assert.Equal(t, "home", configprofile.NewConfigProfileManager(cfg, "home").Active())
+}
+
+func TestConfigProfileManager_TopLevelDefaults(t *testing.T) {
+	t.Parallel()
+
+	cfg, _ := newConfig(t, `active_profile: work
+profiles:
+  work:
+    models:
+      explain: gpt-5
+  local:
+    base_url: http://localhost:11434/v1
+    models:
+      explain: llama3.1
+routing:
+  explain: [work, local]
+models:
+  commit: gpt-5-nano
+  explain: gpt-5-mini
+prompts:
+  commit: Be brief.
+commit:
+  conventional: true
+`)
+	pm := configprofile.NewConfigProfileManager(cfg, "")
+
+	// the profile key wins over the top-level one, which fills the rest of the active profile
+	work, err := pm.Get("work")
+	require.NoError(t, err)
+	assert.Equal(t, profile.Models{Commit: "gpt-5-nano", Explain: "gpt-5"}, work.Models)
+	assert.Equal(t, profile.Prompts{Commit: "Be brief."}, work.Prompts)
+	assert.True(t, work.ConventionalCommits)
+
+	// a routed profile keeps its own models and gets no top-level ones
+	local, err := pm.Get("local")
+	require.NoError(t, err)
+	assert.Equal(t, profile.Models{Explain: "llama3.1"}, local.Models)
+	assert.Empty(t, local.Prompts)
+	assert.True(t, local.ConventionalCommits)
+
+	profiles, err := pm.List()
+	require.NoError(t, err)
+	assert.Equal(t, []profile.Profile{{Name: profile.DefaultName, ConventionalCommits: true}, local, work}, profiles)
+}
+
+func TestConfigProfileManager_Errors(t *testing.T) {
+	t.Parallel()
+
+	cfg, _ := newConfig(t, "profiles:\n  broken:\n    provider: 1\n")
+	pm := configprofile.NewConfigProfileManager(cfg, "")
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name:    "invalid name",
+			run:     func() error { return pm.Add(profile.Profile{Name: "my.work"}) },
+			wantErr: profile.ErrInvalidName,
+		},
+		{
+			name:    "unsupported provider",
+			run:     func() error { return pm.Add(profile.Profile{Name: "local", Provider: "ollama"}) },
+			wantErr: profile.ErrUnsupportedProvider,
+		},
+		{
+			name:    "use missing profile",
+			run:     func() error { return pm.Use("missing") },
+			wantErr: profile.ErrProfileNotFound,
+		},
+		{
+			name:    "remove default profile",
+			run:     func() error { return pm.Remove(profile.DefaultName) },
+			wantErr: profile.ErrDefaultProfile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.run(), tt.wantErr)
+		})
+	}
+
+	_, err := pm.Get("broken")
+	require.ErrorContains(t, err, "profiles.broken.provider must be a string")
+}
+
+func newConfig(t *testing.T, content string) (config.Configurator, string) {
+	t.Helper()
+
+	cfgPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0600))
+
+	cfg, err := configcli.NewCLIConfig(cfgPath, "")
+	require.NoError(t, err)
+
+	return cfg, cfgPath
+}
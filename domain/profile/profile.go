@@ -0,0 +1,81 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// DefaultName is a profile used if none is selected. It exists even if the config has no such section.
+const DefaultName = "default"
+
+const ProviderOpenAI = "openai"
+
+// Providers lists supported providers. A local model with an OpenAI compatible API uses ProviderOpenAI and BaseURL.
+var Providers = []string{ProviderOpenAI}
+
+var (
+	ErrProfileNotFound     = errors.New("profile not found")
+	ErrProfileExists       = errors.New("profile already exists")
+	ErrInvalidName         = errors.New("invalid profile name")
+	ErrUnsupportedProvider = errors.New("unsupported provider")
+	ErrDefaultProfile      = errors.New("default profile can't be removed")
+)
+
+// names are config keys, so dots are not allowed, and upper case is not preserved by the config
+var nameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Profile is a set of settings of one account or provider. Empty fields fall back to the defaults.
+type Profile struct {
+	Name     string
+	Provider string
+	BaseURL  string
+	// TokenCommand is a credential helper command. The stored token is used if it is empty.
+	TokenCommand        string
+	TokenCommandTimeout string
+	Models              Models
+	Prompts             Prompts
+	// ConventionalCommits is not a field of a profile in the config, it applies to every profile.
+	ConventionalCommits bool
+}
+
+// Models overrides models per command.
+type Models struct {
+	Commit  string
+	Explain string
+}
+
+// Prompts overrides system instructions per command.
+type Prompts struct {
+	Commit  string
+	Explain string
+}
+
+type ProfileManager interface {
+	// Active returns a name of the selected profile. A flag or env override wins over the config.
+	Active() string
+	List() ([]Profile, error)
+	Get(name string) (Profile, error)
+	Add(p Profile) error
+	// Use makes the profile active in the config.
+	Use(name string) error
+	// Remove deletes the profile with its stored token. The default profile becomes active if the removed one was.
+	Remove(name string) error
+}
+
+func ValidateName(name string) error {
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf("%w %q: use lower case letters, digits, '-' and '_'", ErrInvalidName, name)
+	}
+
+	return nil
+}
+
+func ValidateProvider(provider string) error {
+	if provider != "" && !slices.Contains(Providers, provider) {
+		return fmt.Errorf("%w %q, supported: %v", ErrUnsupportedProvider, provider, Providers)
+	}
+
+	return nil
+}
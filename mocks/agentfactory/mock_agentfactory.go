@@ -8,6 +8,7 @@ import (
 	mock "github.com/stretchr/testify/mock"
 	"github.com/yaroslav-koval/hange/domain/agent"
 	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/profile"
 )
 
 // NewMockAgentFactory creates a new instance of MockAgentFactory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -38,8 +39,8 @@ func (_m *MockAgentFactory) EXPECT() *MockAgentFactory_Expecter {
 }
 
 // CreateCommitProcessor provides a mock function for the type MockAgentFactory
-func (_mock *MockAgentFactory) CreateCommitProcessor(auth1 auth.Auth) (agent.CommitProcessor, error) {
-	ret := _mock.Called(auth1)
+func (_mock *MockAgentFactory) CreateCommitProcessor(auth1 auth.Auth, profile1 profile.Profile) (agent.CommitProcessor, error) {
+	ret := _mock.Called(auth1, profile1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateCommitProcessor")
@@ -47,18 +48,18 @@ func (_mock *MockAgentFactory) CreateCommitProcessor(auth1 auth.Auth) (agent.Com
 
 	var r0 agent.CommitProcessor
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(auth.Auth) (agent.CommitProcessor, error)); ok {
-		return returnFunc(auth1)
+	if returnFunc, ok := ret.Get(0).(func(auth.Auth, profile.Profile) (agent.CommitProcessor, error)); ok {
+		return returnFunc(auth1, profile1)
 	}
-	if returnFunc, ok := ret.Get(0).(func(auth.Auth) agent.CommitProcessor); ok {
-		r0 = returnFunc(auth1)
+	if returnFunc, ok := ret.Get(0).(func(auth.Auth, profile.Profile) agent.CommitProcessor); ok {
+		r0 = returnFunc(auth1, profile1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(agent.CommitProcessor)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(auth.Auth) error); ok {
-		r1 = returnFunc(auth1)
+	if returnFunc, ok := ret.Get(1).(func(auth.Auth, profile.Profile) error); ok {
+		r1 = returnFunc(auth1, profile1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,18 +73,24 @@ type MockAgentFactory_CreateCommitProcessor_Call struct {
 
 // CreateCommitProcessor is a helper method to define mock.On call
 //   - auth1 auth.Auth
-func (_e *MockAgentFactory_Expecter) CreateCommitProcessor(auth1 interface{}) *MockAgentFactory_CreateCommitProcessor_Call {
-	return &MockAgentFactory_CreateCommitProcessor_Call{Call: _e.mock.On("CreateCommitProcessor", auth1)}
+//   - profile1 profile.Profile
+func (_e *MockAgentFactory_Expecter) CreateCommitProcessor(auth1 interface{}, profile1 interface{}) *MockAgentFactory_CreateCommitProcessor_Call {
+	return &MockAgentFactory_CreateCommitProcessor_Call{Call: _e.mock.On("CreateCommitProcessor", auth1, profile1)}
 }
 
-func (_c *MockAgentFactory_CreateCommitProcessor_Call) Run(run func(auth1 auth.Auth)) *MockAgentFactory_CreateCommitProcessor_Call {
+func (_c *MockAgentFactory_CreateCommitProcessor_Call) Run(run func(auth1 auth.Auth, profile1 profile.Profile)) *MockAgentFactory_CreateCommitProcessor_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 auth.Auth
 		if args[0] != nil {
 			arg0 = args[0].(auth.Auth)
 		}
+		var arg1 profile.Profile
+		if args[1] != nil {
+			arg1 = args[1].(profile.Profile)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -94,14 +101,88 @@ func (_c *MockAgentFactory_CreateCommitProcessor_Call) Return(commitProcessor ag
 	return _c
 }
 
-func (_c *MockAgentFactory_CreateCommitProcessor_Call) RunAndReturn(run func(auth1 auth.Auth) (agent.CommitProcessor, error)) *MockAgentFactory_CreateCommitProcessor_Call {
+func (_c *MockAgentFactory_CreateCommitProcessor_Call) RunAndReturn(run func(auth1 auth.Auth, profile1 profile.Profile) (agent.CommitProcessor, error)) *MockAgentFactory_CreateCommitProcessor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCommitRouter provides a mock function for the type MockAgentFactory
+func (_mock *MockAgentFactory) CreateCommitRouter(profile1 profile.Profile, commitBackends []agent.CommitBackend, backendHealth agent.BackendHealth) (agent.CommitProcessor, error) {
+	ret := _mock.Called(profile1, commitBackends, backendHealth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCommitRouter")
+	}
+
+	var r0 agent.CommitProcessor
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(profile.Profile, []agent.CommitBackend, agent.BackendHealth) (agent.CommitProcessor, error)); ok {
+		return returnFunc(profile1, commitBackends, backendHealth)
+	}
+	if returnFunc, ok := ret.Get(0).(func(profile.Profile, []agent.CommitBackend, agent.BackendHealth) agent.CommitProcessor); ok {
+		r0 = returnFunc(profile1, commitBackends, backendHealth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(agent.CommitProcessor)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(profile.Profile, []agent.CommitBackend, agent.BackendHealth) error); ok {
+		r1 = returnFunc(profile1, commitBackends, backendHealth)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAgentFactory_CreateCommitRouter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCommitRouter'
+type MockAgentFactory_CreateCommitRouter_Call struct {
+	*mock.Call
+}
+
+// CreateCommitRouter is a helper method to define mock.On call
+//   - profile1 profile.Profile
+//   - commitBackends []agent.CommitBackend
+//   - backendHealth agent.BackendHealth
+func (_e *MockAgentFactory_Expecter) CreateCommitRouter(profile1 interface{}, commitBackends interface{}, backendHealth interface{}) *MockAgentFactory_CreateCommitRouter_Call {
+	return &MockAgentFactory_CreateCommitRouter_Call{Call: _e.mock.On("CreateCommitRouter", profile1, commitBackends, backendHealth)}
+}
+
+func (_c *MockAgentFactory_CreateCommitRouter_Call) Run(run func(profile1 profile.Profile, commitBackends []agent.CommitBackend, backendHealth agent.BackendHealth)) *MockAgentFactory_CreateCommitRouter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 profile.Profile
+		if args[0] != nil {
+			arg0 = args[0].(profile.Profile)
+		}
+		var arg1 []agent.CommitBackend
+		if args[1] != nil {
+			arg1 = args[1].([]agent.CommitBackend)
+		}
+		var arg2 agent.BackendHealth
+		if args[2] != nil {
+			arg2 = args[2].(agent.BackendHealth)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateCommitRouter_Call) Return(commitProcessor agent.CommitProcessor, err error) *MockAgentFactory_CreateCommitRouter_Call {
+	_c.Call.Return(commitProcessor, err)
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateCommitRouter_Call) RunAndReturn(run func(profile1 profile.Profile, commitBackends []agent.CommitBackend, backendHealth agent.BackendHealth) (agent.CommitProcessor, error)) *MockAgentFactory_CreateCommitRouter_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // CreateExplainProcessor provides a mock function for the type MockAgentFactory
-func (_mock *MockAgentFactory) CreateExplainProcessor(auth1 auth.Auth) (agent.ExplainProcessor, error) {
-	ret := _mock.Called(auth1)
+func (_mock *MockAgentFactory) CreateExplainProcessor(auth1 auth.Auth, profile1 profile.Profile, resourceJournal agent.ResourceJournal, syncStateStore agent.SyncStateStore) (agent.ExplainProcessor, error) {
+	ret := _mock.Called(auth1, profile1, resourceJournal, syncStateStore)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateExplainProcessor")
@@ -109,18 +190,18 @@ func (_mock *MockAgentFactory) CreateExplainProcessor(auth1 auth.Auth) (agent.Ex
 
 	var r0 agent.ExplainProcessor
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(auth.Auth) (agent.ExplainProcessor, error)); ok {
-		return returnFunc(auth1)
+	if returnFunc, ok := ret.Get(0).(func(auth.Auth, profile.Profile, agent.ResourceJournal, agent.SyncStateStore) (agent.ExplainProcessor, error)); ok {
+		return returnFunc(auth1, profile1, resourceJournal, syncStateStore)
 	}
-	if returnFunc, ok := ret.Get(0).(func(auth.Auth) agent.ExplainProcessor); ok {
-		r0 = returnFunc(auth1)
+	if returnFunc, ok := ret.Get(0).(func(auth.Auth, profile.Profile, agent.ResourceJournal, agent.SyncStateStore) agent.ExplainProcessor); ok {
+		r0 = returnFunc(auth1, profile1, resourceJournal, syncStateStore)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(agent.ExplainProcessor)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(auth.Auth) error); ok {
-		r1 = returnFunc(auth1)
+	if returnFunc, ok := ret.Get(1).(func(auth.Auth, profile.Profile, agent.ResourceJournal, agent.SyncStateStore) error); ok {
+		r1 = returnFunc(auth1, profile1, resourceJournal, syncStateStore)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -134,18 +215,36 @@ type MockAgentFactory_CreateExplainProcessor_Call struct {
 
 // CreateExplainProcessor is a helper method to define mock.On call
 //   - auth1 auth.Auth
-func (_e *MockAgentFactory_Expecter) CreateExplainProcessor(auth1 interface{}) *MockAgentFactory_CreateExplainProcessor_Call {
-	return &MockAgentFactory_CreateExplainProcessor_Call{Call: _e.mock.On("CreateExplainProcessor", auth1)}
+//   - profile1 profile.Profile
+//   - resourceJournal agent.ResourceJournal
+//   - syncStateStore agent.SyncStateStore
+func (_e *MockAgentFactory_Expecter) CreateExplainProcessor(auth1 interface{}, profile1 interface{}, resourceJournal interface{}, syncStateStore interface{}) *MockAgentFactory_CreateExplainProcessor_Call {
+	return &MockAgentFactory_CreateExplainProcessor_Call{Call: _e.mock.On("CreateExplainProcessor", auth1, profile1, resourceJournal, syncStateStore)}
 }
 
-func (_c *MockAgentFactory_CreateExplainProcessor_Call) Run(run func(auth1 auth.Auth)) *MockAgentFactory_CreateExplainProcessor_Call {
+func (_c *MockAgentFactory_CreateExplainProcessor_Call) Run(run func(auth1 auth.Auth, profile1 profile.Profile, resourceJournal agent.ResourceJournal, syncStateStore agent.SyncStateStore)) *MockAgentFactory_CreateExplainProcessor_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 auth.Auth
 		if args[0] != nil {
 			arg0 = args[0].(auth.Auth)
 		}
+		var arg1 profile.Profile
+		if args[1] != nil {
+			arg1 = args[1].(profile.Profile)
+		}
+		var arg2 agent.ResourceJournal
+		if args[2] != nil {
+			arg2 = args[2].(agent.ResourceJournal)
+		}
+		var arg3 agent.SyncStateStore
+		if args[3] != nil {
+			arg3 = args[3].(agent.SyncStateStore)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -156,7 +255,205 @@ func (_c *MockAgentFactory_CreateExplainProcessor_Call) Return(explainProcessor
 	return _c
 }
 
-func (_c *MockAgentFactory_CreateExplainProcessor_Call) RunAndReturn(run func(auth1 auth.Auth) (agent.ExplainProcessor, error)) *MockAgentFactory_CreateExplainProcessor_Call {
+func (_c *MockAgentFactory_CreateExplainProcessor_Call) RunAndReturn(run func(auth1 auth.Auth, profile1 profile.Profile, resourceJournal agent.ResourceJournal, syncStateStore agent.SyncStateStore) (agent.ExplainProcessor, error)) *MockAgentFactory_CreateExplainProcessor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateExplainRouter provides a mock function for the type MockAgentFactory
+func (_mock *MockAgentFactory) CreateExplainRouter(explainBackends []agent.ExplainBackend, backendHealth agent.BackendHealth) (agent.ExplainProcessor, error) {
+	ret := _mock.Called(explainBackends, backendHealth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateExplainRouter")
+	}
+
+	var r0 agent.ExplainProcessor
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func([]agent.ExplainBackend, agent.BackendHealth) (agent.ExplainProcessor, error)); ok {
+		return returnFunc(explainBackends, backendHealth)
+	}
+	if returnFunc, ok := ret.Get(0).(func([]agent.ExplainBackend, agent.BackendHealth) agent.ExplainProcessor); ok {
+		r0 = returnFunc(explainBackends, backendHealth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(agent.ExplainProcessor)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func([]agent.ExplainBackend, agent.BackendHealth) error); ok {
+		r1 = returnFunc(explainBackends, backendHealth)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAgentFactory_CreateExplainRouter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateExplainRouter'
+type MockAgentFactory_CreateExplainRouter_Call struct {
+	*mock.Call
+}
+
+// CreateExplainRouter is a helper method to define mock.On call
+//   - explainBackends []agent.ExplainBackend
+//   - backendHealth agent.BackendHealth
+func (_e *MockAgentFactory_Expecter) CreateExplainRouter(explainBackends interface{}, backendHealth interface{}) *MockAgentFactory_CreateExplainRouter_Call {
+	return &MockAgentFactory_CreateExplainRouter_Call{Call: _e.mock.On("CreateExplainRouter", explainBackends, backendHealth)}
+}
+
+func (_c *MockAgentFactory_CreateExplainRouter_Call) Run(run func(explainBackends []agent.ExplainBackend, backendHealth agent.BackendHealth)) *MockAgentFactory_CreateExplainRouter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []agent.ExplainBackend
+		if args[0] != nil {
+			arg0 = args[0].([]agent.ExplainBackend)
+		}
+		var arg1 agent.BackendHealth
+		if args[1] != nil {
+			arg1 = args[1].(agent.BackendHealth)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateExplainRouter_Call) Return(explainProcessor agent.ExplainProcessor, err error) *MockAgentFactory_CreateExplainRouter_Call {
+	_c.Call.Return(explainProcessor, err)
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateExplainRouter_Call) RunAndReturn(run func(explainBackends []agent.ExplainBackend, backendHealth agent.BackendHealth) (agent.ExplainProcessor, error)) *MockAgentFactory_CreateExplainRouter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateJanitor provides a mock function for the type MockAgentFactory
+func (_mock *MockAgentFactory) CreateJanitor(auth1 auth.Auth, profile1 profile.Profile) (agent.Janitor, error) {
+	ret := _mock.Called(auth1, profile1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJanitor")
+	}
+
+	var r0 agent.Janitor
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(auth.Auth, profile.Profile) (agent.Janitor, error)); ok {
+		return returnFunc(auth1, profile1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(auth.Auth, profile.Profile) agent.Janitor); ok {
+		r0 = returnFunc(auth1, profile1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(agent.Janitor)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(auth.Auth, profile.Profile) error); ok {
+		r1 = returnFunc(auth1, profile1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAgentFactory_CreateJanitor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateJanitor'
+type MockAgentFactory_CreateJanitor_Call struct {
+	*mock.Call
+}
+
+// CreateJanitor is a helper method to define mock.On call
+//   - auth1 auth.Auth
+//   - profile1 profile.Profile
+func (_e *MockAgentFactory_Expecter) CreateJanitor(auth1 interface{}, profile1 interface{}) *MockAgentFactory_CreateJanitor_Call {
+	return &MockAgentFactory_CreateJanitor_Call{Call: _e.mock.On("CreateJanitor", auth1, profile1)}
+}
+
+func (_c *MockAgentFactory_CreateJanitor_Call) Run(run func(auth1 auth.Auth, profile1 profile.Profile)) *MockAgentFactory_CreateJanitor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 auth.Auth
+		if args[0] != nil {
+			arg0 = args[0].(auth.Auth)
+		}
+		var arg1 profile.Profile
+		if args[1] != nil {
+			arg1 = args[1].(profile.Profile)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateJanitor_Call) Return(janitor agent.Janitor, err error) *MockAgentFactory_CreateJanitor_Call {
+	_c.Call.Return(janitor, err)
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateJanitor_Call) RunAndReturn(run func(auth1 auth.Auth, profile1 profile.Profile) (agent.Janitor, error)) *MockAgentFactory_CreateJanitor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTokenVerifier provides a mock function for the type MockAgentFactory
+func (_mock *MockAgentFactory) CreateTokenVerifier(profile1 profile.Profile) (auth.TokenVerifier, error) {
+	ret := _mock.Called(profile1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTokenVerifier")
+	}
+
+	var r0 auth.TokenVerifier
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(profile.Profile) (auth.TokenVerifier, error)); ok {
+		return returnFunc(profile1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(profile.Profile) auth.TokenVerifier); ok {
+		r0 = returnFunc(profile1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(auth.TokenVerifier)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(profile.Profile) error); ok {
+		r1 = returnFunc(profile1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAgentFactory_CreateTokenVerifier_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTokenVerifier'
+type MockAgentFactory_CreateTokenVerifier_Call struct {
+	*mock.Call
+}
+
+// CreateTokenVerifier is a helper method to define mock.On call
+//   - profile1 profile.Profile
+func (_e *MockAgentFactory_Expecter) CreateTokenVerifier(profile1 interface{}) *MockAgentFactory_CreateTokenVerifier_Call {
+	return &MockAgentFactory_CreateTokenVerifier_Call{Call: _e.mock.On("CreateTokenVerifier", profile1)}
+}
+
+func (_c *MockAgentFactory_CreateTokenVerifier_Call) Run(run func(profile1 profile.Profile)) *MockAgentFactory_CreateTokenVerifier_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 profile.Profile
+		if args[0] != nil {
+			arg0 = args[0].(profile.Profile)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateTokenVerifier_Call) Return(tokenVerifier auth.TokenVerifier, err error) *MockAgentFactory_CreateTokenVerifier_Call {
+	_c.Call.Return(tokenVerifier, err)
+	return _c
+}
+
+func (_c *MockAgentFactory_CreateTokenVerifier_Call) RunAndReturn(run func(profile1 profile.Profile) (auth.TokenVerifier, error)) *MockAgentFactory_CreateTokenVerifier_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -40,22 +40,22 @@ func (_m *MockAIAgent) EXPECT() *MockAIAgent_Expecter {
 }
 
 // CreateCommitMessage provides a mock function for the type MockAIAgent
-func (_mock *MockAIAgent) CreateCommitMessage(context1 context.Context, commitData entity.CommitData) (string, error) {
+func (_mock *MockAIAgent) CreateCommitMessage(context1 context.Context, commitData entity.CommitData) (entities.Completion, error) {
 	ret := _mock.Called(context1, commitData)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateCommitMessage")
 	}
 
-	var r0 string
+	var r0 entities.Completion
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) (string, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) (entities.Completion, error)); ok {
 		return returnFunc(context1, commitData)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) string); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) entities.Completion); ok {
 		r0 = returnFunc(context1, commitData)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(entities.Completion)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, entity.CommitData) error); ok {
 		r1 = returnFunc(context1, commitData)
@@ -95,36 +95,36 @@ func (_c *MockAIAgent_CreateCommitMessage_Call) Run(run func(context1 context.Co
 	return _c
 }
 
-func (_c *MockAIAgent_CreateCommitMessage_Call) Return(s string, err error) *MockAIAgent_CreateCommitMessage_Call {
-	_c.Call.Return(s, err)
+func (_c *MockAIAgent_CreateCommitMessage_Call) Return(completion entities.Completion, err error) *MockAIAgent_CreateCommitMessage_Call {
+	_c.Call.Return(completion, err)
 	return _c
 }
 
-func (_c *MockAIAgent_CreateCommitMessage_Call) RunAndReturn(run func(context1 context.Context, commitData entity.CommitData) (string, error)) *MockAIAgent_CreateCommitMessage_Call {
+func (_c *MockAIAgent_CreateCommitMessage_Call) RunAndReturn(run func(context1 context.Context, commitData entity.CommitData) (entities.Completion, error)) *MockAIAgent_CreateCommitMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // ExplainFiles provides a mock function for the type MockAIAgent
-func (_mock *MockAIAgent) ExplainFiles(context1 context.Context, fileCh <-chan entities.File) (string, error) {
-	ret := _mock.Called(context1, fileCh)
+func (_mock *MockAIAgent) ExplainFiles(context1 context.Context, fileCh <-chan entities.File, explainOptions entity.ExplainOptions) (entities.Completion, error) {
+	ret := _mock.Called(context1, fileCh, explainOptions)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ExplainFiles")
 	}
 
-	var r0 string
+	var r0 entities.Completion
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, <-chan entities.File) (string, error)); ok {
-		return returnFunc(context1, fileCh)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, <-chan entities.File, entity.ExplainOptions) (entities.Completion, error)); ok {
+		return returnFunc(context1, fileCh, explainOptions)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, <-chan entities.File) string); ok {
-		r0 = returnFunc(context1, fileCh)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, <-chan entities.File, entity.ExplainOptions) entities.Completion); ok {
+		r0 = returnFunc(context1, fileCh, explainOptions)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(entities.Completion)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, <-chan entities.File) error); ok {
-		r1 = returnFunc(context1, fileCh)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, <-chan entities.File, entity.ExplainOptions) error); ok {
+		r1 = returnFunc(context1, fileCh, explainOptions)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,11 +139,12 @@ type MockAIAgent_ExplainFiles_Call struct {
 // ExplainFiles is a helper method to define mock.On call
 //   - context1 context.Context
 //   - fileCh <-chan entities.File
-func (_e *MockAIAgent_Expecter) ExplainFiles(context1 interface{}, fileCh interface{}) *MockAIAgent_ExplainFiles_Call {
-	return &MockAIAgent_ExplainFiles_Call{Call: _e.mock.On("ExplainFiles", context1, fileCh)}
+//   - explainOptions entity.ExplainOptions
+func (_e *MockAIAgent_Expecter) ExplainFiles(context1 interface{}, fileCh interface{}, explainOptions interface{}) *MockAIAgent_ExplainFiles_Call {
+	return &MockAIAgent_ExplainFiles_Call{Call: _e.mock.On("ExplainFiles", context1, fileCh, explainOptions)}
 }
 
-func (_c *MockAIAgent_ExplainFiles_Call) Run(run func(context1 context.Context, fileCh <-chan entities.File)) *MockAIAgent_ExplainFiles_Call {
+func (_c *MockAIAgent_ExplainFiles_Call) Run(run func(context1 context.Context, fileCh <-chan entities.File, explainOptions entity.ExplainOptions)) *MockAIAgent_ExplainFiles_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -153,20 +154,25 @@ func (_c *MockAIAgent_ExplainFiles_Call) Run(run func(context1 context.Context,
 		if args[1] != nil {
 			arg1 = args[1].(<-chan entities.File)
 		}
+		var arg2 entity.ExplainOptions
+		if args[2] != nil {
+			arg2 = args[2].(entity.ExplainOptions)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockAIAgent_ExplainFiles_Call) Return(s string, err error) *MockAIAgent_ExplainFiles_Call {
-	_c.Call.Return(s, err)
+func (_c *MockAIAgent_ExplainFiles_Call) Return(completion entities.Completion, err error) *MockAIAgent_ExplainFiles_Call {
+	_c.Call.Return(completion, err)
 	return _c
 }
 
-func (_c *MockAIAgent_ExplainFiles_Call) RunAndReturn(run func(context1 context.Context, fileCh <-chan entities.File) (string, error)) *MockAIAgent_ExplainFiles_Call {
+func (_c *MockAIAgent_ExplainFiles_Call) RunAndReturn(run func(context1 context.Context, fileCh <-chan entities.File, explainOptions entity.ExplainOptions) (entities.Completion, error)) *MockAIAgent_ExplainFiles_Call {
 	_c.Call.Return(run)
 	return _c
 }
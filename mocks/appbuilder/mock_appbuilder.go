@@ -11,6 +11,7 @@ import (
 	"github.com/yaroslav-koval/hange/domain/config"
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
 	"github.com/yaroslav-koval/hange/domain/git"
+	"github.com/yaroslav-koval/hange/domain/profile"
 )
 
 // NewMockAppBuilder creates a new instance of MockAppBuilder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -150,6 +151,61 @@ func (_c *MockAppBuilder_GetAuth_Call) RunAndReturn(run func() (auth.Auth, error
 	return _c
 }
 
+// GetConfigMigrator provides a mock function for the type MockAppBuilder
+func (_mock *MockAppBuilder) GetConfigMigrator() (config.Migrator, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConfigMigrator")
+	}
+
+	var r0 config.Migrator
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (config.Migrator, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() config.Migrator); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(config.Migrator)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppBuilder_GetConfigMigrator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetConfigMigrator'
+type MockAppBuilder_GetConfigMigrator_Call struct {
+	*mock.Call
+}
+
+// GetConfigMigrator is a helper method to define mock.On call
+func (_e *MockAppBuilder_Expecter) GetConfigMigrator() *MockAppBuilder_GetConfigMigrator_Call {
+	return &MockAppBuilder_GetConfigMigrator_Call{Call: _e.mock.On("GetConfigMigrator")}
+}
+
+func (_c *MockAppBuilder_GetConfigMigrator_Call) Run(run func()) *MockAppBuilder_GetConfigMigrator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppBuilder_GetConfigMigrator_Call) Return(migrator config.Migrator, err error) *MockAppBuilder_GetConfigMigrator_Call {
+	_c.Call.Return(migrator, err)
+	return _c
+}
+
+func (_c *MockAppBuilder_GetConfigMigrator_Call) RunAndReturn(run func() (config.Migrator, error)) *MockAppBuilder_GetConfigMigrator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetConfigurator provides a mock function for the type MockAppBuilder
 func (_mock *MockAppBuilder) GetConfigurator() (config.Configurator, error) {
 	ret := _mock.Called()
@@ -314,3 +370,221 @@ func (_c *MockAppBuilder_GetGitChangesProvider_Call) RunAndReturn(run func() (gi
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetJanitor provides a mock function for the type MockAppBuilder
+func (_mock *MockAppBuilder) GetJanitor() (agent.Janitor, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJanitor")
+	}
+
+	var r0 agent.Janitor
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (agent.Janitor, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() agent.Janitor); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(agent.Janitor)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppBuilder_GetJanitor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJanitor'
+type MockAppBuilder_GetJanitor_Call struct {
+	*mock.Call
+}
+
+// GetJanitor is a helper method to define mock.On call
+func (_e *MockAppBuilder_Expecter) GetJanitor() *MockAppBuilder_GetJanitor_Call {
+	return &MockAppBuilder_GetJanitor_Call{Call: _e.mock.On("GetJanitor")}
+}
+
+func (_c *MockAppBuilder_GetJanitor_Call) Run(run func()) *MockAppBuilder_GetJanitor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppBuilder_GetJanitor_Call) Return(janitor agent.Janitor, err error) *MockAppBuilder_GetJanitor_Call {
+	_c.Call.Return(janitor, err)
+	return _c
+}
+
+func (_c *MockAppBuilder_GetJanitor_Call) RunAndReturn(run func() (agent.Janitor, error)) *MockAppBuilder_GetJanitor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProfile provides a mock function for the type MockAppBuilder
+func (_mock *MockAppBuilder) GetProfile() (profile.Profile, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 profile.Profile
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (profile.Profile, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() profile.Profile); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(profile.Profile)
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppBuilder_GetProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProfile'
+type MockAppBuilder_GetProfile_Call struct {
+	*mock.Call
+}
+
+// GetProfile is a helper method to define mock.On call
+func (_e *MockAppBuilder_Expecter) GetProfile() *MockAppBuilder_GetProfile_Call {
+	return &MockAppBuilder_GetProfile_Call{Call: _e.mock.On("GetProfile")}
+}
+
+func (_c *MockAppBuilder_GetProfile_Call) Run(run func()) *MockAppBuilder_GetProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppBuilder_GetProfile_Call) Return(profile1 profile.Profile, err error) *MockAppBuilder_GetProfile_Call {
+	_c.Call.Return(profile1, err)
+	return _c
+}
+
+func (_c *MockAppBuilder_GetProfile_Call) RunAndReturn(run func() (profile.Profile, error)) *MockAppBuilder_GetProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProfileManager provides a mock function for the type MockAppBuilder
+func (_mock *MockAppBuilder) GetProfileManager() (profile.ProfileManager, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfileManager")
+	}
+
+	var r0 profile.ProfileManager
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (profile.ProfileManager, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() profile.ProfileManager); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(profile.ProfileManager)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppBuilder_GetProfileManager_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProfileManager'
+type MockAppBuilder_GetProfileManager_Call struct {
+	*mock.Call
+}
+
+// GetProfileManager is a helper method to define mock.On call
+func (_e *MockAppBuilder_Expecter) GetProfileManager() *MockAppBuilder_GetProfileManager_Call {
+	return &MockAppBuilder_GetProfileManager_Call{Call: _e.mock.On("GetProfileManager")}
+}
+
+func (_c *MockAppBuilder_GetProfileManager_Call) Run(run func()) *MockAppBuilder_GetProfileManager_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppBuilder_GetProfileManager_Call) Return(profileManager profile.ProfileManager, err error) *MockAppBuilder_GetProfileManager_Call {
+	_c.Call.Return(profileManager, err)
+	return _c
+}
+
+func (_c *MockAppBuilder_GetProfileManager_Call) RunAndReturn(run func() (profile.ProfileManager, error)) *MockAppBuilder_GetProfileManager_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetResourceJournal provides a mock function for the type MockAppBuilder
+func (_mock *MockAppBuilder) GetResourceJournal() (agent.ResourceJournal, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResourceJournal")
+	}
+
+	var r0 agent.ResourceJournal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (agent.ResourceJournal, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() agent.ResourceJournal); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(agent.ResourceJournal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppBuilder_GetResourceJournal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResourceJournal'
+type MockAppBuilder_GetResourceJournal_Call struct {
+	*mock.Call
+}
+
+// GetResourceJournal is a helper method to define mock.On call
+func (_e *MockAppBuilder_Expecter) GetResourceJournal() *MockAppBuilder_GetResourceJournal_Call {
+	return &MockAppBuilder_GetResourceJournal_Call{Call: _e.mock.On("GetResourceJournal")}
+}
+
+func (_c *MockAppBuilder_GetResourceJournal_Call) Run(run func()) *MockAppBuilder_GetResourceJournal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppBuilder_GetResourceJournal_Call) Return(resourceJournal agent.ResourceJournal, err error) *MockAppBuilder_GetResourceJournal_Call {
+	_c.Call.Return(resourceJournal, err)
+	return _c
+}
+
+func (_c *MockAppBuilder_GetResourceJournal_Call) RunAndReturn(run func() (agent.ResourceJournal, error)) *MockAppBuilder_GetResourceJournal_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -6,11 +6,13 @@ package appfactory_mock
 
 import (
 	mock "github.com/stretchr/testify/mock"
+	"github.com/yaroslav-koval/hange/domain/agent"
 	"github.com/yaroslav-koval/hange/domain/auth"
 	"github.com/yaroslav-koval/hange/domain/config"
 	"github.com/yaroslav-koval/hange/domain/crypt"
 	"github.com/yaroslav-koval/hange/domain/fileprovider"
 	"github.com/yaroslav-koval/hange/domain/git"
+	"github.com/yaroslav-koval/hange/domain/profile"
 )
 
 // NewMockAppFactory creates a new instance of MockAppFactory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -40,79 +42,86 @@ func (_m *MockAppFactory) EXPECT() *MockAppFactory_Expecter {
 	return &MockAppFactory_Expecter{mock: &_m.Mock}
 }
 
-// CreateBase64Decryptor provides a mock function for the type MockAppFactory
-func (_mock *MockAppFactory) CreateBase64Decryptor() (crypt.Decryptor, error) {
-	ret := _mock.Called()
+// CreateBackendHealth provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateBackendHealth(configurator config.Configurator) (agent.BackendHealth, error) {
+	ret := _mock.Called(configurator)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateBase64Decryptor")
+		panic("no return value specified for CreateBackendHealth")
 	}
 
-	var r0 crypt.Decryptor
+	var r0 agent.BackendHealth
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (crypt.Decryptor, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator) (agent.BackendHealth, error)); ok {
+		return returnFunc(configurator)
 	}
-	if returnFunc, ok := ret.Get(0).(func() crypt.Decryptor); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator) agent.BackendHealth); ok {
+		r0 = returnFunc(configurator)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(crypt.Decryptor)
+			r0 = ret.Get(0).(agent.BackendHealth)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(config.Configurator) error); ok {
+		r1 = returnFunc(configurator)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockAppFactory_CreateBase64Decryptor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBase64Decryptor'
-type MockAppFactory_CreateBase64Decryptor_Call struct {
+// MockAppFactory_CreateBackendHealth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBackendHealth'
+type MockAppFactory_CreateBackendHealth_Call struct {
 	*mock.Call
 }
 
-// CreateBase64Decryptor is a helper method to define mock.On call
-func (_e *MockAppFactory_Expecter) CreateBase64Decryptor() *MockAppFactory_CreateBase64Decryptor_Call {
-	return &MockAppFactory_CreateBase64Decryptor_Call{Call: _e.mock.On("CreateBase64Decryptor")}
+// CreateBackendHealth is a helper method to define mock.On call
+//   - configurator config.Configurator
+func (_e *MockAppFactory_Expecter) CreateBackendHealth(configurator interface{}) *MockAppFactory_CreateBackendHealth_Call {
+	return &MockAppFactory_CreateBackendHealth_Call{Call: _e.mock.On("CreateBackendHealth", configurator)}
 }
 
-func (_c *MockAppFactory_CreateBase64Decryptor_Call) Run(run func()) *MockAppFactory_CreateBase64Decryptor_Call {
+func (_c *MockAppFactory_CreateBackendHealth_Call) Run(run func(configurator config.Configurator)) *MockAppFactory_CreateBackendHealth_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 config.Configurator
+		if args[0] != nil {
+			arg0 = args[0].(config.Configurator)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
 
-func (_c *MockAppFactory_CreateBase64Decryptor_Call) Return(decryptor crypt.Decryptor, err error) *MockAppFactory_CreateBase64Decryptor_Call {
-	_c.Call.Return(decryptor, err)
+func (_c *MockAppFactory_CreateBackendHealth_Call) Return(backendHealth agent.BackendHealth, err error) *MockAppFactory_CreateBackendHealth_Call {
+	_c.Call.Return(backendHealth, err)
 	return _c
 }
 
-func (_c *MockAppFactory_CreateBase64Decryptor_Call) RunAndReturn(run func() (crypt.Decryptor, error)) *MockAppFactory_CreateBase64Decryptor_Call {
+func (_c *MockAppFactory_CreateBackendHealth_Call) RunAndReturn(run func(configurator config.Configurator) (agent.BackendHealth, error)) *MockAppFactory_CreateBackendHealth_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateBase64Encryptor provides a mock function for the type MockAppFactory
-func (_mock *MockAppFactory) CreateBase64Encryptor() (crypt.Encryptor, error) {
+// CreateConfigMigrator provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateConfigMigrator() (config.Migrator, error) {
 	ret := _mock.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateBase64Encryptor")
+		panic("no return value specified for CreateConfigMigrator")
 	}
 
-	var r0 crypt.Encryptor
+	var r0 config.Migrator
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (crypt.Encryptor, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func() (config.Migrator, error)); ok {
 		return returnFunc()
 	}
-	if returnFunc, ok := ret.Get(0).(func() crypt.Encryptor); ok {
+	if returnFunc, ok := ret.Get(0).(func() config.Migrator); ok {
 		r0 = returnFunc()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(crypt.Encryptor)
+			r0 = ret.Get(0).(config.Migrator)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func() error); ok {
@@ -123,29 +132,29 @@ func (_mock *MockAppFactory) CreateBase64Encryptor() (crypt.Encryptor, error) {
 	return r0, r1
 }
 
-// MockAppFactory_CreateBase64Encryptor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBase64Encryptor'
-type MockAppFactory_CreateBase64Encryptor_Call struct {
+// MockAppFactory_CreateConfigMigrator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateConfigMigrator'
+type MockAppFactory_CreateConfigMigrator_Call struct {
 	*mock.Call
 }
 
-// CreateBase64Encryptor is a helper method to define mock.On call
-func (_e *MockAppFactory_Expecter) CreateBase64Encryptor() *MockAppFactory_CreateBase64Encryptor_Call {
-	return &MockAppFactory_CreateBase64Encryptor_Call{Call: _e.mock.On("CreateBase64Encryptor")}
+// CreateConfigMigrator is a helper method to define mock.On call
+func (_e *MockAppFactory_Expecter) CreateConfigMigrator() *MockAppFactory_CreateConfigMigrator_Call {
+	return &MockAppFactory_CreateConfigMigrator_Call{Call: _e.mock.On("CreateConfigMigrator")}
 }
 
-func (_c *MockAppFactory_CreateBase64Encryptor_Call) Run(run func()) *MockAppFactory_CreateBase64Encryptor_Call {
+func (_c *MockAppFactory_CreateConfigMigrator_Call) Run(run func()) *MockAppFactory_CreateConfigMigrator_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run()
 	})
 	return _c
 }
 
-func (_c *MockAppFactory_CreateBase64Encryptor_Call) Return(encryptor crypt.Encryptor, err error) *MockAppFactory_CreateBase64Encryptor_Call {
-	_c.Call.Return(encryptor, err)
+func (_c *MockAppFactory_CreateConfigMigrator_Call) Return(migrator config.Migrator, err error) *MockAppFactory_CreateConfigMigrator_Call {
+	_c.Call.Return(migrator, err)
 	return _c
 }
 
-func (_c *MockAppFactory_CreateBase64Encryptor_Call) RunAndReturn(run func() (crypt.Encryptor, error)) *MockAppFactory_CreateBase64Encryptor_Call {
+func (_c *MockAppFactory_CreateConfigMigrator_Call) RunAndReturn(run func() (config.Migrator, error)) *MockAppFactory_CreateConfigMigrator_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -205,6 +214,123 @@ func (_c *MockAppFactory_CreateConfigurator_Call) RunAndReturn(run func() (confi
 	return _c
 }
 
+// CreateDecryptor provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateDecryptor(profile1 profile.Profile) (crypt.Decryptor, error) {
+	ret := _mock.Called(profile1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDecryptor")
+	}
+
+	var r0 crypt.Decryptor
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(profile.Profile) (crypt.Decryptor, error)); ok {
+		return returnFunc(profile1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(profile.Profile) crypt.Decryptor); ok {
+		r0 = returnFunc(profile1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(crypt.Decryptor)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(profile.Profile) error); ok {
+		r1 = returnFunc(profile1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppFactory_CreateDecryptor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDecryptor'
+type MockAppFactory_CreateDecryptor_Call struct {
+	*mock.Call
+}
+
+// CreateDecryptor is a helper method to define mock.On call
+//   - profile1 profile.Profile
+func (_e *MockAppFactory_Expecter) CreateDecryptor(profile1 interface{}) *MockAppFactory_CreateDecryptor_Call {
+	return &MockAppFactory_CreateDecryptor_Call{Call: _e.mock.On("CreateDecryptor", profile1)}
+}
+
+func (_c *MockAppFactory_CreateDecryptor_Call) Run(run func(profile1 profile.Profile)) *MockAppFactory_CreateDecryptor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 profile.Profile
+		if args[0] != nil {
+			arg0 = args[0].(profile.Profile)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAppFactory_CreateDecryptor_Call) Return(decryptor crypt.Decryptor, err error) *MockAppFactory_CreateDecryptor_Call {
+	_c.Call.Return(decryptor, err)
+	return _c
+}
+
+func (_c *MockAppFactory_CreateDecryptor_Call) RunAndReturn(run func(profile1 profile.Profile) (crypt.Decryptor, error)) *MockAppFactory_CreateDecryptor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateEncryptor provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateEncryptor() (crypt.Encryptor, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEncryptor")
+	}
+
+	var r0 crypt.Encryptor
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (crypt.Encryptor, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() crypt.Encryptor); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(crypt.Encryptor)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppFactory_CreateEncryptor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEncryptor'
+type MockAppFactory_CreateEncryptor_Call struct {
+	*mock.Call
+}
+
+// CreateEncryptor is a helper method to define mock.On call
+func (_e *MockAppFactory_Expecter) CreateEncryptor() *MockAppFactory_CreateEncryptor_Call {
+	return &MockAppFactory_CreateEncryptor_Call{Call: _e.mock.On("CreateEncryptor")}
+}
+
+func (_c *MockAppFactory_CreateEncryptor_Call) Run(run func()) *MockAppFactory_CreateEncryptor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAppFactory_CreateEncryptor_Call) Return(encryptor crypt.Encryptor, err error) *MockAppFactory_CreateEncryptor_Call {
+	_c.Call.Return(encryptor, err)
+	return _c
+}
+
+func (_c *MockAppFactory_CreateEncryptor_Call) RunAndReturn(run func() (crypt.Encryptor, error)) *MockAppFactory_CreateEncryptor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateFileProvider provides a mock function for the type MockAppFactory
 func (_mock *MockAppFactory) CreateFileProvider() (fileprovider.FileProvider, error) {
 	ret := _mock.Called()
@@ -315,24 +441,24 @@ func (_c *MockAppFactory_CreateGitChangesProvider_Call) RunAndReturn(run func()
 	return _c
 }
 
-// CreateTokenFetcher provides a mock function for the type MockAppFactory
-func (_mock *MockAppFactory) CreateTokenFetcher(configurator config.Configurator) (auth.TokenFetcher, error) {
+// CreateProfileManager provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateProfileManager(configurator config.Configurator) (profile.ProfileManager, error) {
 	ret := _mock.Called(configurator)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateTokenFetcher")
+		panic("no return value specified for CreateProfileManager")
 	}
 
-	var r0 auth.TokenFetcher
+	var r0 profile.ProfileManager
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(config.Configurator) (auth.TokenFetcher, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator) (profile.ProfileManager, error)); ok {
 		return returnFunc(configurator)
 	}
-	if returnFunc, ok := ret.Get(0).(func(config.Configurator) auth.TokenFetcher); ok {
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator) profile.ProfileManager); ok {
 		r0 = returnFunc(configurator)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(auth.TokenFetcher)
+			r0 = ret.Get(0).(profile.ProfileManager)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(config.Configurator) error); ok {
@@ -343,6 +469,204 @@ func (_mock *MockAppFactory) CreateTokenFetcher(configurator config.Configurator
 	return r0, r1
 }
 
+// MockAppFactory_CreateProfileManager_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateProfileManager'
+type MockAppFactory_CreateProfileManager_Call struct {
+	*mock.Call
+}
+
+// CreateProfileManager is a helper method to define mock.On call
+//   - configurator config.Configurator
+func (_e *MockAppFactory_Expecter) CreateProfileManager(configurator interface{}) *MockAppFactory_CreateProfileManager_Call {
+	return &MockAppFactory_CreateProfileManager_Call{Call: _e.mock.On("CreateProfileManager", configurator)}
+}
+
+func (_c *MockAppFactory_CreateProfileManager_Call) Run(run func(configurator config.Configurator)) *MockAppFactory_CreateProfileManager_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 config.Configurator
+		if args[0] != nil {
+			arg0 = args[0].(config.Configurator)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAppFactory_CreateProfileManager_Call) Return(profileManager profile.ProfileManager, err error) *MockAppFactory_CreateProfileManager_Call {
+	_c.Call.Return(profileManager, err)
+	return _c
+}
+
+func (_c *MockAppFactory_CreateProfileManager_Call) RunAndReturn(run func(configurator config.Configurator) (profile.ProfileManager, error)) *MockAppFactory_CreateProfileManager_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateResourceJournal provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateResourceJournal(configurator config.Configurator, profile1 profile.Profile) (agent.ResourceJournal, error) {
+	ret := _mock.Called(configurator, profile1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateResourceJournal")
+	}
+
+	var r0 agent.ResourceJournal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) (agent.ResourceJournal, error)); ok {
+		return returnFunc(configurator, profile1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) agent.ResourceJournal); ok {
+		r0 = returnFunc(configurator, profile1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(agent.ResourceJournal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(config.Configurator, profile.Profile) error); ok {
+		r1 = returnFunc(configurator, profile1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppFactory_CreateResourceJournal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateResourceJournal'
+type MockAppFactory_CreateResourceJournal_Call struct {
+	*mock.Call
+}
+
+// CreateResourceJournal is a helper method to define mock.On call
+//   - configurator config.Configurator
+//   - profile1 profile.Profile
+func (_e *MockAppFactory_Expecter) CreateResourceJournal(configurator interface{}, profile1 interface{}) *MockAppFactory_CreateResourceJournal_Call {
+	return &MockAppFactory_CreateResourceJournal_Call{Call: _e.mock.On("CreateResourceJournal", configurator, profile1)}
+}
+
+func (_c *MockAppFactory_CreateResourceJournal_Call) Run(run func(configurator config.Configurator, profile1 profile.Profile)) *MockAppFactory_CreateResourceJournal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 config.Configurator
+		if args[0] != nil {
+			arg0 = args[0].(config.Configurator)
+		}
+		var arg1 profile.Profile
+		if args[1] != nil {
+			arg1 = args[1].(profile.Profile)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAppFactory_CreateResourceJournal_Call) Return(resourceJournal agent.ResourceJournal, err error) *MockAppFactory_CreateResourceJournal_Call {
+	_c.Call.Return(resourceJournal, err)
+	return _c
+}
+
+func (_c *MockAppFactory_CreateResourceJournal_Call) RunAndReturn(run func(configurator config.Configurator, profile1 profile.Profile) (agent.ResourceJournal, error)) *MockAppFactory_CreateResourceJournal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSyncStateStore provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateSyncStateStore(configurator config.Configurator, profile1 profile.Profile) (agent.SyncStateStore, error) {
+	ret := _mock.Called(configurator, profile1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSyncStateStore")
+	}
+
+	var r0 agent.SyncStateStore
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) (agent.SyncStateStore, error)); ok {
+		return returnFunc(configurator, profile1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) agent.SyncStateStore); ok {
+		r0 = returnFunc(configurator, profile1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(agent.SyncStateStore)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(config.Configurator, profile.Profile) error); ok {
+		r1 = returnFunc(configurator, profile1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppFactory_CreateSyncStateStore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSyncStateStore'
+type MockAppFactory_CreateSyncStateStore_Call struct {
+	*mock.Call
+}
+
+// CreateSyncStateStore is a helper method to define mock.On call
+//   - configurator config.Configurator
+//   - profile1 profile.Profile
+func (_e *MockAppFactory_Expecter) CreateSyncStateStore(configurator interface{}, profile1 interface{}) *MockAppFactory_CreateSyncStateStore_Call {
+	return &MockAppFactory_CreateSyncStateStore_Call{Call: _e.mock.On("CreateSyncStateStore", configurator, profile1)}
+}
+
+func (_c *MockAppFactory_CreateSyncStateStore_Call) Run(run func(configurator config.Configurator, profile1 profile.Profile)) *MockAppFactory_CreateSyncStateStore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 config.Configurator
+		if args[0] != nil {
+			arg0 = args[0].(config.Configurator)
+		}
+		var arg1 profile.Profile
+		if args[1] != nil {
+			arg1 = args[1].(profile.Profile)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAppFactory_CreateSyncStateStore_Call) Return(syncStateStore agent.SyncStateStore, err error) *MockAppFactory_CreateSyncStateStore_Call {
+	_c.Call.Return(syncStateStore, err)
+	return _c
+}
+
+func (_c *MockAppFactory_CreateSyncStateStore_Call) RunAndReturn(run func(configurator config.Configurator, profile1 profile.Profile) (agent.SyncStateStore, error)) *MockAppFactory_CreateSyncStateStore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTokenFetcher provides a mock function for the type MockAppFactory
+func (_mock *MockAppFactory) CreateTokenFetcher(configurator config.Configurator, profile1 profile.Profile) (auth.TokenFetcher, error) {
+	ret := _mock.Called(configurator, profile1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTokenFetcher")
+	}
+
+	var r0 auth.TokenFetcher
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) (auth.TokenFetcher, error)); ok {
+		return returnFunc(configurator, profile1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) auth.TokenFetcher); ok {
+		r0 = returnFunc(configurator, profile1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(auth.TokenFetcher)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(config.Configurator, profile.Profile) error); ok {
+		r1 = returnFunc(configurator, profile1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
 // MockAppFactory_CreateTokenFetcher_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTokenFetcher'
 type MockAppFactory_CreateTokenFetcher_Call struct {
 	*mock.Call
@@ -350,18 +674,24 @@ type MockAppFactory_CreateTokenFetcher_Call struct {
 
 // CreateTokenFetcher is a helper method to define mock.On call
 //   - configurator config.Configurator
-func (_e *MockAppFactory_Expecter) CreateTokenFetcher(configurator interface{}) *MockAppFactory_CreateTokenFetcher_Call {
-	return &MockAppFactory_CreateTokenFetcher_Call{Call: _e.mock.On("CreateTokenFetcher", configurator)}
+//   - profile1 profile.Profile
+func (_e *MockAppFactory_Expecter) CreateTokenFetcher(configurator interface{}, profile1 interface{}) *MockAppFactory_CreateTokenFetcher_Call {
+	return &MockAppFactory_CreateTokenFetcher_Call{Call: _e.mock.On("CreateTokenFetcher", configurator, profile1)}
 }
 
-func (_c *MockAppFactory_CreateTokenFetcher_Call) Run(run func(configurator config.Configurator)) *MockAppFactory_CreateTokenFetcher_Call {
+func (_c *MockAppFactory_CreateTokenFetcher_Call) Run(run func(configurator config.Configurator, profile1 profile.Profile)) *MockAppFactory_CreateTokenFetcher_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 config.Configurator
 		if args[0] != nil {
 			arg0 = args[0].(config.Configurator)
 		}
+		var arg1 profile.Profile
+		if args[1] != nil {
+			arg1 = args[1].(profile.Profile)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -372,14 +702,14 @@ func (_c *MockAppFactory_CreateTokenFetcher_Call) Return(tokenFetcher auth.Token
 	return _c
 }
 
-func (_c *MockAppFactory_CreateTokenFetcher_Call) RunAndReturn(run func(configurator config.Configurator) (auth.TokenFetcher, error)) *MockAppFactory_CreateTokenFetcher_Call {
+func (_c *MockAppFactory_CreateTokenFetcher_Call) RunAndReturn(run func(configurator config.Configurator, profile1 profile.Profile) (auth.TokenFetcher, error)) *MockAppFactory_CreateTokenFetcher_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // CreateTokenStorer provides a mock function for the type MockAppFactory
-func (_mock *MockAppFactory) CreateTokenStorer(configurator config.Configurator) (auth.TokenStorer, error) {
-	ret := _mock.Called(configurator)
+func (_mock *MockAppFactory) CreateTokenStorer(configurator config.Configurator, profile1 profile.Profile) (auth.TokenStorer, error) {
+	ret := _mock.Called(configurator, profile1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTokenStorer")
@@ -387,18 +717,18 @@ func (_mock *MockAppFactory) CreateTokenStorer(configurator config.Configurator)
 
 	var r0 auth.TokenStorer
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(config.Configurator) (auth.TokenStorer, error)); ok {
-		return returnFunc(configurator)
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) (auth.TokenStorer, error)); ok {
+		return returnFunc(configurator, profile1)
 	}
-	if returnFunc, ok := ret.Get(0).(func(config.Configurator) auth.TokenStorer); ok {
-		r0 = returnFunc(configurator)
+	if returnFunc, ok := ret.Get(0).(func(config.Configurator, profile.Profile) auth.TokenStorer); ok {
+		r0 = returnFunc(configurator, profile1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(auth.TokenStorer)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(config.Configurator) error); ok {
-		r1 = returnFunc(configurator)
+	if returnFunc, ok := ret.Get(1).(func(config.Configurator, profile.Profile) error); ok {
+		r1 = returnFunc(configurator, profile1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -412,18 +742,24 @@ type MockAppFactory_CreateTokenStorer_Call struct {
 
 // CreateTokenStorer is a helper method to define mock.On call
 //   - configurator config.Configurator
-func (_e *MockAppFactory_Expecter) CreateTokenStorer(configurator interface{}) *MockAppFactory_CreateTokenStorer_Call {
-	return &MockAppFactory_CreateTokenStorer_Call{Call: _e.mock.On("CreateTokenStorer", configurator)}
+//   - profile1 profile.Profile
+func (_e *MockAppFactory_Expecter) CreateTokenStorer(configurator interface{}, profile1 interface{}) *MockAppFactory_CreateTokenStorer_Call {
+	return &MockAppFactory_CreateTokenStorer_Call{Call: _e.mock.On("CreateTokenStorer", configurator, profile1)}
 }
 
-func (_c *MockAppFactory_CreateTokenStorer_Call) Run(run func(configurator config.Configurator)) *MockAppFactory_CreateTokenStorer_Call {
+func (_c *MockAppFactory_CreateTokenStorer_Call) Run(run func(configurator config.Configurator, profile1 profile.Profile)) *MockAppFactory_CreateTokenStorer_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 config.Configurator
 		if args[0] != nil {
 			arg0 = args[0].(config.Configurator)
 		}
+		var arg1 profile.Profile
+		if args[1] != nil {
+			arg1 = args[1].(profile.Profile)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -434,7 +770,7 @@ func (_c *MockAppFactory_CreateTokenStorer_Call) Return(tokenStorer auth.TokenSt
 	return _c
 }
 
-func (_c *MockAppFactory_CreateTokenStorer_Call) RunAndReturn(run func(configurator config.Configurator) (auth.TokenStorer, error)) *MockAppFactory_CreateTokenStorer_Call {
+func (_c *MockAppFactory_CreateTokenStorer_Call) RunAndReturn(run func(configurator config.Configurator, profile1 profile.Profile) (auth.TokenStorer, error)) *MockAppFactory_CreateTokenStorer_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,8 @@
 package auth_mock
 
 import (
+	"context"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -35,6 +37,50 @@ func (_m *MockAuth) EXPECT() *MockAuth_Expecter {
 	return &MockAuth_Expecter{mock: &_m.Mock}
 }
 
+// DeleteToken provides a mock function for the type MockAuth
+func (_mock *MockAuth) DeleteToken() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuth_DeleteToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteToken'
+type MockAuth_DeleteToken_Call struct {
+	*mock.Call
+}
+
+// DeleteToken is a helper method to define mock.On call
+func (_e *MockAuth_Expecter) DeleteToken() *MockAuth_DeleteToken_Call {
+	return &MockAuth_DeleteToken_Call{Call: _e.mock.On("DeleteToken")}
+}
+
+func (_c *MockAuth_DeleteToken_Call) Run(run func()) *MockAuth_DeleteToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAuth_DeleteToken_Call) Return(err error) *MockAuth_DeleteToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuth_DeleteToken_Call) RunAndReturn(run func() error) *MockAuth_DeleteToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetToken provides a mock function for the type MockAuth
 func (_mock *MockAuth) GetToken() (string, error) {
 	ret := _mock.Called()
@@ -138,3 +184,98 @@ func (_c *MockAuth_SaveToken_Call) RunAndReturn(run func(authToken string) error
 	_c.Call.Return(run)
 	return _c
 }
+
+// TokenSource provides a mock function for the type MockAuth
+func (_mock *MockAuth) TokenSource() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for TokenSource")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockAuth_TokenSource_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TokenSource'
+type MockAuth_TokenSource_Call struct {
+	*mock.Call
+}
+
+// TokenSource is a helper method to define mock.On call
+func (_e *MockAuth_Expecter) TokenSource() *MockAuth_TokenSource_Call {
+	return &MockAuth_TokenSource_Call{Call: _e.mock.On("TokenSource")}
+}
+
+func (_c *MockAuth_TokenSource_Call) Run(run func()) *MockAuth_TokenSource_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAuth_TokenSource_Call) Return(s string) *MockAuth_TokenSource_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockAuth_TokenSource_Call) RunAndReturn(run func() string) *MockAuth_TokenSource_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyToken provides a mock function for the type MockAuth
+func (_mock *MockAuth) VerifyToken(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuth_VerifyToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyToken'
+type MockAuth_VerifyToken_Call struct {
+	*mock.Call
+}
+
+// VerifyToken is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAuth_Expecter) VerifyToken(ctx interface{}) *MockAuth_VerifyToken_Call {
+	return &MockAuth_VerifyToken_Call{Call: _e.mock.On("VerifyToken", ctx)}
+}
+
+func (_c *MockAuth_VerifyToken_Call) Run(run func(ctx context.Context)) *MockAuth_VerifyToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuth_VerifyToken_Call) Return(err error) *MockAuth_VerifyToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuth_VerifyToken_Call) RunAndReturn(run func(ctx context.Context) error) *MockAuth_VerifyToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
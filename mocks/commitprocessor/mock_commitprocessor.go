@@ -9,6 +9,7 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/entities"
 )
 
 // NewMockCommitProcessor creates a new instance of MockCommitProcessor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -39,22 +40,22 @@ func (_m *MockCommitProcessor) EXPECT() *MockCommitProcessor_Expecter {
 }
 
 // GenCommitMessage provides a mock function for the type MockCommitProcessor
-func (_mock *MockCommitProcessor) GenCommitMessage(context1 context.Context, commitData entity.CommitData) (string, error) {
+func (_mock *MockCommitProcessor) GenCommitMessage(context1 context.Context, commitData entity.CommitData) (entities.Completion, error) {
 	ret := _mock.Called(context1, commitData)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenCommitMessage")
 	}
 
-	var r0 string
+	var r0 entities.Completion
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) (string, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) (entities.Completion, error)); ok {
 		return returnFunc(context1, commitData)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) string); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.CommitData) entities.Completion); ok {
 		r0 = returnFunc(context1, commitData)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(entities.Completion)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, entity.CommitData) error); ok {
 		r1 = returnFunc(context1, commitData)
@@ -94,12 +95,12 @@ func (_c *MockCommitProcessor_GenCommitMessage_Call) Run(run func(context1 conte
 	return _c
 }
 
-func (_c *MockCommitProcessor_GenCommitMessage_Call) Return(s string, err error) *MockCommitProcessor_GenCommitMessage_Call {
-	_c.Call.Return(s, err)
+func (_c *MockCommitProcessor_GenCommitMessage_Call) Return(completion entities.Completion, err error) *MockCommitProcessor_GenCommitMessage_Call {
+	_c.Call.Return(completion, err)
 	return _c
 }
 
-func (_c *MockCommitProcessor_GenCommitMessage_Call) RunAndReturn(run func(context1 context.Context, commitData entity.CommitData) (string, error)) *MockCommitProcessor_GenCommitMessage_Call {
+func (_c *MockCommitProcessor_GenCommitMessage_Call) RunAndReturn(run func(context1 context.Context, commitData entity.CommitData) (entities.Completion, error)) *MockCommitProcessor_GenCommitMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,7 @@ package configurator_mock
 
 import (
 	mock "github.com/stretchr/testify/mock"
+	"github.com/yaroslav-koval/hange/domain/config"
 )
 
 // NewMockConfigurator creates a new instance of MockConfigurator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -35,6 +36,198 @@ func (_m *MockConfigurator) EXPECT() *MockConfigurator_Expecter {
 	return &MockConfigurator_Expecter{mock: &_m.Mock}
 }
 
+// DeleteField provides a mock function for the type MockConfigurator
+func (_mock *MockConfigurator) DeleteField(field string) error {
+	ret := _mock.Called(field)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteField")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(field)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockConfigurator_DeleteField_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteField'
+type MockConfigurator_DeleteField_Call struct {
+	*mock.Call
+}
+
+// DeleteField is a helper method to define mock.On call
+//   - field string
+func (_e *MockConfigurator_Expecter) DeleteField(field interface{}) *MockConfigurator_DeleteField_Call {
+	return &MockConfigurator_DeleteField_Call{Call: _e.mock.On("DeleteField", field)}
+}
+
+func (_c *MockConfigurator_DeleteField_Call) Run(run func(field string)) *MockConfigurator_DeleteField_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigurator_DeleteField_Call) Return(err error) *MockConfigurator_DeleteField_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockConfigurator_DeleteField_Call) RunAndReturn(run func(field string) error) *MockConfigurator_DeleteField_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FieldSource provides a mock function for the type MockConfigurator
+func (_mock *MockConfigurator) FieldSource(field string) config.Source {
+	ret := _mock.Called(field)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FieldSource")
+	}
+
+	var r0 config.Source
+	if returnFunc, ok := ret.Get(0).(func(string) config.Source); ok {
+		r0 = returnFunc(field)
+	} else {
+		r0 = ret.Get(0).(config.Source)
+	}
+	return r0
+}
+
+// MockConfigurator_FieldSource_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FieldSource'
+type MockConfigurator_FieldSource_Call struct {
+	*mock.Call
+}
+
+// FieldSource is a helper method to define mock.On call
+//   - field string
+func (_e *MockConfigurator_Expecter) FieldSource(field interface{}) *MockConfigurator_FieldSource_Call {
+	return &MockConfigurator_FieldSource_Call{Call: _e.mock.On("FieldSource", field)}
+}
+
+func (_c *MockConfigurator_FieldSource_Call) Run(run func(field string)) *MockConfigurator_FieldSource_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigurator_FieldSource_Call) Return(source config.Source) *MockConfigurator_FieldSource_Call {
+	_c.Call.Return(source)
+	return _c
+}
+
+func (_c *MockConfigurator_FieldSource_Call) RunAndReturn(run func(field string) config.Source) *MockConfigurator_FieldSource_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FileFields provides a mock function for the type MockConfigurator
+func (_mock *MockConfigurator) FileFields() []string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FileFields")
+	}
+
+	var r0 []string
+	if returnFunc, ok := ret.Get(0).(func() []string); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	return r0
+}
+
+// MockConfigurator_FileFields_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FileFields'
+type MockConfigurator_FileFields_Call struct {
+	*mock.Call
+}
+
+// FileFields is a helper method to define mock.On call
+func (_e *MockConfigurator_Expecter) FileFields() *MockConfigurator_FileFields_Call {
+	return &MockConfigurator_FileFields_Call{Call: _e.mock.On("FileFields")}
+}
+
+func (_c *MockConfigurator_FileFields_Call) Run(run func()) *MockConfigurator_FileFields_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurator_FileFields_Call) Return(strings []string) *MockConfigurator_FileFields_Call {
+	_c.Call.Return(strings)
+	return _c
+}
+
+func (_c *MockConfigurator_FileFields_Call) RunAndReturn(run func() []string) *MockConfigurator_FileFields_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Path provides a mock function for the type MockConfigurator
+func (_mock *MockConfigurator) Path() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Path")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockConfigurator_Path_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Path'
+type MockConfigurator_Path_Call struct {
+	*mock.Call
+}
+
+// Path is a helper method to define mock.On call
+func (_e *MockConfigurator_Expecter) Path() *MockConfigurator_Path_Call {
+	return &MockConfigurator_Path_Call{Call: _e.mock.On("Path")}
+}
+
+func (_c *MockConfigurator_Path_Call) Run(run func()) *MockConfigurator_Path_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurator_Path_Call) Return(s string) *MockConfigurator_Path_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockConfigurator_Path_Call) RunAndReturn(run func() string) *MockConfigurator_Path_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReadField provides a mock function for the type MockConfigurator
 func (_mock *MockConfigurator) ReadField(field string) any {
 	ret := _mock.Called(field)
@@ -88,6 +281,94 @@ func (_c *MockConfigurator_ReadField_Call) RunAndReturn(run func(field string) a
 	return _c
 }
 
+// Reload provides a mock function for the type MockConfigurator
+func (_mock *MockConfigurator) Reload() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reload")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockConfigurator_Reload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reload'
+type MockConfigurator_Reload_Call struct {
+	*mock.Call
+}
+
+// Reload is a helper method to define mock.On call
+func (_e *MockConfigurator_Expecter) Reload() *MockConfigurator_Reload_Call {
+	return &MockConfigurator_Reload_Call{Call: _e.mock.On("Reload")}
+}
+
+func (_c *MockConfigurator_Reload_Call) Run(run func()) *MockConfigurator_Reload_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurator_Reload_Call) Return(err error) *MockConfigurator_Reload_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockConfigurator_Reload_Call) RunAndReturn(run func() error) *MockConfigurator_Reload_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepoPath provides a mock function for the type MockConfigurator
+func (_mock *MockConfigurator) RepoPath() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepoPath")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockConfigurator_RepoPath_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepoPath'
+type MockConfigurator_RepoPath_Call struct {
+	*mock.Call
+}
+
+// RepoPath is a helper method to define mock.On call
+func (_e *MockConfigurator_Expecter) RepoPath() *MockConfigurator_RepoPath_Call {
+	return &MockConfigurator_RepoPath_Call{Call: _e.mock.On("RepoPath")}
+}
+
+func (_c *MockConfigurator_RepoPath_Call) Run(run func()) *MockConfigurator_RepoPath_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurator_RepoPath_Call) Return(s string) *MockConfigurator_RepoPath_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockConfigurator_RepoPath_Call) RunAndReturn(run func() string) *MockConfigurator_RepoPath_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // WriteField provides a mock function for the type MockConfigurator
 func (_mock *MockConfigurator) WriteField(field string, value any) error {
 	ret := _mock.Called(field, value)
@@ -8,6 +8,7 @@ import (
 	"context"
 
 	mock "github.com/stretchr/testify/mock"
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
 	"github.com/yaroslav-koval/hange/domain/entities"
 )
 
@@ -79,22 +80,22 @@ func (_c *MockExplainProcessor_Cleanup_Call) RunAndReturn(run func(context1 cont
 }
 
 // ExecuteExplainRequest provides a mock function for the type MockExplainProcessor
-func (_mock *MockExplainProcessor) ExecuteExplainRequest(context1 context.Context) (string, error) {
+func (_mock *MockExplainProcessor) ExecuteExplainRequest(context1 context.Context) (entities.Completion, error) {
 	ret := _mock.Called(context1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ExecuteExplainRequest")
 	}
 
-	var r0 string
+	var r0 entities.Completion
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (entities.Completion, error)); ok {
 		return returnFunc(context1)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) string); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) entities.Completion); ok {
 		r0 = returnFunc(context1)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(entities.Completion)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
 		r1 = returnFunc(context1)
@@ -128,27 +129,27 @@ func (_c *MockExplainProcessor_ExecuteExplainRequest_Call) Run(run func(context1
 	return _c
 }
 
-func (_c *MockExplainProcessor_ExecuteExplainRequest_Call) Return(s string, err error) *MockExplainProcessor_ExecuteExplainRequest_Call {
-	_c.Call.Return(s, err)
+func (_c *MockExplainProcessor_ExecuteExplainRequest_Call) Return(completion entities.Completion, err error) *MockExplainProcessor_ExecuteExplainRequest_Call {
+	_c.Call.Return(completion, err)
 	return _c
 }
 
-func (_c *MockExplainProcessor_ExecuteExplainRequest_Call) RunAndReturn(run func(context1 context.Context) (string, error)) *MockExplainProcessor_ExecuteExplainRequest_Call {
+func (_c *MockExplainProcessor_ExecuteExplainRequest_Call) RunAndReturn(run func(context1 context.Context) (entities.Completion, error)) *MockExplainProcessor_ExecuteExplainRequest_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // ProcessFiles provides a mock function for the type MockExplainProcessor
-func (_mock *MockExplainProcessor) ProcessFiles(context1 context.Context, fileCh <-chan entities.File) error {
-	ret := _mock.Called(context1, fileCh)
+func (_mock *MockExplainProcessor) ProcessFiles(context1 context.Context, fileCh <-chan entities.File, explainOptions entity.ExplainOptions) error {
+	ret := _mock.Called(context1, fileCh, explainOptions)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ProcessFiles")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, <-chan entities.File) error); ok {
-		r0 = returnFunc(context1, fileCh)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, <-chan entities.File, entity.ExplainOptions) error); ok {
+		r0 = returnFunc(context1, fileCh, explainOptions)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -163,11 +164,12 @@ type MockExplainProcessor_ProcessFiles_Call struct {
 // ProcessFiles is a helper method to define mock.On call
 //   - context1 context.Context
 //   - fileCh <-chan entities.File
-func (_e *MockExplainProcessor_Expecter) ProcessFiles(context1 interface{}, fileCh interface{}) *MockExplainProcessor_ProcessFiles_Call {
-	return &MockExplainProcessor_ProcessFiles_Call{Call: _e.mock.On("ProcessFiles", context1, fileCh)}
+//   - explainOptions entity.ExplainOptions
+func (_e *MockExplainProcessor_Expecter) ProcessFiles(context1 interface{}, fileCh interface{}, explainOptions interface{}) *MockExplainProcessor_ProcessFiles_Call {
+	return &MockExplainProcessor_ProcessFiles_Call{Call: _e.mock.On("ProcessFiles", context1, fileCh, explainOptions)}
 }
 
-func (_c *MockExplainProcessor_ProcessFiles_Call) Run(run func(context1 context.Context, fileCh <-chan entities.File)) *MockExplainProcessor_ProcessFiles_Call {
+func (_c *MockExplainProcessor_ProcessFiles_Call) Run(run func(context1 context.Context, fileCh <-chan entities.File, explainOptions entity.ExplainOptions)) *MockExplainProcessor_ProcessFiles_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -177,9 +179,14 @@ func (_c *MockExplainProcessor_ProcessFiles_Call) Run(run func(context1 context.
 		if args[1] != nil {
 			arg1 = args[1].(<-chan entities.File)
 		}
+		var arg2 entity.ExplainOptions
+		if args[2] != nil {
+			arg2 = args[2].(entity.ExplainOptions)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -190,7 +197,7 @@ func (_c *MockExplainProcessor_ProcessFiles_Call) Return(err error) *MockExplain
 	return _c
 }
 
-func (_c *MockExplainProcessor_ProcessFiles_Call) RunAndReturn(run func(context1 context.Context, fileCh <-chan entities.File) error) *MockExplainProcessor_ProcessFiles_Call {
+func (_c *MockExplainProcessor_ProcessFiles_Call) RunAndReturn(run func(context1 context.Context, fileCh <-chan entities.File, explainOptions entity.ExplainOptions) error) *MockExplainProcessor_ProcessFiles_Call {
 	_c.Call.Return(run)
 	return _c
 }
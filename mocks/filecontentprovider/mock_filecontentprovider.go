@@ -104,3 +104,143 @@ func (_c *MockFileContentProvider_GetFileContent_Call) RunAndReturn(run func(con
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetFileHead provides a mock function for the type MockFileContentProvider
+func (_mock *MockFileContentProvider) GetFileHead(ctx context.Context, path string, n int) ([]byte, error) {
+	ret := _mock.Called(ctx, path, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFileHead")
+	}
+
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) ([]byte, error)); ok {
+		return returnFunc(ctx, path, n)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) []byte); ok {
+		r0 = returnFunc(ctx, path, n)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, path, n)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFileContentProvider_GetFileHead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFileHead'
+type MockFileContentProvider_GetFileHead_Call struct {
+	*mock.Call
+}
+
+// GetFileHead is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+//   - n int
+func (_e *MockFileContentProvider_Expecter) GetFileHead(ctx interface{}, path interface{}, n interface{}) *MockFileContentProvider_GetFileHead_Call {
+	return &MockFileContentProvider_GetFileHead_Call{Call: _e.mock.On("GetFileHead", ctx, path, n)}
+}
+
+func (_c *MockFileContentProvider_GetFileHead_Call) Run(run func(ctx context.Context, path string, n int)) *MockFileContentProvider_GetFileHead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileContentProvider_GetFileHead_Call) Return(bytes []byte, err error) *MockFileContentProvider_GetFileHead_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *MockFileContentProvider_GetFileHead_Call) RunAndReturn(run func(ctx context.Context, path string, n int) ([]byte, error)) *MockFileContentProvider_GetFileHead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFileSize provides a mock function for the type MockFileContentProvider
+func (_mock *MockFileContentProvider) GetFileSize(context1 context.Context, s string) (int64, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFileSize")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFileContentProvider_GetFileSize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFileSize'
+type MockFileContentProvider_GetFileSize_Call struct {
+	*mock.Call
+}
+
+// GetFileSize is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockFileContentProvider_Expecter) GetFileSize(context1 interface{}, s interface{}) *MockFileContentProvider_GetFileSize_Call {
+	return &MockFileContentProvider_GetFileSize_Call{Call: _e.mock.On("GetFileSize", context1, s)}
+}
+
+func (_c *MockFileContentProvider_GetFileSize_Call) Run(run func(context1 context.Context, s string)) *MockFileContentProvider_GetFileSize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileContentProvider_GetFileSize_Call) Return(n int64, err error) *MockFileContentProvider_GetFileSize_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockFileContentProvider_GetFileSize_Call) RunAndReturn(run func(context1 context.Context, s string) (int64, error)) *MockFileContentProvider_GetFileSize_Call {
+	_c.Call.Return(run)
+	return _c
+}
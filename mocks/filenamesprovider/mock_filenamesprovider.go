@@ -8,6 +8,7 @@ import (
 	"context"
 
 	mock "github.com/stretchr/testify/mock"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
 )
 
 // NewMockFileNamesProvider creates a new instance of MockFileNamesProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -38,8 +39,8 @@ func (_m *MockFileNamesProvider) EXPECT() *MockFileNamesProvider_Expecter {
 }
 
 // GetAllFileNames provides a mock function for the type MockFileNamesProvider
-func (_mock *MockFileNamesProvider) GetAllFileNames(context1 context.Context, strings []string) ([]string, error) {
-	ret := _mock.Called(context1, strings)
+func (_mock *MockFileNamesProvider) GetAllFileNames(context1 context.Context, namesConfig fileprovider.NamesConfig, strings []string) ([]string, error) {
+	ret := _mock.Called(context1, namesConfig, strings)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllFileNames")
@@ -47,18 +48,18 @@ func (_mock *MockFileNamesProvider) GetAllFileNames(context1 context.Context, st
 
 	var r0 []string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]string, error)); ok {
-		return returnFunc(context1, strings)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, fileprovider.NamesConfig, []string) ([]string, error)); ok {
+		return returnFunc(context1, namesConfig, strings)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []string); ok {
-		r0 = returnFunc(context1, strings)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, fileprovider.NamesConfig, []string) []string); ok {
+		r0 = returnFunc(context1, namesConfig, strings)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = returnFunc(context1, strings)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, fileprovider.NamesConfig, []string) error); ok {
+		r1 = returnFunc(context1, namesConfig, strings)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,24 +73,30 @@ type MockFileNamesProvider_GetAllFileNames_Call struct {
 
 // GetAllFileNames is a helper method to define mock.On call
 //   - context1 context.Context
+//   - namesConfig fileprovider.NamesConfig
 //   - strings []string
-func (_e *MockFileNamesProvider_Expecter) GetAllFileNames(context1 interface{}, strings interface{}) *MockFileNamesProvider_GetAllFileNames_Call {
-	return &MockFileNamesProvider_GetAllFileNames_Call{Call: _e.mock.On("GetAllFileNames", context1, strings)}
+func (_e *MockFileNamesProvider_Expecter) GetAllFileNames(context1 interface{}, namesConfig interface{}, strings interface{}) *MockFileNamesProvider_GetAllFileNames_Call {
+	return &MockFileNamesProvider_GetAllFileNames_Call{Call: _e.mock.On("GetAllFileNames", context1, namesConfig, strings)}
 }
 
-func (_c *MockFileNamesProvider_GetAllFileNames_Call) Run(run func(context1 context.Context, strings []string)) *MockFileNamesProvider_GetAllFileNames_Call {
+func (_c *MockFileNamesProvider_GetAllFileNames_Call) Run(run func(context1 context.Context, namesConfig fileprovider.NamesConfig, strings []string)) *MockFileNamesProvider_GetAllFileNames_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []string
+		var arg1 fileprovider.NamesConfig
 		if args[1] != nil {
-			arg1 = args[1].([]string)
+			arg1 = args[1].(fileprovider.NamesConfig)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -100,7 +107,7 @@ func (_c *MockFileNamesProvider_GetAllFileNames_Call) Return(strings1 []string,
 	return _c
 }
 
-func (_c *MockFileNamesProvider_GetAllFileNames_Call) RunAndReturn(run func(context1 context.Context, strings []string) ([]string, error)) *MockFileNamesProvider_GetAllFileNames_Call {
+func (_c *MockFileNamesProvider_GetAllFileNames_Call) RunAndReturn(run func(context1 context.Context, namesConfig fileprovider.NamesConfig, strings []string) ([]string, error)) *MockFileNamesProvider_GetAllFileNames_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -40,8 +40,8 @@ func (_m *MockFileProvider) EXPECT() *MockFileProvider_Expecter {
 }
 
 // GetAllFileNames provides a mock function for the type MockFileProvider
-func (_mock *MockFileProvider) GetAllFileNames(context1 context.Context, strings []string) ([]string, error) {
-	ret := _mock.Called(context1, strings)
+func (_mock *MockFileProvider) GetAllFileNames(context1 context.Context, namesConfig fileprovider.NamesConfig, strings []string) ([]string, error) {
+	ret := _mock.Called(context1, namesConfig, strings)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllFileNames")
@@ -49,18 +49,18 @@ func (_mock *MockFileProvider) GetAllFileNames(context1 context.Context, strings
 
 	var r0 []string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]string, error)); ok {
-		return returnFunc(context1, strings)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, fileprovider.NamesConfig, []string) ([]string, error)); ok {
+		return returnFunc(context1, namesConfig, strings)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []string); ok {
-		r0 = returnFunc(context1, strings)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, fileprovider.NamesConfig, []string) []string); ok {
+		r0 = returnFunc(context1, namesConfig, strings)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = returnFunc(context1, strings)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, fileprovider.NamesConfig, []string) error); ok {
+		r1 = returnFunc(context1, namesConfig, strings)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -74,24 +74,30 @@ type MockFileProvider_GetAllFileNames_Call struct {
 
 // GetAllFileNames is a helper method to define mock.On call
 //   - context1 context.Context
+//   - namesConfig fileprovider.NamesConfig
 //   - strings []string
-func (_e *MockFileProvider_Expecter) GetAllFileNames(context1 interface{}, strings interface{}) *MockFileProvider_GetAllFileNames_Call {
-	return &MockFileProvider_GetAllFileNames_Call{Call: _e.mock.On("GetAllFileNames", context1, strings)}
+func (_e *MockFileProvider_Expecter) GetAllFileNames(context1 interface{}, namesConfig interface{}, strings interface{}) *MockFileProvider_GetAllFileNames_Call {
+	return &MockFileProvider_GetAllFileNames_Call{Call: _e.mock.On("GetAllFileNames", context1, namesConfig, strings)}
 }
 
-func (_c *MockFileProvider_GetAllFileNames_Call) Run(run func(context1 context.Context, strings []string)) *MockFileProvider_GetAllFileNames_Call {
+func (_c *MockFileProvider_GetAllFileNames_Call) Run(run func(context1 context.Context, namesConfig fileprovider.NamesConfig, strings []string)) *MockFileProvider_GetAllFileNames_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []string
+		var arg1 fileprovider.NamesConfig
 		if args[1] != nil {
-			arg1 = args[1].([]string)
+			arg1 = args[1].(fileprovider.NamesConfig)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -102,7 +108,7 @@ func (_c *MockFileProvider_GetAllFileNames_Call) Return(strings1 []string, err e
 	return _c
 }
 
-func (_c *MockFileProvider_GetAllFileNames_Call) RunAndReturn(run func(context1 context.Context, strings []string) ([]string, error)) *MockFileProvider_GetAllFileNames_Call {
+func (_c *MockFileProvider_GetAllFileNames_Call) RunAndReturn(run func(context1 context.Context, namesConfig fileprovider.NamesConfig, strings []string) ([]string, error)) *MockFileProvider_GetAllFileNames_Call {
 	_c.Call.Return(run)
 	return _c
 }
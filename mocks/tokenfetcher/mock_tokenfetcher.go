@@ -87,3 +87,47 @@ func (_c *MockTokenFetcher_Fetch_Call) RunAndReturn(run func() (string, error))
 	_c.Call.Return(run)
 	return _c
 }
+
+// Source provides a mock function for the type MockTokenFetcher
+func (_mock *MockTokenFetcher) Source() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Source")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockTokenFetcher_Source_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Source'
+type MockTokenFetcher_Source_Call struct {
+	*mock.Call
+}
+
+// Source is a helper method to define mock.On call
+func (_e *MockTokenFetcher_Expecter) Source() *MockTokenFetcher_Source_Call {
+	return &MockTokenFetcher_Source_Call{Call: _e.mock.On("Source")}
+}
+
+func (_c *MockTokenFetcher_Source_Call) Run(run func()) *MockTokenFetcher_Source_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTokenFetcher_Source_Call) Return(s string) *MockTokenFetcher_Source_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockTokenFetcher_Source_Call) RunAndReturn(run func() string) *MockTokenFetcher_Source_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -35,6 +35,50 @@ func (_m *MockTokenStorer) EXPECT() *MockTokenStorer_Expecter {
 	return &MockTokenStorer_Expecter{mock: &_m.Mock}
 }
 
+// Delete provides a mock function for the type MockTokenStorer
+func (_mock *MockTokenStorer) Delete() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTokenStorer_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockTokenStorer_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+func (_e *MockTokenStorer_Expecter) Delete() *MockTokenStorer_Delete_Call {
+	return &MockTokenStorer_Delete_Call{Call: _e.mock.On("Delete")}
+}
+
+func (_c *MockTokenStorer_Delete_Call) Run(run func()) *MockTokenStorer_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTokenStorer_Delete_Call) Return(err error) *MockTokenStorer_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTokenStorer_Delete_Call) RunAndReturn(run func() error) *MockTokenStorer_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Store provides a mock function for the type MockTokenStorer
 func (_mock *MockTokenStorer) Store(token string) error {
 	ret := _mock.Called(token)
@@ -0,0 +1,55 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates a file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "out.json")
+
+		require.NoError(t, WriteFile(path, []byte("new"), 0o640))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(data))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	})
+
+	t.Run("replaces a file and leaves no temporary files", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out.txt")
+		require.NoError(t, os.WriteFile(path, []byte("old content"), 0o644))
+
+		require.NoError(t, WriteFile(path, []byte("new"), 0o644))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(data))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("fails when directory does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		err := WriteFile(filepath.Join(t.TempDir(), "missing", "out.txt"), []byte("new"), 0o644)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,316 @@
+// Package cassette records HTTP interactions to a file and replays them without network.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/yaroslav-koval/hange/pkg/atomicfile"
+)
+
+type Mode string
+
+const (
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+// redacted replaces secrets, so a cassette can be attached to a bug report
+const redacted = "[REDACTED]"
+
+// boundary replaces a random multipart boundary, so bodies of equal requests are equal
+const boundary = "cassette-boundary"
+
+var ErrInvalidSpec = errors.New(`cassette must be "record:<path>" or "replay:<path>"`)
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// secretHeaders are dropped from recorded requests and responses
+var secretHeaders = []string{"Authorization", "Api-Key", "Cookie", "Set-Cookie", "Openai-Organization",
+	"Openai-Project"}
+
+// ParseSpec parses "record:<path>" or "replay:<path>".
+func ParseSpec(spec string) (Mode, string, error) {
+	mode, path, ok := strings.Cut(spec, ":")
+	if !ok || path == "" {
+		return "", "", ErrInvalidSpec
+	}
+
+	switch Mode(mode) {
+	case ModeRecord, ModeReplay:
+		return Mode(mode), path, nil
+	default:
+		return "", "", ErrInvalidSpec
+	}
+}
+
+// Interaction is a request and its response. Bodies which are not valid UTF-8 are base64 encoded.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 bool        `json:"body_base64,omitempty"`
+}
+
+type Response struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 bool        `json:"body_base64,omitempty"`
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// New creates a round tripper of the mode. A recorder sends requests by next and rewrites the file
+// after each interaction, so an interrupted run keeps what it recorded. Secrets are replaced in URLs and bodies.
+// A replayer doesn't use next, it fails a request which doesn't match an unused interaction.
+func New(mode Mode, path string, next http.RoundTripper, secrets ...string) (http.RoundTripper, error) {
+	switch mode {
+	case ModeRecord:
+		if next == nil {
+			next = http.DefaultTransport
+		}
+
+		return &recorder{path: path, next: next, secrets: secrets, mutex: &sync.Mutex{}}, nil
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+
+		c := cassette{}
+		if err = json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+
+		return &replayer{
+			interactions: c.Interactions,
+			used:         make([]bool, len(c.Interactions)),
+			mutex:        &sync.Mutex{},
+		}, nil
+	default:
+		return nil, ErrInvalidSpec
+	}
+}
+
+type recorder struct {
+	path         string
+	next         http.RoundTripper
+	secrets      []string
+	interactions []Interaction
+	mutex        *sync.Mutex
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	reqBody = normalizeBoundary(req.Header.Get("Content-Type"), reqBody)
+
+	i := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.scrub(req.URL.String()),
+			Header: scrubHeader(req.Header),
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: scrubHeader(resp.Header),
+		},
+	}
+	i.Request.Body, i.Request.BodyBase64 = encodeBody([]byte(r.scrub(string(reqBody))))
+	i.Response.Body, i.Response.BodyBase64 = encodeBody([]byte(r.scrub(string(respBody))))
+
+	if err = r.append(i); err != nil {
+		return nil, fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (r *recorder) append(i Interaction) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.interactions = append(r.interactions, i)
+
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(r.path, data, 0600)
+}
+
+func (r *recorder) scrub(s string) string {
+	for _, secret := range r.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+
+	return s
+}
+
+type replayer struct {
+	interactions []Interaction
+	used         []bool
+	mutex        *sync.Mutex
+}
+
+// RoundTrip prefers an interaction with an equal body. Otherwise the first unused one of the method and URL
+// is taken, since bodies may contain values of the run, e.g. a timestamp. Concurrent requests may be recorded
+// in any order, so the order of interactions matters only for requests of the same method and URL.
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := encodeBody(normalizeBoundary(req.Header.Get("Content-Type"), reqBody))
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	match := -1
+
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request.Method != req.Method || requestURI(in.Request.URL) != req.URL.RequestURI() {
+			continue
+		}
+
+		if in.Request.Body == body {
+			match = i
+			break
+		}
+
+		if match == -1 {
+			match = i
+		}
+	}
+
+	if match == -1 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+	}
+
+	r.used[match] = true
+	in := r.interactions[match].Response
+
+	respBody, err := decodeBody(in.Body, in.BodyBase64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func normalizeBoundary(contentType string, body []byte) []byte {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["boundary"] == "" {
+		return body
+	}
+
+	return bytes.ReplaceAll(body, []byte(params["boundary"]), []byte(boundary))
+}
+
+func scrubHeader(h http.Header) http.Header {
+	h = h.Clone()
+
+	for _, name := range secretHeaders {
+		h.Del(name)
+	}
+
+	if ct := h.Get("Content-Type"); ct != "" {
+		if _, params, err := mime.ParseMediaType(ct); err == nil && params["boundary"] != "" {
+			h.Set("Content-Type", strings.ReplaceAll(ct, params["boundary"], boundary))
+		}
+	}
+
+	return h
+}
+
+func requestURI(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	return u.RequestURI()
+}
+
+func encodeBody(body []byte) (string, bool) {
+	if utf8.Valid(body) {
+		return string(body), false
+	}
+
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+func decodeBody(body string, isBase64 bool) ([]byte, error) {
+	if !isBase64 {
+		return []byte(body), nil
+	}
+
+	return base64.StdEncoding.DecodeString(body)
+}
@@ -0,0 +1,131 @@
+package cassette
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const token = "sk-secret"
+
+func TestParseSpec(t *testing.T) {
+	t.Parallel()
+
+	mode, path, err := ParseSpec("replay:testdata/run.json")
+	require.NoError(t, err)
+	assert.Equal(t, ModeReplay, mode)
+	assert.Equal(t, "testdata/run.json", path)
+
+	for _, spec := range []string{"record", "record:", "play:file.json"} {
+		_, _, err = ParseSpec(spec)
+		assert.ErrorIs(t, err, ErrInvalidSpec, spec)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=1")
+
+		switch r.URL.Path {
+		case "/files":
+			_, _ = w.Write([]byte(`{"id":"file_1"}`))
+		case "/responses":
+			body, _ := io.ReadAll(r.Body)
+			_, _ = w.Write([]byte(`{"echo":` + string(body) + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	rec, err := New(ModeRecord, path, http.DefaultTransport, token)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: rec}
+	assert.Equal(t, `{"id":"file_1"}`, send(t, client, uploadRequest(t, server.URL)))
+	assert.Equal(t, `{"echo":{"n":1}}`, send(t, client, jsonRequest(t, server.URL, `{"n":1}`)))
+	assert.Equal(t, `{"echo":{"n":2}}`, send(t, client, jsonRequest(t, server.URL, `{"n":2}`)))
+
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), token)
+	assert.NotContains(t, string(data), "session=1")
+	assert.Contains(t, string(data), boundary)
+
+	rep, err := New(ModeReplay, path, nil)
+	require.NoError(t, err)
+
+	client = &http.Client{Transport: rep}
+
+	// an equal body wins over the order, a new multipart boundary still matches
+	assert.Equal(t, `{"echo":{"n":2}}`, send(t, client, jsonRequest(t, server.URL, `{"n":2}`)))
+	assert.Equal(t, `{"id":"file_1"}`, send(t, client, uploadRequest(t, server.URL)))
+	assert.Equal(t, `{"echo":{"n":1}}`, send(t, client, jsonRequest(t, server.URL, `{"n":3}`)))
+
+	_, err = client.Do(jsonRequest(t, server.URL, `{"n":1}`))
+	require.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func send(t *testing.T, client *http.Client, req *http.Request) string {
+	t.Helper()
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func jsonRequest(t *testing.T, baseURL, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/responses", strings.NewReader(body))
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return req
+}
+
+// uploadRequest has a random multipart boundary and binary content
+func uploadRequest(t *testing.T, baseURL string) *http.Request {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	mw := multipart.NewWriter(buf)
+
+	fw, err := mw.CreateFormFile("file", "a.bin")
+	require.NoError(t, err)
+
+	_, err = fw.Write([]byte{0xff, 0xfe, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/files", buf)
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return req
+}
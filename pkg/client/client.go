@@ -0,0 +1,290 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yaroslav-koval/hange/domain/agent/entity"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/config/consts"
+	"github.com/yaroslav-koval/hange/domain/entities"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/factory/agentfactory"
+	"github.com/yaroslav-koval/hange/domain/factory/appfactory"
+	"github.com/yaroslav-koval/hange/domain/fileprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/archiveprovider"
+	"github.com/yaroslav-koval/hange/domain/fileprovider/ignore"
+	"github.com/yaroslav-koval/hange/domain/git"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	"github.com/yaroslav-koval/hange/pkg/logging"
+)
+
+var (
+	ErrUnsupportedProvider = errors.New("unsupported provider")
+	ErrNoPaths             = errors.New("no paths to explain")
+	// ErrStdin is returned for the "-" path, the client never reads stdin of the process.
+	ErrStdin = fmt.Errorf("%q can't be explained by the client", archiveprovider.StdinPath)
+)
+
+// Client generates commit messages and explanations. It wires the config, the profile and the agent once
+// and reuses them, so it should be kept for the lifetime of the program. Calls of a client run one at a time,
+// since a run keeps its state in the agent. Use several clients for parallel calls.
+type Client struct {
+	app    factory.AppBuilder
+	gitDir string
+	logger *slog.Logger
+	calls  *sync.Mutex
+}
+
+// New creates a client. Nothing is read until the first call, so a missing config or token is reported by it.
+func New(opts ...Option) (*Client, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.provider != "" && o.provider != ProviderOffline && !slices.Contains(profile.Providers, o.provider) {
+		return nil, fmt.Errorf("%w %q, supported: %v", ErrUnsupportedProvider, o.provider,
+			append(slices.Clone(profile.Providers), ProviderOffline))
+	}
+
+	if o.gitDir != "" {
+		dir, err := filepath.Abs(o.gitDir)
+		if err != nil {
+			return nil, err
+		}
+
+		o.gitDir = dir
+	}
+
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
+	agentFactory := agentfactory.NewOpenAIFactory()
+	if o.provider == ProviderOffline {
+		agentFactory = agentfactory.NewOfflineFactory(agentFactory)
+	}
+
+	appFactory := &clientFactory{
+		AppFactory: appfactory.NewCLIFactory(o.configPath, o.profile, o.gitDir),
+		opts:       o,
+	}
+
+	return &Client{
+		app:    factory.NewAppBuilder(appFactory, agentFactory),
+		gitDir: o.gitDir,
+		logger: o.logger,
+		calls:  &sync.Mutex{},
+	}, nil
+}
+
+// CommitMessageRequest is a request of a commit message of the staged changes.
+type CommitMessageRequest struct {
+	// Context describes the changes, e.g. a task. It is optional.
+	Context string
+}
+
+// CommitMessage generates a commit message of the staged changes of the repository.
+func (c *Client) CommitMessage(ctx context.Context, req CommitMessageRequest) (Result, error) {
+	c.calls.Lock()
+	defer c.calls.Unlock()
+
+	start := time.Now()
+
+	completion, err := c.commitMessage(ctx, req)
+	if err != nil {
+		c.logger.Debug("Commit message is not generated", logging.Err(err))
+		return Result{}, err
+	}
+
+	c.logger.Debug("Commit message is generated", slog.Duration(logging.KeyDuration, time.Since(start)))
+
+	return newResult(completion), nil
+}
+
+func (c *Client) commitMessage(ctx context.Context, req CommitMessageRequest) (entities.Completion, error) {
+	changes, err := c.app.GetGitChangesProvider()
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	status, err := changes.Status(ctx)
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	stagedStatus, err := changes.StagedStatus(ctx)
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	diff, err := changes.StagedDiff(ctx, git.DiffContextLines)
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	ag, err := c.app.GetAIAgent()
+	if err != nil {
+		return entities.Completion{}, err
+	}
+
+	return ag.CreateCommitMessage(ctx, entity.CommitData{
+		UserInput:    req.Context,
+		Status:       status,
+		StagedStatus: stagedStatus,
+		Diff:         diff,
+	})
+}
+
+// ExplainRequest is a request of an explanation of files and directories.
+type ExplainRequest struct {
+	// Paths are files, directories or archives. Relative paths are relative to the git dir of the client.
+	Paths []string
+	// Include and Exclude are gitignore-style patterns of files to read and to skip, relative to each path.
+	// The "ignore" patterns of the config are skipped too, they are relative to the repository.
+	Include []string
+	Exclude []string
+	// Persistent keeps the vector store of the repository between calls, so only changes are uploaded.
+	// The "explain.persistent" config key decides if it is nil.
+	Persistent *bool
+	// Fresh rebuilds the persistent vector store, it implies Persistent.
+	Fresh bool
+}
+
+// Explain explains files and directories. Directories are read recursively, ignored, binary, generated
+// and large files are skipped and listed in the explanation.
+func (c *Client) Explain(ctx context.Context, req ExplainRequest) (Explanation, error) {
+	c.calls.Lock()
+	defer c.calls.Unlock()
+
+	start := time.Now()
+
+	e, err := c.explain(ctx, req)
+	if err != nil {
+		c.logger.Debug("Files are not explained", logging.Err(err))
+		return Explanation{}, err
+	}
+
+	c.logger.Debug("Files are explained", slog.Int("files", e.FilesProcessed),
+		slog.Duration(logging.KeyDuration, time.Since(start)))
+
+	return e, nil
+}
+
+func (c *Client) explain(ctx context.Context, req ExplainRequest) (Explanation, error) {
+	paths, err := c.resolvePaths(req.Paths)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	opts, namesCfg, err := c.explainConfig(req)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	skipped := fileprovider.NewSkipCollector()
+
+	completion, processed, err := factory.ExplainPaths(ctx, c.app, factory.ExplainRun{
+		Paths:   paths,
+		Names:   namesCfg,
+		Policy:  fileprovider.SkipPolicy{MaxFileSize: fileprovider.DefaultMaxFileSize},
+		Skipped: skipped,
+		Options: opts,
+	})
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	return Explanation{
+		Result:         newResult(completion),
+		FilesProcessed: processed,
+		Skipped:        c.skippedFiles(skipped.Skipped()),
+	}, nil
+}
+
+// resolvePaths makes relative paths relative to the git dir, the process doesn't change its working directory
+func (c *Client) resolvePaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, ErrNoPaths
+	}
+
+	resolved := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		if p == archiveprovider.StdinPath {
+			return nil, ErrStdin
+		}
+
+		if p == "" {
+			return nil, fmt.Errorf("%w: empty path", ErrNoPaths)
+		}
+
+		if c.gitDir != "" && !filepath.IsAbs(p) {
+			p = filepath.Join(c.gitDir, p)
+		}
+
+		resolved = append(resolved, p)
+	}
+
+	return resolved, nil
+}
+
+// explainConfig applies the config defaults of the explain command to the request
+func (c *Client) explainConfig(req ExplainRequest) (entity.ExplainOptions, fileprovider.NamesConfig, error) {
+	cfg, err := c.app.GetConfigurator()
+	if err != nil {
+		return entity.ExplainOptions{}, fileprovider.NamesConfig{}, err
+	}
+
+	// the rules of the config are relative to the repository, not to the paths of the request
+	rules, err := ignore.ReadConfig(cfg, c.gitDir)
+	if err != nil {
+		return entity.ExplainOptions{}, fileprovider.NamesConfig{}, err
+	}
+
+	namesCfg := fileprovider.NamesConfig{
+		Include: req.Include,
+		Exclude: req.Exclude,
+		Ignore:  rules,
+	}
+
+	opts := entity.ExplainOptions{Fresh: req.Fresh, Persistent: req.Fresh}
+
+	switch {
+	case req.Fresh:
+	case req.Persistent != nil:
+		opts.Persistent = *req.Persistent
+	default:
+		if opts.Persistent, err = config.ReadBool(cfg, consts.ExplainPersistentPath); err != nil {
+			return entity.ExplainOptions{}, fileprovider.NamesConfig{}, err
+		}
+	}
+
+	return opts, namesCfg, nil
+}
+
+// skippedFiles are relative to the git dir, as the paths of the request
+func (c *Client) skippedFiles(files []entities.SkippedFile) []SkippedFile {
+	res := make([]SkippedFile, 0, len(files))
+
+	for _, f := range files {
+		path := f.Path
+
+		if c.gitDir != "" {
+			if rel, err := filepath.Rel(c.gitDir, path); err == nil && filepath.IsLocal(rel) {
+				path = filepath.ToSlash(rel)
+			}
+		}
+
+		res = append(res, SkippedFile{Path: path, Reason: string(f.Reason), Details: f.Details})
+	}
+
+	return res
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/domain/profile"
+	appfactory_mock "github.com/yaroslav-koval/hange/mocks/appfactory"
+	configurator_mock "github.com/yaroslav-koval/hange/mocks/configurator"
+	profilemanager_mock "github.com/yaroslav-koval/hange/mocks/profilemanager"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(WithProvider("anthropic", ""))
+	require.ErrorIs(t, err, ErrUnsupportedProvider)
+
+	c, err := New(WithGitDir("repo"))
+	require.NoError(t, err)
+	assert.True(t, filepath.IsAbs(c.gitDir), "the git dir is absolute")
+}
+
+func TestClient_Explain_rejectsPaths(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(WithGitDir(t.TempDir()))
+	require.NoError(t, err)
+
+	for _, paths := range [][]string{nil, {""}, {"cmd", "-"}} {
+		_, err = c.Explain(context.Background(), ExplainRequest{Paths: paths})
+		require.Error(t, err)
+	}
+
+	_, err = c.Explain(context.Background(), ExplainRequest{Paths: []string{"-"}})
+	require.ErrorIs(t, err, ErrStdin)
+}
+
+func TestClientFactory(t *testing.T) {
+	t.Parallel()
+
+	cfg := configurator_mock.NewMockConfigurator(t)
+	work := profile.Profile{Name: "work", Provider: profile.ProviderOpenAI, BaseURL: "https://api.example.com/v1"}
+
+	t.Run("provider overrides every profile", func(t *testing.T) {
+		t.Parallel()
+
+		pm := profilemanager_mock.NewMockProfileManager(t)
+		pm.EXPECT().Get("work").Return(work, nil)
+
+		next := appfactory_mock.NewMockAppFactory(t)
+		next.EXPECT().CreateProfileManager(cfg).Return(pm, nil)
+
+		f := &clientFactory{AppFactory: next, opts: options{baseURL: "http://localhost:11434/v1"}}
+
+		got, err := f.CreateProfileManager(cfg)
+		require.NoError(t, err)
+
+		p, err := got.Get("work")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:11434/v1", p.BaseURL)
+		assert.Equal(t, profile.ProviderOpenAI, p.Provider)
+	})
+
+	t.Run("token source replaces the stored token", func(t *testing.T) {
+		t.Parallel()
+
+		f := &clientFactory{
+			AppFactory: appfactory_mock.NewMockAppFactory(t),
+			opts:       options{tokenSource: StaticToken("sk-test")},
+		}
+
+		fetcher, err := f.CreateTokenFetcher(cfg, work)
+		require.NoError(t, err)
+
+		token, err := fetcher.Fetch()
+		require.NoError(t, err)
+
+		decryptor, err := f.CreateDecryptor(work)
+		require.NoError(t, err)
+
+		plain, err := decryptor.Decrypt([]byte(token))
+		require.NoError(t, err)
+		assert.Equal(t, "sk-test", string(plain))
+	})
+}
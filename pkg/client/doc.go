@@ -0,0 +1,23 @@
+// Package client embeds hange into other Go programs: commit messages of staged changes and explanations
+// of files, wired the same way as the command line, with the same config, profiles and routing.
+//
+//	c, err := client.New(client.WithGitDir("/path/to/repo"))
+//	if err != nil {
+//		return err
+//	}
+//
+//	res, err := c.CommitMessage(ctx, client.CommitMessageRequest{Context: "JIRA-1"})
+//
+// # Compatibility
+//
+// The package follows semantic versioning of the module. Within a major version:
+//
+//   - exported identifiers of this package are not removed, renamed or changed in an incompatible way;
+//   - new options, methods, request and result fields may be added, their zero values keep the previous behavior;
+//   - fields of results are not reordered for positional use, use field names in composite literals;
+//   - errors are matched with errors.Is against the exported Err values, messages may change.
+//
+// Packages under domain and cmd are the implementation of the command line. They are not covered by the policy
+// and may change in any release, so programs should only import this package.
+// The config file format follows the command line, see "hange config migrate" for upgrades between versions.
+package client
@@ -0,0 +1,117 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/yaroslav-koval/hange/pkg/client"
+)
+
+// The offline provider derives the message from the staged patch without any request, so no token is needed.
+func ExampleClient_CommitMessage() {
+	repo, config, cleanup := exampleRepo()
+	defer cleanup()
+
+	c, err := client.New(
+		client.WithGitDir(repo),
+		client.WithConfigPath(config),
+		client.WithProvider(client.ProviderOffline, ""),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	res, err := c.CommitMessage(context.Background(), client.CommitMessageRequest{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(res.Text)
+	// Output: retry: add Do
+}
+
+func ExampleClient_Explain() {
+	c, err := client.New(
+		client.WithGitDir("/path/to/repo"),
+		client.WithTokenSource(func() (string, error) {
+			return os.Getenv("OPENAI_API_KEY"), nil
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	persistent := true
+
+	e, err := c.Explain(context.Background(), client.ExplainRequest{
+		Paths:      []string{"cmd", "README.md"},
+		Exclude:    []string{"*_test.go"},
+		Persistent: &persistent,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(e.Text)
+
+	for _, f := range e.Skipped {
+		fmt.Printf("skipped %s: %s\n", f.Path, f.Reason)
+	}
+}
+
+// A local model behind an OpenAI compatible API
+func ExampleWithProvider() {
+	c, err := client.New(
+		client.WithProvider(client.ProviderOpenAI, "http://localhost:11434/v1"),
+		client.WithTokenSource(client.StaticToken("ollama")),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	res, err := c.CommitMessage(context.Background(), client.CommitMessageRequest{Context: "JIRA-1"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(res.Text, res.Model)
+}
+
+// exampleRepo creates a repository with a staged Go file and an empty config next to it
+func exampleRepo() (string, string, func()) {
+	dir, err := os.MkdirTemp("", "hange-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	config := filepath.Join(dir, "config")
+	if err = os.WriteFile(config, nil, 0o600); err != nil {
+		log.Fatal(err)
+	}
+
+	repo := filepath.Join(dir, "repo")
+
+	src := "package retry\n\nfunc Do(f func() error) error {\n\treturn f()\n}\n"
+	if err = os.MkdirAll(filepath.Join(repo, "retry"), 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = os.WriteFile(filepath.Join(repo, "retry", "retry.go"), []byte(src), 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"init", "-q"}, {"add", "retry/retry.go"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			log.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	return repo, config, func() { _ = os.RemoveAll(dir) }
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"github.com/yaroslav-koval/hange/domain/auth"
+	"github.com/yaroslav-koval/hange/domain/auth/tokenfetch"
+	"github.com/yaroslav-koval/hange/domain/config"
+	"github.com/yaroslav-koval/hange/domain/crypt"
+	"github.com/yaroslav-koval/hange/domain/crypt/noop"
+	"github.com/yaroslav-koval/hange/domain/factory"
+	"github.com/yaroslav-koval/hange/domain/profile"
+)
+
+// clientFactory applies the options of the client over the factory of the command line
+type clientFactory struct {
+	factory.AppFactory
+	opts options
+}
+
+func (f *clientFactory) CreateProfileManager(configurator config.Configurator) (profile.ProfileManager, error) {
+	pm, err := f.AppFactory.CreateProfileManager(configurator)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.opts.provider == "" && f.opts.baseURL == "" {
+		return pm, nil
+	}
+
+	return &providerProfiles{ProfileManager: pm, opts: f.opts}, nil
+}
+
+func (f *clientFactory) CreateTokenFetcher(
+	configurator config.Configurator, p profile.Profile) (auth.TokenFetcher, error) {
+	if f.opts.tokenSource == nil {
+		return f.AppFactory.CreateTokenFetcher(configurator, p)
+	}
+
+	return tokenfetch.NewFuncTokenFetcher(f.opts.tokenSource, "token source of the client"), nil
+}
+
+// CreateDecryptor doesn't decrypt a token of the token source, it is never stored
+func (f *clientFactory) CreateDecryptor(p profile.Profile) (crypt.Decryptor, error) {
+	if f.opts.tokenSource == nil {
+		return f.AppFactory.CreateDecryptor(p)
+	}
+
+	return noop.NewNoopDecryptor(), nil
+}
+
+// providerProfiles overrides the provider of the profiles read by the client
+type providerProfiles struct {
+	profile.ProfileManager
+	opts options
+}
+
+func (pp *providerProfiles) Get(name string) (profile.Profile, error) {
+	p, err := pp.ProfileManager.Get(name)
+	if err != nil {
+		return profile.Profile{}, err
+	}
+
+	// the offline provider doesn't send requests, the profile keeps its provider
+	if pp.opts.provider != "" && pp.opts.provider != ProviderOffline {
+		p.Provider = pp.opts.provider
+	}
+
+	if pp.opts.baseURL != "" {
+		p.BaseURL = pp.opts.baseURL
+	}
+
+	return p, nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"log/slog"
+)
+
+// Providers accepted by WithProvider
+const (
+	// ProviderOpenAI is the OpenAI API or an OpenAI compatible one, e.g. a local model.
+	ProviderOpenAI = "openai"
+	// ProviderOffline derives commit messages from the staged patch by heuristics without any request.
+	// Explain fails with it.
+	ProviderOffline = "offline"
+)
+
+// Option configures a Client.
+type Option func(*options)
+
+// TokenSource returns the API token of the provider. It is called when the first request needs the token.
+type TokenSource func() (string, error)
+
+// StaticToken returns the token as is.
+func StaticToken(token string) TokenSource {
+	return func() (string, error) {
+		return token, nil
+	}
+}
+
+type options struct {
+	configPath  string
+	profile     string
+	gitDir      string
+	provider    string
+	baseURL     string
+	tokenSource TokenSource
+	logger      *slog.Logger
+}
+
+// WithConfigPath reads the config file instead of $HOME/.hange/config.
+func WithConfigPath(path string) Option {
+	return func(o *options) {
+		o.configPath = path
+	}
+}
+
+// WithProfile uses the profile instead of the active one of the config.
+func WithProfile(name string) Option {
+	return func(o *options) {
+		o.profile = name
+	}
+}
+
+// WithGitDir makes the client work in the directory instead of the working directory of the process:
+// git commands, the repository config and relative paths of Explain.
+func WithGitDir(dir string) Option {
+	return func(o *options) {
+		o.gitDir = dir
+	}
+}
+
+// WithProvider sends requests to the provider instead of the one of the profile. baseURL points to
+// an OpenAI compatible API, the profile's one is kept if it is empty. It applies to every profile of routing.
+func WithProvider(name, baseURL string) Option {
+	return func(o *options) {
+		o.provider = name
+		o.baseURL = baseURL
+	}
+}
+
+// WithTokenSource supplies the token instead of the one stored in the config or printed by a credential helper.
+// The token is not stored.
+func WithTokenSource(ts TokenSource) Option {
+	return func(o *options) {
+		o.tokenSource = ts
+	}
+}
+
+// WithLogger receives records of the client's calls. The default is slog.Default.
+// Packages of the implementation log to slog.Default, set it with slog.SetDefault to capture their records too.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
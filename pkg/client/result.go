@@ -0,0 +1,55 @@
+package client
+
+import (
+	"github.com/yaroslav-koval/hange/domain/entities"
+)
+
+// Result is a text generated by the provider with the request metadata.
+type Result struct {
+	Text  string
+	Model string
+	// Backend is a name of the profile which answered if requests are routed between profiles.
+	Backend string
+	Usage   Usage
+	// Warnings are non-fatal issues that may reduce quality of the text, e.g. an incomplete response.
+	Warnings []string
+}
+
+// Usage is an amount of tokens spent by the requests.
+type Usage struct {
+	InputTokens  int64
+	OutputTokens int64
+	TotalTokens  int64
+}
+
+// Explanation is a Result of Explain with the files it is based on.
+type Explanation struct {
+	Result
+	// FilesProcessed is a number of files sent to the provider.
+	FilesProcessed int
+	// Skipped are files which were not sent, sorted by path.
+	Skipped []SkippedFile
+}
+
+// SkippedFile is a file which was not sent to the provider.
+type SkippedFile struct {
+	Path string
+	// Reason is "binary", "too large" or "generated".
+	Reason string
+	// Details explains the reason in a human-readable way, e.g. a detected content type or a size.
+	Details string
+}
+
+func newResult(c entities.Completion) Result {
+	return Result{
+		Text:    c.Text,
+		Model:   c.Model,
+		Backend: c.Backend,
+		Usage: Usage{
+			InputTokens:  c.Usage.InputTokens,
+			OutputTokens: c.Usage.OutputTokens,
+			TotalTokens:  c.Usage.TotalTokens,
+		},
+		Warnings: c.Warnings,
+	}
+}
@@ -1,3 +1,15 @@
 package envs
 
 const EnvHangeConfigPath = "HANGE_CONFIG_PATH"
+
+// EnvHangePassphrase is a passphrase for the stored auth token. A TTY prompt is used if it is not set.
+const EnvHangePassphrase = "HANGE_PASSPHRASE"
+
+// EnvHangeProfile selects a profile instead of the active one from the config.
+const EnvHangeProfile = "HANGE_PROFILE"
+
+// EnvHangeCassette records OpenAI requests to a file or replays them from it: "record:<path>" or "replay:<path>".
+const EnvHangeCassette = "HANGE_CASSETTE"
+
+// EnvHangeServeToken is a token of the HTTP API of serve. A random one is written to the token file if it is not set.
+const EnvHangeServeToken = "HANGE_SERVE_TOKEN"
@@ -0,0 +1,92 @@
+// Package logging configures slog of a run: a format, a destination and attributes shared by all records.
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats lists supported formats.
+var Formats = []string{FormatText, FormatJSON}
+
+// Keys of attributes used by several packages, so records can be filtered by the same key.
+const (
+	KeyRunID         = "run_id"
+	KeyCommand       = "command"
+	KeyProfile       = "profile"
+	KeyFile          = "file"
+	KeyVectorStoreID = "vector_store_id"
+	KeyFileID        = "file_id"
+	KeyDuration      = "duration"
+	KeyAttempt       = "attempt"
+	KeyError         = "error"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported log format")
+
+// Options of a logger. Logs never go to stdout, it is kept for command results.
+type Options struct {
+	Format string
+	// File is appended to. Stderr is used if it is empty.
+	File  string
+	Level slog.Level
+	// Attrs are added to every record, e.g. the run ID.
+	Attrs []slog.Attr
+}
+
+// New creates a logger of the options. The returned function closes the log file.
+func New(opts Options, stderr io.Writer) (*slog.Logger, func() error, error) {
+	if opts.Format == "" {
+		opts.Format = FormatText
+	}
+
+	if !slices.Contains(Formats, opts.Format) {
+		return nil, nil, fmt.Errorf("%w %q, supported: %v", ErrUnsupportedFormat, opts.Format, Formats)
+	}
+
+	w := stderr
+	closeFile := func() error { return nil }
+
+	if opts.File != "" {
+		f, err := os.OpenFile(opts.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+
+		w = f
+		closeFile = f.Close
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+
+	var handler slog.Handler = slog.NewTextHandler(w, handlerOpts)
+	if opts.Format == FormatJSON {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	}
+
+	return slog.New(handler.WithAttrs(opts.Attrs)), closeFile, nil
+}
+
+// NewRunID returns a random ID of an invocation, so records of concurrent runs in one file can be told apart.
+func NewRunID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// Err is an attribute of the error.
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("json to stderr", func(t *testing.T) {
+		t.Parallel()
+
+		stderr := &bytes.Buffer{}
+
+		logger, closeFile, err := New(Options{
+			Format: FormatJSON,
+			Attrs:  []slog.Attr{slog.String(KeyRunID, "run1")},
+		}, stderr)
+		require.NoError(t, err)
+
+		logger.Debug("hidden")
+		logger.Warn("Failed to delete file", slog.String(KeyFile, "a.go"), Err(errors.New("not found")))
+		require.NoError(t, closeFile())
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(stderr.Bytes(), &record))
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "Failed to delete file", record["msg"])
+		assert.Equal(t, "run1", record[KeyRunID])
+		assert.Equal(t, "a.go", record[KeyFile])
+		assert.Equal(t, "not found", record[KeyError])
+	})
+
+	t.Run("text to file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "hange.log")
+		stderr := &bytes.Buffer{}
+
+		for _, msg := range []string{"first", "second"} {
+			logger, closeFile, err := New(Options{File: path, Level: slog.LevelDebug}, stderr)
+			require.NoError(t, err)
+
+			logger.Debug(msg)
+			require.NoError(t, closeFile())
+		}
+
+		assert.Empty(t, stderr.String())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 2, "the file is appended to")
+		assert.Contains(t, lines[0], "msg=first")
+		assert.Contains(t, lines[1], "level=DEBUG")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := New(Options{Format: "xml"}, &bytes.Buffer{})
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
+
+func TestNewRunID(t *testing.T) {
+	t.Parallel()
+
+	assert.Len(t, NewRunID(), 16)
+	assert.NotEqual(t, NewRunID(), NewRunID())
+}
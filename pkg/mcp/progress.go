@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+// notifier sends progress of a call to the client. The progress is a sum of items done by all the stages,
+// so it only grows, as the protocol requires.
+type notifier struct {
+	server *Server
+	token  json.RawMessage
+
+	mutex  *sync.Mutex
+	totals map[progress.Stage]int
+	done   map[progress.Stage]int
+}
+
+func newNotifier(s *Server, token json.RawMessage) *notifier {
+	return &notifier{
+		server: s,
+		token:  token,
+		mutex:  &sync.Mutex{},
+		totals: map[progress.Stage]int{},
+		done:   map[progress.Stage]int{},
+	}
+}
+
+func (n *notifier) Expect(stage progress.Stage, count int) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.totals[stage] += count
+}
+
+func (n *notifier) Done(stage progress.Stage, count int, _ int64) {
+	if count <= 0 {
+		return
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.done[stage] += count
+
+	params := progressParams{
+		ProgressToken: n.token,
+		Message:       fmt.Sprintf("%s %d/%d files", stage, n.done[stage], n.totals[stage]),
+	}
+
+	for _, c := range n.done {
+		params.Progress += c
+	}
+
+	for _, c := range n.totals {
+		params.Total += c
+	}
+
+	// notifications are sent under the lock, so the client receives them in order of progress
+	n.server.notify(methodProgress, params)
+}
+
+func (n *notifier) Finish(progress.Stage) {}
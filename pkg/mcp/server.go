@@ -0,0 +1,359 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/yaroslav-koval/hange/pkg/logging"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+// ErrInvalidArguments is wrapped by tools to answer with an invalid params error instead of a failed result.
+var ErrInvalidArguments = errors.New("invalid arguments")
+
+// Tool is a tool exposed by the server.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is a JSON schema of the arguments.
+	InputSchema json.RawMessage
+	Run         ToolFunc
+}
+
+// ToolFunc runs a tool. The context is cancelled if the client cancels the call or the server stops.
+// Progress is sent to the client if it asked for it, otherwise the reporter discards it.
+// An error is returned to the client as a failed result, the server goes on.
+type ToolFunc func(ctx context.Context, args json.RawMessage, reporter progress.Reporter) (ToolResult, error)
+
+// ToolResult is a text for the model and, optionally, the same result as a JSON value.
+type ToolResult struct {
+	Text       string
+	Structured any
+}
+
+// DecodeArguments decodes arguments of a call into v. Unknown arguments are rejected.
+func DecodeArguments(args json.RawMessage, v any) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(args))
+	d.DisallowUnknownFields()
+
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArguments, err)
+	}
+
+	return nil
+}
+
+// NewServer creates a server of the tools. Instructions tell the model how to use them, can be empty.
+func NewServer(info Implementation, instructions string, tools []Tool) *Server {
+	byName := make(map[string]Tool, len(tools))
+	for _, t := range tools {
+		byName[t.Name] = t
+	}
+
+	return &Server{
+		info:         info,
+		instructions: instructions,
+		tools:        tools,
+		byName:       byName,
+		writeMutex:   &sync.Mutex{},
+		mutex:        &sync.Mutex{},
+		inFlight:     map[string]*call{},
+		wg:           &sync.WaitGroup{},
+	}
+}
+
+// Server answers requests read from a reader. Tool calls run concurrently, the other requests are answered in order.
+type Server struct {
+	info         Implementation
+	instructions string
+	tools        []Tool
+	byName       map[string]Tool
+
+	writeMutex *sync.Mutex
+	w          io.Writer
+
+	mutex *sync.Mutex
+	// inFlight are calls which are not answered yet, keyed by their request IDs
+	inFlight map[string]*call
+	wg       *sync.WaitGroup
+}
+
+type call struct {
+	cancel context.CancelFunc
+	// cancelled is set if the client cancelled the call, it is not answered then
+	cancelled atomic.Bool
+}
+
+// Serve reads messages until r is closed or the context is done. Calls in flight are answered if r is closed
+// and cancelled if the context is done. Serve returns after they are finished.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+
+	go func() {
+		reader := bufio.NewReader(r)
+
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+
+				readErr <- err
+
+				return
+			}
+		}
+	}()
+
+	var err error
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		case err = <-readErr:
+			break loop
+		case line := <-lines:
+			s.handle(ctx, line)
+		}
+	}
+
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) handle(ctx context.Context, line []byte) {
+	if bytes.HasPrefix(bytes.TrimSpace(line), []byte("[")) {
+		s.respondError(nil, CodeInvalidRequest, "batches are not supported")
+		return
+	}
+
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		s.respondError(nil, CodeParseError, "failed to parse message: "+err.Error())
+		return
+	}
+
+	if msg.JSONRPC != jsonRPCVersion {
+		s.respondError(msg.ID, CodeInvalidRequest, "jsonrpc must be "+jsonRPCVersion)
+		return
+	}
+
+	// responses of the client are not expected, the server sends no requests
+	if msg.Method == "" {
+		return
+	}
+
+	if msg.isNotification() {
+		s.notified(msg)
+		return
+	}
+
+	switch msg.Method {
+	case methodInitialize:
+		s.initialize(msg)
+	case methodPing:
+		s.respond(msg.ID, struct{}{})
+	case methodToolsList:
+		s.listTools(msg)
+	case methodToolsCall:
+		s.callTool(ctx, msg)
+	default:
+		s.respondError(msg.ID, CodeMethodNotFound, "method not found: "+msg.Method)
+	}
+}
+
+func (s *Server) notified(msg message) {
+	switch msg.Method {
+	case methodInitialized:
+		slog.Debug("MCP client is initialized")
+	case methodCancelled:
+		var params cancelledParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			slog.Debug("Invalid cancellation", logging.Err(err))
+			return
+		}
+
+		s.mutex.Lock()
+		c, ok := s.inFlight[idKey(params.RequestID)]
+		s.mutex.Unlock()
+
+		// a call may finish before its cancellation arrives
+		if ok {
+			slog.Debug("MCP call is cancelled", slog.String("request_id", string(params.RequestID)),
+				slog.String("reason", params.Reason))
+			c.cancelled.Store(true)
+			c.cancel()
+		}
+	default:
+		slog.Debug("Unknown MCP notification is ignored", slog.String("method", msg.Method))
+	}
+}
+
+func (s *Server) initialize(msg message) {
+	var params initializeParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		s.respondError(msg.ID, CodeInvalidParams, "invalid initialize params: "+err.Error())
+		return
+	}
+
+	version := ProtocolVersion
+	if slices.Contains(supportedVersions, params.ProtocolVersion) {
+		version = params.ProtocolVersion
+	}
+
+	slog.Debug("MCP client connected", slog.String("client", params.ClientInfo.Name),
+		slog.String("client_version", params.ClientInfo.Version), slog.String("protocol_version", version))
+
+	s.respond(msg.ID, initializeResult{
+		ProtocolVersion: version,
+		ServerInfo:      s.info,
+		Instructions:    s.instructions,
+	})
+}
+
+func (s *Server) listTools(msg message) {
+	res := toolsListResult{Tools: make([]toolDescription, len(s.tools))}
+
+	for i, t := range s.tools {
+		res.Tools[i] = toolDescription{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: t.InputSchema,
+		}
+	}
+
+	s.respond(msg.ID, res)
+}
+
+// callTool runs the tool in the background, so the calls can be cancelled while they run
+func (s *Server) callTool(ctx context.Context, msg message) {
+	var params toolsCallParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		s.respondError(msg.ID, CodeInvalidParams, "invalid call params: "+err.Error())
+		return
+	}
+
+	tool, ok := s.byName[params.Name]
+	if !ok {
+		s.respondError(msg.ID, CodeInvalidParams, "unknown tool: "+params.Name)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c := &call{cancel: cancel}
+	key := idKey(msg.ID)
+
+	s.mutex.Lock()
+	s.inFlight[key] = c
+	s.mutex.Unlock()
+
+	var reporter progress.Reporter = progress.Discard
+	if len(params.Meta.ProgressToken) > 0 {
+		reporter = newNotifier(s, params.Meta.ProgressToken)
+	}
+
+	s.wg.Go(func() {
+		defer func() {
+			cancel()
+
+			s.mutex.Lock()
+			delete(s.inFlight, key)
+			s.mutex.Unlock()
+		}()
+
+		res, err := tool.Run(ctx, params.Arguments, reporter)
+
+		switch {
+		case c.cancelled.Load():
+			slog.Debug("Cancelled MCP call is not answered", slog.String("tool", tool.Name))
+		case errors.Is(err, ErrInvalidArguments):
+			s.respondError(msg.ID, CodeInvalidParams, err.Error())
+		case err != nil:
+			slog.Debug("MCP tool failed", slog.String("tool", tool.Name), logging.Err(err))
+			s.respond(msg.ID, toolsCallResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true})
+		default:
+			s.respond(msg.ID, toolsCallResult{
+				Content:           []content{{Type: "text", Text: res.Text}},
+				StructuredContent: res.Structured,
+			})
+		}
+	})
+}
+
+// idKey makes IDs of a request and of its cancellation equal regardless of spaces
+func idKey(id json.RawMessage) string {
+	b := &bytes.Buffer{}
+	if err := json.Compact(b, id); err != nil {
+		return string(id)
+	}
+
+	return b.String()
+}
+
+func (s *Server) respond(id json.RawMessage, result any) {
+	s.write(message{JSONRPC: jsonRPCVersion, ID: id, Result: result})
+}
+
+// respondError answers with an error, a null ID is used if the ID of the request is unknown
+func (s *Server) respondError(id json.RawMessage, code int, msg string) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+
+	s.write(message{JSONRPC: jsonRPCVersion, ID: id, Error: &Error{Code: code, Message: msg}})
+}
+
+func (s *Server) notify(method string, params any) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		slog.Warn("Failed to encode MCP notification", slog.String("method", method), logging.Err(err))
+		return
+	}
+
+	s.write(message{JSONRPC: jsonRPCVersion, Method: method, Params: data})
+}
+
+// write sends a message as a single line, messages of concurrent calls are not interleaved
+func (s *Server) write(msg message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Warn("Failed to encode MCP message", logging.Err(err))
+		return
+	}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if _, err = s.w.Write(append(data, '\n')); err != nil {
+		slog.Warn("Failed to write MCP message", logging.Err(err))
+	}
+}
@@ -0,0 +1,300 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaroslav-koval/hange/pkg/progress"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	blocked := make(chan struct{})
+
+	tools := []Tool{
+		{
+			Name:        "echo",
+			Description: "Echoes the text",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}}}`),
+			Run: func(_ context.Context, args json.RawMessage, reporter progress.Reporter) (ToolResult, error) {
+				var a struct {
+					Text string `json:"text"`
+				}
+				if err := DecodeArguments(args, &a); err != nil {
+					return ToolResult{}, err
+				}
+
+				reporter.Expect(progress.StageUpload, 2)
+				reporter.Done(progress.StageUpload, 1, 10)
+				reporter.Done(progress.StageUpload, 1, 10)
+
+				return ToolResult{Text: a.Text, Structured: map[string]string{"text": a.Text}}, nil
+			},
+		},
+		{
+			Name:        "fail",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+			Run: func(context.Context, json.RawMessage, progress.Reporter) (ToolResult, error) {
+				return ToolResult{}, errors.New("no staged changes")
+			},
+		},
+		{
+			Name:        "block",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+			Run: func(ctx context.Context, _ json.RawMessage, _ progress.Reporter) (ToolResult, error) {
+				<-ctx.Done()
+				close(blocked)
+
+				return ToolResult{}, ctx.Err()
+			},
+		},
+	}
+
+	c := startTestServer(t, NewServer(Implementation{Name: "hange", Version: "1.0.0"}, "", tools))
+
+	t.Run("initialize", func(t *testing.T) {
+		c.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26",` +
+			`"capabilities":{},"clientInfo":{"name":"test","version":"0.1"}}}`)
+
+		res := c.receive()
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26",`+
+			`"capabilities":{"tools":{"listChanged":false}},"serverInfo":{"name":"hange","version":"1.0.0"}}}`, res)
+
+		c.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		c.send(`{"jsonrpc":"2.0","id":"p","method":"ping"}`)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":"p","result":{}}`, c.receive(), "notifications are not answered")
+	})
+
+	t.Run("list tools", func(t *testing.T) {
+		c.send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+
+		var res struct {
+			Result toolsListResult `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(c.receive()), &res))
+		require.Len(t, res.Result.Tools, 3)
+		assert.Equal(t, "echo", res.Result.Tools[0].Name)
+		assert.JSONEq(t, string(tools[0].InputSchema), string(res.Result.Tools[0].InputSchema))
+	})
+
+	t.Run("call with progress", func(t *testing.T) {
+		c.send(`{"jsonrpc":"2.0","id":3,"method":"tools/call",` +
+			`"params":{"name":"echo","arguments":{"text":"hi"},"_meta":{"progressToken":"t1"}}}`)
+
+		assert.JSONEq(t, `{"jsonrpc":"2.0","method":"notifications/progress",`+
+			`"params":{"progressToken":"t1","progress":1,"total":2,"message":"upload 1/2 files"}}`, c.receive())
+		assert.JSONEq(t, `{"jsonrpc":"2.0","method":"notifications/progress",`+
+			`"params":{"progressToken":"t1","progress":2,"total":2,"message":"upload 2/2 files"}}`, c.receive())
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":3,"result":{"content":[{"type":"text","text":"hi"}],`+
+			`"structuredContent":{"text":"hi"},"isError":false}}`, c.receive())
+	})
+
+	t.Run("failed call", func(t *testing.T) {
+		c.send(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"fail"}}`)
+
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":4,"result":{"content":[{"type":"text","text":"no staged changes"}],`+
+			`"isError":true}}`, c.receive())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		c.send(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"echo","arguments":{"txt":"hi"}}}`)
+
+		assert.Contains(t, c.receive(), `"code":-32602`)
+	})
+
+	t.Run("unknown tool and method", func(t *testing.T) {
+		c.send(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"rm"}}`)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":6,"error":{"code":-32602,"message":"unknown tool: rm"}}`, c.receive())
+
+		c.send(`{"jsonrpc":"2.0","id":7,"method":"resources/list"}`)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"error":{"code":-32601,"message":"method not found: resources/list"}}`,
+			c.receive())
+	})
+
+	t.Run("malformed messages", func(t *testing.T) {
+		c.send(`{"jsonrpc":`)
+		assert.Contains(t, c.receive(), `"id":null,"error":{"code":-32700`)
+
+		c.send(`[{"jsonrpc":"2.0","id":8,"method":"ping"}]`)
+		assert.Contains(t, c.receive(), `"code":-32600`)
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		c.send(`{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"block"}}`)
+		c.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":9,"reason":"user"}}`)
+
+		select {
+		case <-blocked:
+		case <-time.After(time.Second):
+			t.Fatal("the call is not cancelled")
+		}
+
+		// the cancelled call is not answered, the next response is of the ping
+		c.send(`{"jsonrpc":"2.0","id":10,"method":"ping"}`)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":10,"result":{}}`, c.receive())
+	})
+}
+
+func TestServer_Serve(t *testing.T) {
+	t.Parallel()
+
+	t.Run("closed input answers calls", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+
+		s := NewServer(Implementation{Name: "hange"}, "", []Tool{{
+			Name: "slow",
+			Run: func(context.Context, json.RawMessage, progress.Reporter) (ToolResult, error) {
+				<-release
+				return ToolResult{Text: "done"}, nil
+			},
+		}})
+
+		out := &syncBuffer{}
+		done := make(chan error, 1)
+
+		go func() {
+			done <- s.Serve(context.Background(),
+				strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`), out)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("Serve returns before the call is answered")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-done)
+		assert.Contains(t, out.String(), `"text":"done"`)
+	})
+
+	t.Run("done context cancels calls", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+
+		s := NewServer(Implementation{Name: "hange"}, "", []Tool{{
+			Name: "block",
+			Run: func(ctx context.Context, _ json.RawMessage, _ progress.Reporter) (ToolResult, error) {
+				close(started)
+				<-ctx.Done()
+
+				return ToolResult{}, ctx.Err()
+			},
+		}})
+
+		inR, inW := io.Pipe()
+		t.Cleanup(func() { _ = inW.Close() })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		go func() {
+			done <- s.Serve(ctx, inR, io.Discard)
+		}()
+
+		_, err := inW.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block"}}` + "\n"))
+		require.NoError(t, err)
+
+		<-started
+		cancel()
+
+		select {
+		case err = <-done:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("Serve doesn't return when the context is done")
+		}
+	})
+}
+
+// syncBuffer is written by concurrent calls
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.String()
+}
+
+// testClient talks to a server over in-memory pipes
+type testClient struct {
+	t       *testing.T
+	in      *io.PipeWriter
+	out     *bufio.Scanner
+	timeout time.Duration
+}
+
+func startTestServer(t *testing.T, s *Server) *testClient {
+	t.Helper()
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.Serve(ctx, inR, outW)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		_ = inW.Close()
+		_ = outR.Close()
+		<-done
+	})
+
+	return &testClient{t: t, in: inW, out: bufio.NewScanner(outR), timeout: time.Second}
+}
+
+func (c *testClient) send(msg string) {
+	c.t.Helper()
+
+	_, err := c.in.Write([]byte(msg + "\n"))
+	require.NoError(c.t, err)
+}
+
+func (c *testClient) receive() string {
+	c.t.Helper()
+
+	line := make(chan string, 1)
+
+	go func() {
+		if c.out.Scan() {
+			line <- c.out.Text()
+		}
+	}()
+
+	select {
+	case l := <-line:
+		return l
+	case <-time.After(c.timeout):
+		c.t.Fatal("no message from the server")
+		return ""
+	}
+}
@@ -0,0 +1,221 @@
+// Package plugin discovers "hange-<name>" executables on PATH and runs them with the resolved config of hange.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Prefix is a prefix of names of plugin executables, "hange-<name>" is run as "hange <name>".
+const Prefix = "hange-"
+
+// HandshakeVersion is a version of the handshake. Fields are only added within a version.
+const HandshakeVersion = 1
+
+// TokenFD is a file descriptor of the plugin the token is read from.
+const TokenFD = 3
+
+// TokenSupported reports whether the token can be passed. Windows has no inherited descriptors beyond stdio,
+// so plugins run there without the token.
+const TokenSupported = runtime.GOOS != "windows"
+
+// Plugin is an executable found on PATH.
+type Plugin struct {
+	Name string
+	Path string
+	// Shadowed are paths of executables with the same name later on PATH, they are never run.
+	Shadowed []string
+}
+
+// Handshake is the resolved config of hange written to the plugin as the first line of its stdin.
+type Handshake struct {
+	Version      int     `json:"version"`
+	HangeVersion string  `json:"hange_version"`
+	Plugin       string  `json:"plugin"`
+	WorkDir      string  `json:"work_dir"`
+	RepoRoot     string  `json:"repo_root,omitempty"`
+	ConfigPath   string  `json:"config_path"`
+	Profile      Profile `json:"profile"`
+	// TokenFD is set if the token is passed, it is read once from the descriptor until EOF. It is 0 and omitted
+	// if there is no token or on Windows.
+	TokenFD int  `json:"token_fd,omitempty"`
+	DryRun  bool `json:"dry_run"`
+	Verbose bool `json:"verbose"`
+}
+
+// Profile is the profile the plugin runs with. Models have the defaults applied.
+type Profile struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	BaseURL  string `json:"base_url,omitempty"`
+	Models   Models `json:"models"`
+}
+
+type Models struct {
+	Commit  string `json:"commit"`
+	Explain string `json:"explain"`
+}
+
+// Stdio is the standard streams of the plugin. In is copied after the handshake.
+type Stdio struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// Discover finds plugins in the directories of pathList, a value of PATH. The first executable of a name wins,
+// as the shell would run it. Plugins are sorted by name.
+func Discover(pathList string) []Plugin {
+	var plugins []Plugin
+
+	found := make(map[string]int)
+
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// PATH often has directories which don't exist
+			continue
+		}
+
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok {
+				continue
+			}
+
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			if i, ok := found[name]; ok {
+				plugins[i].Shadowed = append(plugins[i].Shadowed, path)
+				continue
+			}
+
+			found[name] = len(plugins)
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	slices.SortFunc(plugins, func(a, b Plugin) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return plugins
+}
+
+func pluginName(fileName string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		fileName = strings.TrimSuffix(strings.ToLower(fileName), ".exe")
+	}
+
+	name, ok := strings.CutPrefix(fileName, Prefix)
+
+	return name, ok && name != ""
+}
+
+func isExecutable(path string) bool {
+	// symlinks are followed, a link to a directory is not a plugin
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// Run runs the plugin with the arguments. The handshake is written as one JSON line to its stdin, followed by
+// stdio.In. A non-empty token is written to a pipe of TokenFD and never to the environment or arguments.
+// The token is dropped with a warning if it is not TokenSupported.
+// An error of a plugin which exited with a non-zero code is *exec.ExitError.
+func Run(ctx context.Context, p Plugin, args []string, hs Handshake, token string, stdio Stdio) error {
+	cmd := exec.CommandContext(ctx, p.Path, args...)
+	cmd.Stdout = stdio.Out
+	cmd.Stderr = stdio.Err
+
+	if token != "" && !TokenSupported {
+		slog.Warn("Plugin runs without a token, passing it is not supported on "+runtime.GOOS,
+			slog.String("plugin", p.Name))
+
+		token = ""
+	}
+
+	if token != "" {
+		r, err := tokenPipe(token)
+		if err != nil {
+			return err
+		}
+
+		defer r.Close()
+
+		// ExtraFiles[0] is the descriptor 3 of the plugin
+		cmd.ExtraFiles = []*os.File{r}
+		hs.TokenFD = TokenFD
+	}
+
+	line, err := json.Marshal(hs)
+	if err != nil {
+		return err
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		// a plugin may exit without reading stdin, write errors are not errors of the plugin
+		if _, err := stdin.Write(append(line, '\n')); err != nil {
+			return
+		}
+
+		if stdio.In != nil {
+			_, _ = io.Copy(stdin, stdio.In)
+		}
+
+		_ = stdin.Close()
+	}()
+
+	// Wait closes stdin, so the copy above fails on the next write once the plugin exits
+	return cmd.Wait()
+}
+
+// tokenPipe returns the read end of a pipe holding the token. The write end is closed, so the token
+// is read once until EOF. A token is far smaller than a pipe buffer, the write doesn't block.
+func tokenPipe(token string) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = io.WriteString(w, token); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+
+		return nil, err
+	}
+
+	if err = w.Close(); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,135 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("executables are shell scripts")
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+
+	writeScript(t, first, "hange-lint", "")
+	writeScript(t, second, "hange-lint", "")
+	writeScript(t, second, "hange-deploy", "")
+	writeScript(t, second, "hange-", "")
+	writeScript(t, second, "kubectl-hange", "")
+	require.NoError(t, os.WriteFile(filepath.Join(second, "hange-notes"), nil, 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(second, "hange-dir"), 0o755))
+
+	missing := filepath.Join(first, "missing")
+	plugins := Discover(strings.Join([]string{first, "", missing, second}, string(os.PathListSeparator)))
+
+	assert.Equal(t, []Plugin{
+		{Name: "deploy", Path: filepath.Join(second, "hange-deploy")},
+		{
+			Name:     "lint",
+			Path:     filepath.Join(first, "hange-lint"),
+			Shadowed: []string{filepath.Join(second, "hange-lint")},
+		},
+	}, plugins)
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("executables are shell scripts")
+	}
+
+	dir := t.TempDir()
+	hs := Handshake{
+		Version:    HandshakeVersion,
+		Plugin:     "echo",
+		WorkDir:    dir,
+		ConfigPath: filepath.Join(dir, "config"),
+		Profile:    Profile{Name: "work", Provider: "openai", Models: Models{Commit: "m1", Explain: "m2"}},
+	}
+
+	// the handshake, the arguments, the token if its descriptor is open and the rest of stdin, which fails it
+	p := Plugin{Name: "echo", Path: writeScript(t, dir, "hange-echo", `read -r hs
+echo "$hs"
+echo "$@"
+if [ -e /dev/fd/3 ]; then cat <&3; echo; else echo "no token"; fi
+rest=$(cat)
+echo "$rest"
+if [ "$rest" = fail ]; then exit 4; fi`)}
+
+	run := func(t *testing.T, token, input string) (Handshake, []string, error) {
+		t.Helper()
+
+		out := &bytes.Buffer{}
+		err := Run(context.Background(), p, []string{"-v", "a b"}, hs, token, Stdio{
+			In:  strings.NewReader(input + "\n"),
+			Out: out,
+			Err: out,
+		})
+
+		lines := strings.Split(out.String(), "\n")
+		require.Len(t, lines, 5, out.String())
+
+		var got Handshake
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+
+		return got, lines[1:4], err
+	}
+
+	t.Run("token is passed by a pipe", func(t *testing.T) {
+		t.Parallel()
+
+		got, lines, err := run(t, "sk-test", "input")
+		require.NoError(t, err)
+
+		want := hs
+		want.TokenFD = TokenFD
+
+		assert.Equal(t, want, got)
+		assert.Equal(t, []string{"-v a b", "sk-test", "input"}, lines)
+	})
+
+	t.Run("no token, the exit code is kept", func(t *testing.T) {
+		t.Parallel()
+
+		got, lines, err := run(t, "", "fail")
+
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, 4, exitErr.ExitCode())
+
+		assert.Equal(t, hs, got)
+		assert.Equal(t, []string{"-v a b", "no token", "fail"}, lines)
+	})
+}
+
+func TestHandshake_omitsTokenFD(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(Handshake{Version: HandshakeVersion})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "token_fd")
+}
+
+func writeScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+
+	return path
+}
@@ -0,0 +1,289 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TTYInterval is how often the live bar is redrawn
+	TTYInterval = 200 * time.Millisecond
+	// PlainInterval is how often a line is printed when the output is not a terminal
+	PlainInterval = 5 * time.Second
+
+	barWidth = 20
+)
+
+// NewPrinter creates a Reporter which draws a live bar if tty is set, otherwise it prints plain lines
+// every PlainInterval. A line with the totals and the duration of a stage is printed when it finishes.
+// Close must be called to stop printing.
+func NewPrinter(w io.Writer, tty bool) *Printer {
+	interval := PlainInterval
+	if tty {
+		interval = TTYInterval
+	}
+
+	p := newPrinter(w, tty, time.Now)
+	p.ticker = time.NewTicker(interval)
+
+	go p.loop()
+
+	return p
+}
+
+func newPrinter(w io.Writer, tty bool, now func() time.Time) *Printer {
+	return &Printer{
+		w:      w,
+		tty:    tty,
+		now:    now,
+		mutex:  &sync.Mutex{},
+		stages: map[Stage]*stats{},
+		stop:   make(chan struct{}),
+	}
+}
+
+// Printer renders progress of stages to a writer, usually stderr.
+type Printer struct {
+	w      io.Writer
+	tty    bool
+	now    func() time.Time
+	ticker *time.Ticker
+	stop   chan struct{}
+
+	mutex *sync.Mutex
+	// order keeps stages in order of their start
+	order  []Stage
+	stages map[Stage]*stats
+	// drawn is set while the live bar is on the screen
+	drawn  bool
+	closed bool
+}
+
+type stats struct {
+	total    int
+	done     int
+	bytes    int64
+	started  time.Time
+	finished time.Time
+}
+
+func (s *stats) running() bool {
+	return s.finished.IsZero()
+}
+
+func (p *Printer) Expect(stage Stage, n int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stage(stage).total += n
+}
+
+func (p *Printer) Done(stage Stage, n int, bytes int64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	s := p.stage(stage)
+	s.done += n
+	s.bytes += bytes
+}
+
+func (p *Printer) Finish(stage Stage) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	s := p.stage(stage)
+	if !s.running() || p.closed {
+		return
+	}
+
+	s.finished = p.now()
+
+	p.clear()
+	p.print(summary(stage, s))
+	p.draw()
+}
+
+// Close stops printing and removes the live bar. Stages which are not finished are not summarized.
+func (p *Printer) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	p.closed = true
+
+	if p.ticker != nil {
+		p.ticker.Stop()
+		close(p.stop)
+	}
+
+	p.clear()
+}
+
+func (p *Printer) loop() {
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-p.ticker.C:
+			p.tick()
+		}
+	}
+}
+
+// tick redraws the live bar or prints a line of every running stage
+func (p *Printer) tick() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	if p.tty {
+		p.draw()
+		return
+	}
+
+	for _, stage := range p.order {
+		if s := p.stages[stage]; s.running() {
+			p.print(p.line(stage, s))
+		}
+	}
+}
+
+// stage returns stats of the stage, the stage is started on the first call
+func (p *Printer) stage(stage Stage) *stats {
+	s, ok := p.stages[stage]
+	if !ok {
+		s = &stats{started: p.now()}
+		p.stages[stage] = s
+		p.order = append(p.order, stage)
+	}
+
+	return s
+}
+
+// draw puts running stages on a single line, the last started one gets a bar. Logs written in between
+// move the bar to the next line instead of being overwritten.
+func (p *Printer) draw() {
+	if !p.tty {
+		return
+	}
+
+	var running []Stage
+
+	for _, stage := range p.order {
+		if p.stages[stage].running() {
+			running = append(running, stage)
+		}
+	}
+
+	if len(running) == 0 {
+		p.clear()
+		return
+	}
+
+	parts := make([]string, len(running))
+	for i, stage := range running {
+		parts[i] = p.line(stage, p.stages[stage])
+	}
+
+	last := p.stages[running[len(running)-1]]
+	parts[len(parts)-1] = bar(last) + " " + parts[len(parts)-1]
+
+	_, _ = fmt.Fprint(p.w, "\r\033[K"+strings.Join(parts, " | "))
+	p.drawn = true
+}
+
+func (p *Printer) clear() {
+	if p.drawn {
+		_, _ = fmt.Fprint(p.w, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+func (p *Printer) print(line string) {
+	_, _ = fmt.Fprintln(p.w, line)
+}
+
+// line describes a running stage, e.g. "upload 42/100 files, 3.1 MiB, 12s, ETA 17s"
+func (p *Printer) line(stage Stage, s *stats) string {
+	elapsed := p.now().Sub(s.started)
+
+	line := fmt.Sprintf("%s %d", stage, s.done)
+	if s.total > 0 {
+		line += fmt.Sprintf("/%d", s.total)
+	}
+
+	line += " files"
+
+	if s.bytes > 0 {
+		line += ", " + FormatBytes(s.bytes)
+	}
+
+	line += ", " + formatDuration(elapsed)
+
+	if eta, ok := estimate(s, elapsed); ok {
+		line += ", ETA " + formatDuration(eta)
+	}
+
+	return line
+}
+
+func summary(stage Stage, s *stats) string {
+	line := fmt.Sprintf("%s: %d files", stage, s.done)
+	if s.bytes > 0 {
+		line += ", " + FormatBytes(s.bytes)
+	}
+
+	return line + " in " + formatDuration(s.finished.Sub(s.started))
+}
+
+// estimate assumes the remaining items take as long as the done ones on average
+func estimate(s *stats, elapsed time.Duration) (time.Duration, bool) {
+	if s.done == 0 || s.total <= s.done {
+		return 0, false
+	}
+
+	return elapsed / time.Duration(s.done) * time.Duration(s.total-s.done), true
+}
+
+func bar(s *stats) string {
+	filled := 0
+	if s.total > 0 {
+		filled = min(s.done*barWidth/s.total, barWidth)
+	}
+
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled) + "]"
+}
+
+// FormatBytes formats a size with a binary unit, e.g. "3.1 MiB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(100 * time.Millisecond).String()
+}
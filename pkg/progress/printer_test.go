@@ -0,0 +1,102 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClock is moved forward by tests
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestPrinter_Plain(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	clock := &testClock{now: time.Unix(0, 0)}
+	p := newPrinter(out, false, clock.Now)
+
+	p.Expect(StageUpload, 4)
+	p.Done(StageUpload, 1, 2048)
+
+	clock.now = clock.now.Add(2 * time.Second)
+	p.tick()
+
+	assert.Equal(t, "upload 1/4 files, 2.0 KiB, 2s, ETA 6s\n", out.String())
+
+	out.Reset()
+	p.Done(StageUpload, 3, 1<<20)
+
+	clock.now = clock.now.Add(time.Second)
+	p.Finish(StageUpload)
+	p.tick()
+
+	assert.Equal(t, "upload: 4 files, 1.0 MiB in 3s\n", out.String(), "finished stages are not printed by ticks")
+
+	out.Reset()
+	p.Finish(StageUpload)
+	p.Close()
+
+	assert.Empty(t, out.String(), "a stage is summarized once")
+}
+
+func TestPrinter_TTY(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	clock := &testClock{now: time.Unix(0, 0)}
+	p := newPrinter(out, true, clock.Now)
+
+	p.Expect(StageRead, 10)
+	p.Done(StageRead, 10, 100)
+	p.Expect(StageUpload, 10)
+	p.Done(StageUpload, 5, 50)
+
+	clock.now = clock.now.Add(time.Second)
+	p.tick()
+
+	assert.Equal(t,
+		"\r\033[Kread 10/10 files, 100 B, 1s | [==========          ] upload 5/10 files, 50 B, 1s, ETA 1s",
+		out.String())
+
+	out.Reset()
+	p.Finish(StageRead)
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, []string{
+		"\r\033[Kread: 10 files, 100 B in 1s",
+		"\r\033[K[==========          ] upload 5/10 files, 50 B, 1s, ETA 1s",
+	}, lines, "the summary replaces the bar, which is drawn again below")
+
+	out.Reset()
+	p.Close()
+	p.tick()
+
+	assert.Equal(t, "\r\033[K", out.String(), "the bar is removed on close")
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int64]string{
+		0:             "0 B",
+		1023:          "1023 B",
+		1024:          "1.0 KiB",
+		1536:          "1.5 KiB",
+		3 << 20:       "3.0 MiB",
+		5<<30 + 1<<29: "5.5 GiB",
+	}
+
+	for n, expected := range tests {
+		assert.Equal(t, expected, FormatBytes(n))
+	}
+}
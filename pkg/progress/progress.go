@@ -0,0 +1,41 @@
+// Package progress reports counts, bytes and timings of long stages of a run, e.g. uploads of files.
+package progress
+
+// Stage is a step of a run whose progress is reported.
+type Stage string
+
+const (
+	StageRead   Stage = "read"
+	StageUpload Stage = "upload"
+	StageIndex  Stage = "index"
+)
+
+// Reporter receives progress of stages. A stage starts with the first call of any method for it.
+// Implementations are safe for concurrent use.
+type Reporter interface {
+	// Expect adds n items to the total of the stage. The total may grow while the stage runs,
+	// e.g. when files are read and uploaded at the same time.
+	Expect(stage Stage, n int)
+	// Done reports n more items of the stage done, bytes is their size.
+	Done(stage Stage, n int, bytes int64)
+	// Finish marks the end of the stage.
+	Finish(stage Stage)
+}
+
+// Discard is a Reporter that does nothing.
+var Discard Reporter = discard{}
+
+type discard struct{}
+
+func (discard) Expect(Stage, int)      {}
+func (discard) Done(Stage, int, int64) {}
+func (discard) Finish(Stage)           {}
+
+// OrDiscard returns r, or Discard if r is nil.
+func OrDiscard(r Reporter) Reporter {
+	if r == nil {
+		return Discard
+	}
+
+	return r
+}